@@ -0,0 +1,55 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/backend/ethereum/bindings"
+)
+
+// AddLegacyABI registers the ABI of a previous version of the adjudicator
+// contract. It is used to decode the calldata of historical register and
+// progress transactions that were sent before a contract upgrade changed the
+// signatures of these functions. The method is selected by the transaction's
+// function selector: the current ABI is tried first, then the legacy ABIs in
+// the order in which they were added.
+//
+// Should only be called before the Adjudicator is used.
+func (a *Adjudicator) AddLegacyABI(abiJSON string) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return errors.Wrap(err, "parsing ABI")
+	}
+	a.legacyABIs = append(a.legacyABIs, parsed)
+	return nil
+}
+
+// callDataMethod returns the adjudicator method with the given name whose
+// function selector prefixes data.
+func (a *Adjudicator) callDataMethod(name string, data []byte) (abi.Method, error) {
+	abis := append([]abi.ABI{bindings.ABI.Adjudicator}, a.legacyABIs...)
+	for _, contract := range abis {
+		method, ok := contract.Methods[name]
+		if ok && bytes.HasPrefix(data, method.ID) {
+			return method, nil
+		}
+	}
+	return abi.Method{}, errors.Errorf("no known ABI of method %s matches the call data", name)
+}
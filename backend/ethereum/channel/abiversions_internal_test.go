@@ -0,0 +1,52 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/backend/ethereum/bindings"
+)
+
+const legacyRegisterABI = `[{"type":"function","name":"register","inputs":[{"name":"channelID","type":"bytes32"}],"outputs":[]}]`
+
+func TestAdjudicator_callDataMethod(t *testing.T) {
+	var a Adjudicator
+	current := bindings.ABI.Adjudicator.Methods["register"]
+	callData := func(selector []byte) []byte {
+		return append(append([]byte{}, selector...), make([]byte, 32)...)
+	}
+
+	m, err := a.callDataMethod("register", callData(current.ID))
+	require.NoError(t, err)
+	assert.Equal(t, current.ID, m.ID)
+
+	require.Error(t, a.AddLegacyABI("invalid"))
+	require.NoError(t, a.AddLegacyABI(legacyRegisterABI))
+	legacy := a.legacyABIs[0].Methods["register"]
+	require.NotEqual(t, current.ID, legacy.ID)
+
+	m, err = a.callDataMethod("register", callData(legacy.ID))
+	require.NoError(t, err)
+	assert.Equal(t, legacy.ID, m.ID)
+
+	_, err = a.callDataMethod("progress", callData(legacy.ID))
+	assert.Error(t, err, "selector of another method")
+	_, err = a.callDataMethod("register", []byte{1, 2})
+	assert.Error(t, err, "unknown selector")
+}
@@ -19,6 +19,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -33,8 +34,14 @@ import (
 	psync "perun.network/go-perun/pkg/sync"
 )
 
-// compile time check that we implement the perun adjudicator interface.
-var _ channel.Adjudicator = (*Adjudicator)(nil)
+// compile time checks that we implement the perun adjudicator interfaces.
+var (
+	_ channel.Adjudicator            = (*Adjudicator)(nil)
+	_ client.ReceiverWithdrawer      = (*Adjudicator)(nil)
+	_ client.Concluder               = (*Adjudicator)(nil)
+	_ client.ConfirmationDepthSetter = (*Adjudicator)(nil)
+	_ client.ResumableAdjudicator    = (*Adjudicator)(nil)
+)
 
 // The Adjudicator struct implements the channel.Adjudicator interface
 // It provides all functionality to close a channel.
@@ -50,6 +57,16 @@ type Adjudicator struct {
 	mu psync.Mutex
 	// txSender is sending the TX.
 	txSender accounts.Account
+	// limit bounds the number of concurrently processed assets.
+	limit limiter
+	// txGuard is consulted before sending transactions, guarded by mu.
+	txGuard TxGuard
+	// legacyABIs are previous contract ABIs used to decode old calldata.
+	legacyABIs []abi.ABI
+	// replacement configures the replacement of timed-out transactions.
+	replacement txReplacement
+	// gasLimits are the gas limits of the sent transactions.
+	gasLimits GasLimits
 }
 
 // NewAdjudicator creates a new ethereum adjudicator. The receiver is the
@@ -87,16 +104,21 @@ func (a *Adjudicator) Progress(ctx context.Context, req channel.ProgressReq) err
 }
 
 func (a *Adjudicator) callRegister(ctx context.Context, req channel.AdjudicatorReq, subChannels []channel.SignedState) error {
-	return a.call(ctx, req,
-		func(opts *bind.TransactOpts, params adjudicator.ChannelParams, state adjudicator.ChannelState, sigs [][]byte) (*types.Transaction, error) {
-			ch := adjudicator.AdjudicatorSignedState{
-				Params: params,
-				State:  state,
-				Sigs:   sigs,
-			}
-			sub := toEthSignedStates(subChannels)
-			return a.contract.Register(opts, ch, sub)
-		}, Register)
+	return a.call(ctx, req, a.registerFunc(subChannels), Register)
+}
+
+// registerFunc returns the adjudicator function that registers a channel
+// together with the given sub-channels.
+func (a *Adjudicator) registerFunc(subChannels []channel.SignedState) adjFunc {
+	return func(opts *bind.TransactOpts, params adjudicator.ChannelParams, state adjudicator.ChannelState, sigs [][]byte) (*types.Transaction, error) {
+		ch := adjudicator.AdjudicatorSignedState{
+			Params: params,
+			State:  state,
+			Sigs:   sigs,
+		}
+		sub := toEthSignedStates(subChannels)
+		return a.contract.Register(opts, ch, sub)
+	}
 }
 
 func toEthSignedStates(subChannels []channel.SignedState) (ethSubChannels []adjudicator.AdjudicatorSignedState) {
@@ -111,8 +133,11 @@ func toEthSignedStates(subChannels []channel.SignedState) (ethSubChannels []adju
 	return
 }
 
-func (a *Adjudicator) callConclude(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error {
-	ethSubStates := toEthSubStates(req.Tx.State, subStates)
+func (a *Adjudicator) callConclude(ctx context.Context, req channel.AdjudicatorReq, resolve channel.StateResolver) error {
+	ethSubStates, err := toEthSubStates(req.Tx.State, resolve)
+	if err != nil {
+		return err
+	}
 
 	conclude := func(
 		opts *bind.TransactOpts,
@@ -141,6 +166,16 @@ type adjFunc = func(
 // `fn` should be a method of `a.contract`, like `a.contract.Register`.
 // `txType` should be one of the valid transaction types defined in the client package.
 func (a *Adjudicator) call(ctx context.Context, req channel.AdjudicatorReq, fn adjFunc, txType OnChainTxType) error {
+	tx, err := a.send(ctx, req, fn, txType)
+	if err != nil {
+		return err
+	}
+	return a.confirm(ctx, tx, txType)
+}
+
+// send sends the transaction calling `fn` with the data from `req` without
+// waiting for it to be mined.
+func (a *Adjudicator) send(ctx context.Context, req channel.AdjudicatorReq, fn adjFunc, txType OnChainTxType) (*types.Transaction, error) {
 	ethParams := ToEthParams(req.Params)
 	ethState := ToEthState(req.Tx.State)
 	tx, err := func() (*types.Transaction, error) {
@@ -149,10 +184,17 @@ func (a *Adjudicator) call(ctx context.Context, req channel.AdjudicatorReq, fn a
 		}
 		defer a.mu.Unlock()
 
-		trans, err := a.NewTransactor(ctx, GasLimit, a.txSender)
+		gasLimit, err := a.gasLimit(ctx, txType, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return fn(opts, ethParams, ethState, req.Tx.Sigs)
+		})
+		if err != nil {
+			return nil, err
+		}
+		trans, err := a.NewTransactor(ctx, gasLimit, a.txSender)
 		if err != nil {
 			return nil, errors.WithMessage(err, "creating transactor")
 		}
+		a.guardTransactor(ctx, trans, txType)
 		tx, err := fn(trans, ethParams, ethState, req.Tx.Sigs)
 		if err != nil {
 			err = cherrors.CheckIsChainNotReachableError(err)
@@ -161,11 +203,17 @@ func (a *Adjudicator) call(ctx context.Context, req channel.AdjudicatorReq, fn a
 		log.Debugf("Sent transaction %v", tx.Hash().Hex())
 		return tx, nil
 	}()
-	if err != nil {
-		return err
-	}
+	return tx, err
+}
 
-	_, err = a.ConfirmTransaction(ctx, tx, a.txSender)
+// confirm waits for the transaction, or one of its replacements if
+// replacement is enabled, to be mined.
+func (a *Adjudicator) confirm(ctx context.Context, tx *types.Transaction, txType OnChainTxType) (err error) {
+	if a.replacement.maxAttempts > 0 {
+		tx, err = a.confirmReplacing(ctx, tx, txType)
+	} else {
+		_, err = a.ConfirmTransaction(ctx, tx, a.txSender)
+	}
 	if errors.Is(err, errTxTimedOut) {
 		err = client.NewTxTimedoutError(txType.String(), tx.Hash().Hex(), err.Error())
 	}
@@ -177,19 +225,35 @@ func (a *Adjudicator) call(ctx context.Context, req channel.AdjudicatorReq, fn a
 // This error can be checked with function IsErrInvalidContractCode.
 func ValidateAdjudicator(ctx context.Context,
 	backend bind.ContractCaller, adjudicatorAddr common.Address) error {
-	return validateContract(ctx, backend, adjudicatorAddr, adjudicator.AdjudicatorBinRuntime)
+	return ValidateAdjudicatorAt(&bind.CallOpts{Context: ctx}, backend, adjudicatorAddr)
+}
+
+// ValidateAdjudicatorAt is like ValidateAdjudicator, but reads the bytecode as
+// specified by opts. The code is read at opts.BlockNumber, or at the latest
+// block if it is nil. If opts.Pending is set, the code is read from the
+// pending state instead, which requires a backend that can read pending code,
+// like any bind.ContractBackend.
+// This allows to pin the validation to a block, e.g., for upgradeable
+// contracts.
+func ValidateAdjudicatorAt(opts *bind.CallOpts,
+	backend bind.ContractCaller, adjudicatorAddr common.Address) error {
+	return validateContract(opts, backend, adjudicatorAddr, adjudicator.AdjudicatorBinRuntime)
 }
 
-// toEthSubStates generates a channel tree in depth-first order.
-func toEthSubStates(state *channel.State, subStates channel.StateMap) (ethSubStates []adjudicator.ChannelState) {
+// toEthSubStates generates a channel tree in depth-first order. The
+// sub-states are fetched with resolve while walking the tree.
+func toEthSubStates(state *channel.State, resolve channel.StateResolver) (ethSubStates []adjudicator.ChannelState, err error) {
 	for _, subAlloc := range state.Locked {
-		subState, ok := subStates[subAlloc.ID]
-		if !ok {
-			log.Panic("sub-state not found")
+		subState, err := resolve(subAlloc.ID)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "resolving sub-state %x", subAlloc.ID)
 		}
 		ethSubStates = append(ethSubStates, ToEthState(subState))
 		if len(subState.Locked) > 0 {
-			_subSubStates := toEthSubStates(subState, subStates)
+			_subSubStates, err := toEthSubStates(subState, resolve)
+			if err != nil {
+				return nil, err
+			}
 			ethSubStates = append(ethSubStates, _subSubStates...)
 		}
 	}
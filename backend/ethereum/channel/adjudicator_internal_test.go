@@ -76,11 +76,33 @@ func Test_toEthSubStates(t *testing.T) {
 
 	for _, tc := range tests {
 		state, subStates, expected := tc.setup()
-		got := toEthSubStates(state, subStates)
+		got, err := toEthSubStates(state, subStates.Resolver())
+		assert.NoError(err, tc.title)
 		assert.Equal(expected, got, tc.title)
 	}
 }
 
+func Test_toEthSubStates_Unresolvable(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	// ch[0]( ch[1]( ch[2] ) ), ch[2] missing
+	ch := genStates(rng, 3)
+	ch[0].AddSubAlloc(*ch[1].ToSubAlloc())
+	ch[1].AddSubAlloc(*ch[2].ToSubAlloc())
+
+	_, err := toEthSubStates(ch[0], toStateMap(ch[1]).Resolver())
+	assert.Error(t, err)
+
+	var resolved []channel.ID
+	resolve := func(id channel.ID) (*channel.State, error) {
+		resolved = append(resolved, id)
+		return toStateMap(ch[1:]...).Resolver()(id)
+	}
+	got, err := toEthSubStates(ch[0], resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, toEthStates(ch[1:]...), got)
+	assert.Equal(t, []channel.ID{ch[1].ID, ch[2].ID}, resolved, "should resolve lazily in depth-first order")
+}
+
 func genStates(rng *rand.Rand, n int) (states []*channel.State) {
 	states = make([]*channel.State, n)
 	for i := range states {
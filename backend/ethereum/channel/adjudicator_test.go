@@ -16,9 +16,11 @@ package channel_test
 
 import (
 	"context"
+	"runtime"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -97,6 +99,12 @@ func TestSubscribeRegistered(t *testing.T) {
 	assert.NoError(t, adj.Register(txCtx, req, nil), "Registering state should succeed")
 	event := sub.Next()
 	assert.Equal(t, event, registered.Next(), "Events should be equal")
+	eventLog, ok := ethchannel.EventLog(event)
+	require.True(t, ok, "event should carry its on-chain log")
+	assert.NotZero(t, eventLog.BlockNumber)
+	assert.NotEqual(t, common.Hash{}, eventLog.TxHash)
+	_, ok = ethchannel.EventLog(channel.NewConcludedEvent(params.ID(), &channel.ElapsedTimeout{}, 0))
+	assert.False(t, ok, "foreign event should carry no on-chain log")
 	assert.NoError(t, registered.Close(), "Closing event channel should not error")
 	assert.Nil(t, registered.Next(), "Next on closed channel should produce nil")
 	assert.NoError(t, registered.Err(), "Closing should produce no error")
@@ -109,6 +117,37 @@ func TestSubscribeRegistered(t *testing.T) {
 	assert.NoError(t, registered2.Err(), "Closing should produce no error")
 }
 
+func TestSubscribe_Close(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	params := channeltest.NewRandomParams(rng, channeltest.WithParts(s.Parts...))
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+
+	// Open and close a subscription once so that all lazily started goroutines
+	// of the backend are running.
+	sub, err := s.Adjs[0].Subscribe(ctx, params)
+	require.NoError(t, err)
+	require.NoError(t, sub.Close())
+	goroutines := runtime.NumGoroutine()
+
+	const n = 100
+	subs := make([]channel.AdjudicatorSubscription, n)
+	for i := range subs {
+		subs[i], err = s.Adjs[0].Subscribe(ctx, params)
+		require.NoError(t, err)
+	}
+	for _, sub := range subs {
+		require.NoError(t, sub.Close())
+	}
+	// Close must only return after the goroutines of the subscription returned.
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines, "closed subscriptions leaked goroutines")
+	for _, sub := range subs {
+		assert.Nil(t, sub.Next(), "Next on closed subscription should produce nil")
+		assert.NoError(t, sub.Err(), "Closing should produce no error")
+	}
+}
+
 func TestValidateAdjudicator(t *testing.T) {
 	// Test setup
 	rng := pkgtest.Prng(t)
@@ -136,4 +175,23 @@ func TestValidateAdjudicator(t *testing.T) {
 		t.Logf("adjudicator address is %v", adjudicatorAddr)
 		require.NoError(t, ethchannel.ValidateAdjudicator(ctx, *s.CB, adjudicatorAddr))
 	})
+	t.Run("pinned_block", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+		defer cancel()
+		before, err := s.CB.HeaderByNumber(ctx, nil)
+		require.NoError(t, err)
+		adjudicatorAddr, err := ethchannel.DeployAdjudicator(ctx, *s.CB, s.TxSender.Account)
+		require.NoError(t, err)
+		after, err := s.CB.HeaderByNumber(ctx, nil)
+		require.NoError(t, err)
+
+		validateAt := func(opts bind.CallOpts) error {
+			opts.Context = ctx
+			return ethchannel.ValidateAdjudicatorAt(&opts, *s.CB, adjudicatorAddr)
+		}
+		require.True(t, ethchannel.IsErrInvalidContractCode(validateAt(bind.CallOpts{BlockNumber: before.Number})),
+			"no code before deployment")
+		require.NoError(t, validateAt(bind.CallOpts{BlockNumber: after.Number}))
+		require.NoError(t, validateAt(bind.CallOpts{Pending: true}))
+	})
 }
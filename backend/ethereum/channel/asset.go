@@ -36,6 +36,35 @@ type Asset = wallet.Address
 
 var _ channel.Asset = new(Asset)
 
+// assetBinaryV1 is the version prefix of the binary asset format of
+// MarshalAsset, followed by the address of the AssetHolder.
+const assetBinaryV1 byte = 1
+
+// MarshalAsset returns the standalone binary representation of the asset,
+// which consists of a one-byte format version and the AssetHolder address.
+// It is meant for storing assets outside of the wire protocol, where assets
+// are encoded with Asset.Encode.
+func MarshalAsset(asset *Asset) []byte {
+	return append([]byte{assetBinaryV1}, asset.Bytes()...)
+}
+
+// UnmarshalAsset decodes an asset from the binary representation created by
+// MarshalAsset. It errors on unknown format versions and if data contains
+// more or less bytes than the format requires.
+func UnmarshalAsset(data []byte) (*Asset, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty asset data")
+	}
+	if data[0] != assetBinaryV1 {
+		return nil, errors.Errorf("unknown asset format version %d", data[0])
+	}
+	if l := len(data) - 1; l != common.AddressLength {
+		return nil, errors.Errorf("invalid asset data length %d, expected %d", l, common.AddressLength)
+	}
+	asset := Asset(common.BytesToAddress(data[1:]))
+	return &asset, nil
+}
+
 // ValidateAssetHolderETH checks if the bytecode at the given asset holder ETH
 // address is correct and if the adjudicator address is correctly set in the
 // asset holder contract. The contract code at the adjudicator address is not
@@ -46,7 +75,16 @@ var _ channel.Asset = new(Asset)
 // be checked with function IsErrInvalidContractCode.
 func ValidateAssetHolderETH(ctx context.Context,
 	backend bind.ContractBackend, assetHolderETH, adjudicator common.Address) error {
-	return validateAssetHolder(ctx, backend, assetHolderETH, adjudicator,
+	return ValidateAssetHolderETHAt(&bind.CallOpts{Context: ctx}, backend, assetHolderETH, adjudicator)
+}
+
+// ValidateAssetHolderETHAt is like ValidateAssetHolderETH, but reads the
+// contract bytecode and state as specified by opts, see ValidateAdjudicatorAt.
+// Reading the pending state additionally requires the backend to implement
+// bind.PendingContractCaller.
+func ValidateAssetHolderETHAt(opts *bind.CallOpts,
+	backend bind.ContractBackend, assetHolderETH, adjudicator common.Address) error {
+	return validateAssetHolder(opts, backend, assetHolderETH, adjudicator,
 		assetholdereth.AssetHolderETHBinRuntime)
 }
 
@@ -60,13 +98,22 @@ func ValidateAssetHolderETH(ctx context.Context,
 // be checked with function IsErrInvalidContractCode.
 func ValidateAssetHolderERC20(ctx context.Context,
 	backend bind.ContractBackend, assetHolderERC20, adjudicator, token common.Address) error {
-	return validateAssetHolder(ctx, backend, assetHolderERC20, adjudicator,
+	return ValidateAssetHolderERC20At(&bind.CallOpts{Context: ctx}, backend, assetHolderERC20, adjudicator, token)
+}
+
+// ValidateAssetHolderERC20At is like ValidateAssetHolderERC20, but reads the
+// contract bytecode and state as specified by opts, see ValidateAdjudicatorAt.
+// Reading the pending state additionally requires the backend to implement
+// bind.PendingContractCaller.
+func ValidateAssetHolderERC20At(opts *bind.CallOpts,
+	backend bind.ContractBackend, assetHolderERC20, adjudicator, token common.Address) error {
+	return validateAssetHolder(opts, backend, assetHolderERC20, adjudicator,
 		assetHolderERC20BinRuntimeFor(token))
 }
 
-func validateAssetHolder(ctx context.Context,
+func validateAssetHolder(opts *bind.CallOpts,
 	backend bind.ContractBackend, assetHolderAddr, adjudicatorAddr common.Address, bytecode string) error {
-	if err := validateContract(ctx, backend, assetHolderAddr, bytecode); err != nil {
+	if err := validateContract(opts, backend, assetHolderAddr, bytecode); err != nil {
 		return errors.WithMessage(err, "validating asset holder")
 	}
 
@@ -74,11 +121,7 @@ func validateAssetHolder(ctx context.Context,
 	if err != nil {
 		return errors.Wrap(err, "binding AssetHolder")
 	}
-	opts := bind.CallOpts{
-		Pending: false,
-		Context: ctx,
-	}
-	if addrSetInContract, err := assetHolder.Adjudicator(&opts); err != nil {
+	if addrSetInContract, err := assetHolder.Adjudicator(opts); err != nil {
 		err = cherrors.CheckIsChainNotReachableError(err)
 		return errors.WithMessage(err, "fetching adjudicator address set in asset holder contract")
 	} else if addrSetInContract != adjudicatorAddr {
@@ -88,9 +131,25 @@ func validateAssetHolder(ctx context.Context,
 	return nil
 }
 
-func validateContract(ctx context.Context,
+// pendingCodeReader reads contract code from the pending state. It is
+// implemented by all bind.ContractBackends.
+type pendingCodeReader interface {
+	PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error)
+}
+
+func validateContract(opts *bind.CallOpts,
 	backend bind.ContractCaller, contract common.Address, bytecode string) error {
-	code, err := backend.CodeAt(ctx, contract, nil)
+	var code []byte
+	var err error
+	if opts.Pending {
+		pendingBackend, ok := backend.(pendingCodeReader)
+		if !ok {
+			return errors.WithStack(bind.ErrNoPendingState)
+		}
+		code, err = pendingBackend.PendingCodeAt(opts.Context, contract)
+	} else {
+		code, err = backend.CodeAt(opts.Context, contract, opts.BlockNumber)
+	}
 	if err != nil {
 		err = cherrors.CheckIsChainNotReachableError(err)
 		return errors.WithMessage(err, "fetching contract code")
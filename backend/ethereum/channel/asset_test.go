@@ -21,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	ethchannel "perun.network/go-perun/backend/ethereum/channel"
@@ -29,6 +30,25 @@ import (
 	pkgtest "perun.network/go-perun/pkg/test"
 )
 
+func TestMarshalAsset(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	asset := ethwallettest.NewRandomAddress(rng)
+	data := ethchannel.MarshalAsset(&asset)
+
+	decoded, err := ethchannel.UnmarshalAsset(data)
+	require.NoError(t, err)
+	assert.Equal(t, asset, *decoded)
+
+	_, err = ethchannel.UnmarshalAsset(append(data, 0))
+	assert.Error(t, err, "trailing data should be rejected")
+	_, err = ethchannel.UnmarshalAsset(data[:len(data)-1])
+	assert.Error(t, err, "short data should be rejected")
+	_, err = ethchannel.UnmarshalAsset(append([]byte{2}, data[1:]...))
+	assert.Error(t, err, "unknown versions should be rejected")
+	_, err = ethchannel.UnmarshalAsset(nil)
+	assert.Error(t, err)
+}
+
 func TestValidateAssetHolderETH(t *testing.T) {
 	testValidateAssetHolder(t, ethchannel.DeployETHAssetholder, ethchannel.ValidateAssetHolderETH)
 }
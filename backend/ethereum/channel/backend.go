@@ -48,8 +48,6 @@ var (
 	abiBytes32, _ = abi.NewType("bytes32", "", nil)
 	abiParams     abi.Type
 	abiState      abi.Type
-	abiProgress   abi.Method
-	abiRegister   abi.Method
 )
 
 func init() {
@@ -71,14 +69,6 @@ func init() {
 		panic("hashState not found")
 	}
 	abiState = hashState.Inputs[0].Type
-
-	if abiProgress, ok = adj.Methods["progress"]; !ok {
-		panic("Could not find method progress in adjudicator contract.")
-	}
-
-	if abiRegister, ok = adj.Methods["register"]; !ok {
-		panic("Could not find method register in adjudicator contract.")
-	}
 }
 
 // Backend implements the interface defined in channel/Backend.go.
@@ -0,0 +1,100 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"perun.network/go-perun/channel"
+)
+
+// BatchError reports the errors of the failed requests of a batch operation,
+// indexed by the position of the request in the batch.
+type BatchError map[int]error
+
+// Error implements the error interface.
+func (e BatchError) Error() string {
+	idxs := make([]int, 0, len(e))
+	for i := range e {
+		idxs = append(idxs, i)
+	}
+	sort.Ints(idxs)
+	msgs := make([]string, len(idxs))
+	for j, i := range idxs {
+		msgs[j] = fmt.Sprintf("request %d: %v", i, e[i])
+	}
+	return fmt.Sprintf("%d requests of batch failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// BatchRegister registers the states of many independent channels, e.g., when
+// a service shuts down. The adjudicator contract registers one channel per
+// transaction, so BatchRegister sends all register transactions first, with
+// consecutive nonces, and waits for them to be mined afterwards. This takes
+// about as long as a single registration instead of one per channel.
+//
+// Like Register, final states are concluded directly. The channels must not
+// have sub-channels.
+//
+// Returns a BatchError containing the errors of the failed requests. The other
+// requests were registered successfully.
+func (a *Adjudicator) BatchRegister(ctx context.Context, reqs []channel.AdjudicatorReq) error {
+	var (
+		mu   sync.Mutex
+		errs = make(BatchError)
+		wg   sync.WaitGroup
+	)
+	setErr := func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[i] = err
+	}
+
+	for i, req := range reqs {
+		i, req := i, req
+		if req.Tx.State.IsFinal {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := a.registerFinal(ctx, req); err != nil {
+					setErr(i, err)
+				}
+			}()
+			continue
+		}
+
+		tx, err := a.send(ctx, req, a.registerFunc(nil), Register)
+		if err != nil {
+			setErr(i, err)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := a.confirm(ctx, tx, Register); err != nil {
+				setErr(i, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestAdjudicator_BatchRegister(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+
+	const n, invalid = 4, 2
+	reqs := make([]channel.AdjudicatorReq, n)
+	for i := range reqs {
+		params, state := channeltest.NewRandomParamsAndState(
+			rng,
+			channeltest.WithChallengeDuration(uint64(100*time.Second)),
+			channeltest.WithParts(s.Parts...),
+			channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+			channeltest.WithIsFinal(false),
+			channeltest.WithLedgerChannel(true),
+			channeltest.WithVirtualChannel(false),
+		)
+		tx := testSignState(t, s.Accs, params, state)
+		if i == invalid {
+			tx.State = tx.State.Clone()
+			tx.State.Version++ // invalidates the signature
+		}
+		reqs[i] = channel.AdjudicatorReq{Params: params, Acc: s.Accs[0], Idx: 0, Tx: tx}
+	}
+
+	err := s.Adjs[0].BatchRegister(ctx, reqs)
+	var batchErr ethchannel.BatchError
+	require.True(t, errors.As(err, &batchErr), "expected BatchError, got %v", err)
+	require.Len(t, batchErr, 1)
+	assert.Error(t, batchErr[invalid])
+
+	for i, req := range reqs {
+		if i == invalid {
+			continue
+		}
+		sub, err := s.Adjs[0].Subscribe(ctx, req.Params)
+		require.NoError(t, err)
+		event := sub.Next()
+		require.NoError(t, sub.Close())
+		require.IsType(t, &channel.RegisteredEvent{}, event, "channel %d", i)
+		assert.Equal(t, req.Tx.State.Version, event.Version(), "channel %d", i)
+	}
+}
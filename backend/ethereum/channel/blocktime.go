@@ -0,0 +1,81 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	cherrors "perun.network/go-perun/backend/ethereum/channel/errors"
+)
+
+// blockTimeCache caches the last block time estimate of a ContractBackend. It
+// is shared between all copies of a ContractBackend.
+type blockTimeCache struct {
+	mtx          sync.Mutex
+	head         uint64 // Block number of the head the estimate was made at.
+	sampleBlocks int    // Number of blocks that were sampled.
+	blockTime    time.Duration
+}
+
+// AverageBlockTime estimates the average time between two blocks by comparing
+// the timestamps of the latest block and the block sampleBlocks before it. If
+// the chain is shorter than sampleBlocks, all available blocks are sampled.
+//
+// The estimate is cached until a new block is mined, so calling this method
+// repeatedly only queries the chain once per block.
+func (c *ContractBackend) AverageBlockTime(ctx context.Context, sampleBlocks int) (time.Duration, error) {
+	if sampleBlocks < 1 {
+		return 0, errors.New("sampleBlocks must be positive")
+	}
+
+	latest, err := c.HeaderByNumber(ctx, nil)
+	if err != nil {
+		err = cherrors.CheckIsChainNotReachableError(err)
+		return 0, errors.WithMessage(err, "retrieving latest block")
+	}
+	head := latest.Number.Uint64()
+
+	c.blockTime.mtx.Lock()
+	defer c.blockTime.mtx.Unlock()
+	if c.blockTime.head == head && c.blockTime.sampleBlocks == sampleBlocks {
+		return c.blockTime.blockTime, nil
+	}
+
+	span := uint64(sampleBlocks)
+	if head < span {
+		span = head
+	}
+	if span == 0 {
+		return 0, errors.New("not enough blocks to sample")
+	}
+	first, err := c.HeaderByNumber(ctx, new(big.Int).SetUint64(head-span))
+	if err != nil {
+		err = cherrors.CheckIsChainNotReachableError(err)
+		return 0, errors.WithMessage(err, "retrieving first sampled block")
+	}
+
+	elapsed := time.Duration(latest.Time-first.Time) * time.Second
+	blockTime := elapsed / time.Duration(span)
+
+	c.blockTime.head = head
+	c.blockTime.sampleBlocks = sampleBlocks
+	c.blockTime.blockTime = blockTime
+	return blockTime, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestContractBackend_AverageBlockTime(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSimSetup(rng)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.CB.AverageBlockTime(ctx, 0)
+	assert.Error(t, err, "zero sample blocks should fail")
+
+	// The simulated backend mines a block every 10 seconds.
+	for i := 0; i < 5; i++ {
+		s.SimBackend.Commit()
+	}
+	for _, sampleBlocks := range []int{1, 3, 1000} {
+		blockTime, err := s.CB.AverageBlockTime(ctx, sampleBlocks)
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Second, blockTime)
+	}
+}
@@ -16,9 +16,12 @@ package channel
 
 import (
 	"context"
+	"strings"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/backend/ethereum/bindings"
@@ -30,6 +33,13 @@ import (
 
 const secondaryWaitBlocks = 2
 
+// Conclude ensures that the channel has been concluded with the state of req,
+// without withdrawing the funds from the asset holders. It makes the
+// Adjudicator a client.Concluder.
+func (a *Adjudicator) Conclude(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error {
+	return errors.WithMessage(a.ensureConcluded(ctx, req, subStates.Resolver()), "ensure Concluded")
+}
+
 // ensureConcluded ensures that conclude or concludeFinal (for non-final and
 // final states, resp.) is called on the adjudicator.
 // - a subscription on Concluded events is established
@@ -37,8 +47,8 @@ const secondaryWaitBlocks = 2
 //   - if found, channel is already concluded and success is returned
 //   - if none found, conclude/concludeFinal is called on the adjudicator
 // - it waits for a Concluded event from the blockchain.
-func (a *Adjudicator) ensureConcluded(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error {
-	sub, err := subscription.NewEventSub(ctx, a.ContractBackend, a.bound, updateEventType(req.Params.ID()), startBlockOffset)
+func (a *Adjudicator) ensureConcluded(ctx context.Context, req channel.AdjudicatorReq, resolve channel.StateResolver) error {
+	sub, err := a.newEventSub(ctx, a.bound, updateEventType(req.Params.ID()))
 	if err != nil {
 		return errors.WithMessage(err, "subscribing")
 	}
@@ -74,7 +84,7 @@ func (a *Adjudicator) ensureConcluded(ctx context.Context, req channel.Adjudicat
 	if req.Tx.IsFinal {
 		err = errors.WithMessage(a.callConcludeFinal(ctx, req), "calling concludeFinal")
 	} else {
-		err = errors.WithMessage(a.callConclude(ctx, req, subStates), "calling conclude")
+		err = errors.WithMessage(a.callConclude(ctx, req, resolve), "calling conclude")
 	}
 	if IsErrTxFailed(err) {
 		a.log.Warn("Calling conclude(Final) failed, waiting for event anyways...")
@@ -100,6 +110,40 @@ func (a *Adjudicator) ensureConcluded(ctx context.Context, req channel.Adjudicat
 	}
 }
 
+// CanConclude checks whether concluding the channel with the given request
+// would currently succeed, without sending a transaction. It simulates the
+// conclude or concludeFinal call, as Withdraw would send it for non-final and
+// final states, resp., on the latest block. If the call would revert, e.g.,
+// because the challenge duration has not elapsed yet or the channel is
+// already concluded, false and the revert reason are returned. An error is
+// only returned if the call could not be simulated.
+//
+// Note that the result can change with every block, e.g., when the timeout
+// elapses or another participant concludes the channel.
+func (a *Adjudicator) CanConclude(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) (ok bool, reason string, err error) {
+	opts := &bind.CallOpts{Context: ctx, From: a.txSender.Address}
+	ethParams := ToEthParams(req.Params)
+	ethState := ToEthState(req.Tx.State)
+	var out []interface{}
+	if req.Tx.IsFinal {
+		err = a.bound.Call(opts, &out, "concludeFinal", ethParams, ethState, req.Tx.Sigs)
+	} else {
+		var ethSubStates []adjudicator.ChannelState
+		if ethSubStates, err = toEthSubStates(req.Tx.State, subStates.Resolver()); err != nil {
+			return false, "", err
+		}
+		err = a.bound.Call(opts, &out, "conclude", ethParams, ethState, ethSubStates)
+	}
+
+	if err == nil {
+		return true, "", nil
+	} else if strings.Contains(err.Error(), vm.ErrExecutionReverted.Error()) {
+		return false, err.Error(), nil
+	}
+	err = cherrors.CheckIsChainNotReachableError(err)
+	return false, "", errors.WithMessage(err, "simulating conclude")
+}
+
 // isConcluded returns whether a channel is already concluded.
 func (a *Adjudicator) isConcluded(ctx context.Context, sub *subscription.EventSub) (bool, error) {
 	events := make(chan *subscription.Event, 10)
@@ -119,13 +163,53 @@ func (a *Adjudicator) isConcluded(ctx context.Context, sub *subscription.EventSu
 	return false, errors.WithMessage(<-subErr, "reading past events")
 }
 
-func updateEventType(channelID [32]byte) subscription.EventFactory {
+// AreConcluded returns for each of the given channels whether it is already
+// concluded on-chain. In contrast to checking each channel separately, the
+// past events of all channels are read with a single filter query.
+func (a *Adjudicator) AreConcluded(ctx context.Context, ids []channel.ID) (map[channel.ID]bool, error) {
+	concluded := make(map[channel.ID]bool, len(ids))
+	if len(ids) == 0 {
+		return concluded, nil
+	}
+	for _, id := range ids {
+		concluded[id] = false
+	}
+
+	sub, err := a.newEventSub(ctx, a.bound, updateEventType(ids...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "subscribing")
+	}
+	defer sub.Close()
+
+	events := make(chan *subscription.Event, 10)
+	subErr := make(chan error, 1)
+	go func() {
+		defer close(events)
+		subErr <- sub.ReadPast(ctx, events)
+	}()
+	for _e := range events {
+		e := _e.Data.(*adjudicator.AdjudicatorChannelUpdate)
+		if e.Phase == phaseConcluded {
+			concluded[e.ChannelID] = true
+		}
+	}
+	if err := <-subErr; err != nil {
+		return nil, errors.WithMessage(err, "reading past events")
+	}
+	return concluded, nil
+}
+
+func updateEventType(channelIDs ...[32]byte) subscription.EventFactory {
+	filter := make([]interface{}, len(channelIDs))
+	for i, id := range channelIDs {
+		filter[i] = id
+	}
 	return func() *subscription.Event {
 		return &subscription.Event{
 			Name: bindings.Events.AdjChannelUpdate,
 			Data: new(adjudicator.AdjudicatorChannelUpdate),
 			// In the best case we could already filter for 'Concluded' phase only here.
-			Filter: [][]interface{}{{channelID}},
+			Filter: [][]interface{}{filter},
 		}
 	}
 }
@@ -99,6 +99,83 @@ func testConcludeFinal(t *testing.T, numParts int) {
 	ct.Wait("register")
 }
 
+func TestAdjudicator_AreConcluded(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	params, state := channeltest.NewRandomParamsAndState(
+		rng,
+		channeltest.WithParts(s.Parts...),
+		channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+		channeltest.WithIsFinal(true),
+		channeltest.WithLedgerChannel(true),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+	otherID := channeltest.NewRandomChannelID(rng)
+	ids := []channel.ID{params.ID(), otherID}
+
+	concluded, err := s.Adjs[0].AreConcluded(ctx, ids)
+	require.NoError(t, err)
+	assert.Equal(t, map[channel.ID]bool{params.ID(): false, otherID: false}, concluded)
+
+	req := channel.NewFundingReq(params, state, 0, state.Balances)
+	require.NoError(t, s.Funders[0].Fund(ctx, *req))
+	regReq := channel.AdjudicatorReq{
+		Params: params,
+		Acc:    s.Accs[0],
+		Tx:     testSignState(t, s.Accs, params, state),
+	}
+	require.NoError(t, s.Adjs[0].Register(ctx, regReq, nil))
+
+	concluded, err = s.Adjs[0].AreConcluded(ctx, ids)
+	require.NoError(t, err)
+	assert.Equal(t, map[channel.ID]bool{params.ID(): true, otherID: false}, concluded)
+}
+
+func TestAdjudicator_CanConclude(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 2)
+	ctx, cancel := newDefaultTestContext()
+	defer cancel()
+	adj := s.Adjs[0]
+
+	ch := makeRandomChannel(rng, s.Parts, (*ethchannel.Asset)(&s.Asset), 60, true)
+	require.NoError(t, fund(ctx, s.Funders, ch))
+	tx, err := signState(s.Accs, ch.params, ch.state)
+	require.NoError(t, err)
+	req := channel.AdjudicatorReq{Params: ch.params, Acc: s.Accs[0], Tx: tx}
+
+	ok, reason, err := adj.CanConclude(ctx, req, nil)
+	require.NoError(t, err)
+	assert.False(t, ok, "unregistered channel cannot be concluded")
+	assert.NotEmpty(t, reason)
+
+	require.NoError(t, register(ctx, adj, s.Accs, ch, nil))
+	ok, reason, err = adj.CanConclude(ctx, req, nil)
+	require.NoError(t, err)
+	assert.False(t, ok, "channel cannot be concluded before the timeout")
+	assert.NotEmpty(t, reason)
+
+	sub, err := adj.Subscribe(ctx, ch.params)
+	require.NoError(t, err)
+	require.NoError(t, sub.Next().Timeout().Wait(ctx))
+	require.NoError(t, sub.Close())
+
+	ok, reason, err = adj.CanConclude(ctx, req, nil)
+	require.NoError(t, err)
+	assert.True(t, ok, "channel can be concluded after the timeout: %s", reason)
+
+	// A final state can be concluded without registering it first.
+	final := makeRandomChannel(rng, s.Parts, (*ethchannel.Asset)(&s.Asset), 60, true)
+	final.state.IsFinal = true
+	require.NoError(t, fund(ctx, s.Funders, final))
+	tx, err = signState(s.Accs, final.params, final.state)
+	require.NoError(t, err)
+	ok, reason, err = adj.CanConclude(ctx, channel.AdjudicatorReq{Params: final.params, Acc: s.Accs[0], Tx: tx}, nil)
+	require.NoError(t, err)
+	assert.True(t, ok, "final channel can be concluded: %s", reason)
+}
+
 func TestAdjudicator_ConcludeWithSubChannels(t *testing.T) {
 	// 0. setup
 
@@ -0,0 +1,68 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// SetConcurrencyLimit limits how many assets are processed concurrently
+// during a withdrawal. Every asset opens its own event subscription and sends
+// its own transaction, so channels with many assets can otherwise overwhelm
+// rate-limited RPC providers. Assets exceeding the limit are queued until a
+// running one has finished. A limit of zero, the default, disables limiting.
+//
+// Should only be called before the Adjudicator is used.
+func (a *Adjudicator) SetConcurrencyLimit(limit int) {
+	if limit < 0 {
+		a.log.Panic("concurrency limit must not be negative")
+	}
+	a.limit = newLimiter(limit)
+}
+
+// limiter bounds the number of concurrently running operations. The zero
+// value does not limit.
+type limiter chan struct{}
+
+func newLimiter(n int) limiter {
+	if n == 0 {
+		return nil
+	}
+	return make(limiter, n)
+}
+
+// acquire blocks until a slot is free or ctx is done. On success, the slot
+// must be released by calling release.
+func (l limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "context cancelled while waiting for slot")
+	}
+}
+
+// release frees a slot that was obtained by acquire.
+func (l limiter) release() {
+	if l == nil {
+		return
+	}
+	<-l
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Run("limits", func(t *testing.T) {
+		const limit, n = 2, 8
+		l := newLimiter(limit)
+		var running, max int32
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			go func() {
+				defer wg.Done()
+				require.NoError(t, l.acquire(context.Background()))
+				defer l.release()
+				cur := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&max)
+					if cur <= old || atomic.CompareAndSwapInt32(&max, old, cur) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(limit), max)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		l := newLimiter(0)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, l.acquire(context.Background()))
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		l := newLimiter(1)
+		require.NoError(t, l.acquire(context.Background()))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, l.acquire(ctx), context.Canceled)
+	})
+}
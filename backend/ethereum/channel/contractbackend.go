@@ -17,8 +17,10 @@ package channel
 import (
 	"context"
 	stderrors "errors"
+	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
@@ -29,6 +31,7 @@ import (
 	"github.com/pkg/errors"
 
 	cherrors "perun.network/go-perun/backend/ethereum/channel/errors"
+	"perun.network/go-perun/backend/ethereum/subscription"
 	"perun.network/go-perun/client"
 	"perun.network/go-perun/log"
 	pcontext "perun.network/go-perun/pkg/context"
@@ -37,7 +40,13 @@ import (
 // How many blocks we query into the past for events.
 const startBlockOffset = 100
 
-// GasLimit is the max amount of gas we want to send per transaction.
+// confirmationPollInterval is the interval in which the head of the chain is
+// queried while waiting for the confirmation depth of a transaction. It
+// matches the interval of bind.WaitMined.
+const confirmationPollInterval = time.Second
+
+// GasLimit is the default max amount of gas we want to send per adjudicator
+// transaction. See GasLimits.
 const GasLimit = 1000000
 
 // errTxTimedOut is an internal named error that with an empty message.
@@ -65,6 +74,9 @@ type ContractBackend struct {
 	tr                Transactor
 	nonceMtx          *sync.Mutex
 	expectedNextNonce map[common.Address]uint64
+	pollInterval      time.Duration
+	confirmationDepth uint64
+	blockTime         *blockTimeCache
 }
 
 // NewContractBackend creates a new ContractBackend with the given parameters.
@@ -74,7 +86,47 @@ func NewContractBackend(cf ContractInterface, tr Transactor) ContractBackend {
 		tr:                tr,
 		expectedNextNonce: make(map[common.Address]uint64),
 		nonceMtx:          &sync.Mutex{},
+		blockTime:         &blockTimeCache{},
+	}
+}
+
+// SetPollInterval sets the interval in which event subscriptions created by
+// this ContractBackend query the chain for new events. A zero interval, the
+// default, makes them rely on push-based log subscriptions instead.
+//
+// Shorter intervals lower the latency of event detection, longer intervals
+// lower the load on the RPC endpoint. Must be called before the
+// ContractBackend is passed to an Adjudicator or Funder.
+func (c *ContractBackend) SetPollInterval(interval time.Duration) {
+	if interval < 0 {
+		panic("negative poll interval")
 	}
+	c.pollInterval = interval
+}
+
+// PollInterval returns the poll interval of this ContractBackend.
+// Zero means that push-based log subscriptions are used.
+func (c *ContractBackend) PollInterval() time.Duration {
+	return c.pollInterval
+}
+
+// SetConfirmationDepth sets the number of blocks that must be mined on top of
+// the block of a transaction until ConfirmTransaction considers it confirmed.
+// Zero, the default, considers a transaction confirmed once it is mined. It
+// makes Adjudicators and Funders a client.ConfirmationDepthSetter.
+//
+// Must be called before the ContractBackend is used. Adjudicators and Funders
+// hold a copy of their ContractBackend, so call it on them directly once they
+// are created.
+func (c *ContractBackend) SetConfirmationDepth(depth uint64) {
+	c.confirmationDepth = depth
+}
+
+// newEventSub creates a new event subscription on the given contract that
+// respects the ContractBackend's poll interval and starts startBlockOffset
+// blocks in the past.
+func (c *ContractBackend) newEventSub(ctx context.Context, contract *bind.BoundContract, eFact subscription.EventFactory) (*subscription.EventSub, error) {
+	return subscription.NewPollingEventSub(ctx, c, contract, eFact, startBlockOffset, c.pollInterval)
 }
 
 // NewWatchOpts returns bind.WatchOpts with the field Start set to the current
@@ -158,12 +210,26 @@ func (c *ContractBackend) NewTransactor(ctx context.Context, gasLimit uint64,
 	return auth, nil
 }
 
+// releaseNonce returns the nonce of a transaction that was created with
+// NewTransactor but never sent, so that it is used by the next transaction of
+// acc. It does nothing if another nonce was handed out in the meantime.
+func (c *ContractBackend) releaseNonce(acc common.Address, nonce uint64) {
+	c.nonceMtx.Lock()
+	defer c.nonceMtx.Unlock()
+	if c.expectedNextNonce[acc] == nonce+1 {
+		c.expectedNextNonce[acc] = nonce
+	}
+}
+
 // ConfirmTransaction returns whether a transaction was mined successfully or not
 // and the receipt if it could be retrieved.
 // Returns txTimedOutError if the context is cancelled or if the context
 // deadline is exceeded when waiting for the transaction to be mined.
 func (c *ContractBackend) ConfirmTransaction(ctx context.Context, tx *types.Transaction, acc accounts.Account) (*types.Receipt, error) {
 	receipt, err := bind.WaitMined(ctx, c, tx)
+	if err == nil && c.confirmationDepth > 0 {
+		receipt, err = c.waitConfirmationDepth(ctx, tx, receipt)
+	}
 	if err != nil {
 		switch {
 		case pcontext.IsContextError(err):
@@ -193,6 +259,43 @@ func (c *ContractBackend) ConfirmTransaction(ctx context.Context, tx *types.Tran
 	return receipt, nil
 }
 
+// waitConfirmationDepth waits until the confirmation depth is reached on top of
+// the block of the receipt of tx. If tx is moved to another block by a
+// reorganization in the meantime, it waits for the depth on top of the new
+// block.
+func (c *ContractBackend) waitConfirmationDepth(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) (*types.Receipt, error) {
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+	for {
+		head, err := c.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		confirmed := new(big.Int).Add(receipt.BlockNumber, new(big.Int).SetUint64(c.confirmationDepth))
+		if head.Number.Cmp(confirmed) >= 0 {
+			current, err := c.TransactionReceipt(ctx, tx.Hash())
+			switch {
+			case errors.Is(err, ethereum.NotFound):
+				if current, err = bind.WaitMined(ctx, c, tx); err != nil {
+					return nil, err
+				}
+			case err != nil:
+				return nil, err
+			case current.BlockHash == receipt.BlockHash:
+				return receipt, nil
+			}
+			receipt = current
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // ErrTxFailed signals a failed, i.e., reverted, transaction.
 var ErrTxFailed = stderrors.New("transaction failed")
 
@@ -219,6 +322,24 @@ func errorReason(ctx context.Context, b *ContractBackend, tx *types.Transaction,
 	return reason, errors.Wrap(err, "unpacking revert reason")
 }
 
+// ErrGasLimitTooLow signals that a transaction needs more gas than the
+// configured gas limit allows. It is returned before the transaction is sent.
+type ErrGasLimitTooLow struct {
+	Needed     uint64 // Estimated gas needed by the transaction.
+	Configured uint64 // Gas limit the transaction would have been sent with.
+}
+
+// Error implements the error interface.
+func (e ErrGasLimitTooLow) Error() string {
+	return fmt.Sprintf("gas limit too low: needed %d, configured %d", e.Needed, e.Configured)
+}
+
+// IsErrGasLimitTooLow returns whether the error was caused by a gas limit that
+// is too low.
+func IsErrGasLimitTooLow(err error) bool {
+	return errors.As(err, new(ErrGasLimitTooLow))
+}
+
 // ErrInvalidContractCode signals invalid bytecode at given address, such as incorrect or no code.
 var ErrInvalidContractCode = stderrors.New("invalid bytecode at address")
 
@@ -16,9 +16,13 @@ package channel_test
 
 import (
 	"context"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -100,3 +104,55 @@ func Test_NewWatchOpts(t *testing.T) {
 	assert.Equal(t, context.WithValue(context.Background(), &key, "bar"), watchOpts.Context, "context should be set")
 	assert.Equal(t, uint64(1), *watchOpts.Start, "startblock should be 1")
 }
+
+func Test_PollInterval(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSimSetup(rng)
+	assert.Zero(t, s.CB.PollInterval(), "poll interval should be zero by default")
+	s.CB.SetPollInterval(time.Second)
+	assert.Equal(t, time.Second, s.CB.PollInterval())
+	assert.Panics(t, func() { s.CB.SetPollInterval(-time.Second) })
+}
+
+func Test_ConfirmationDepth(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSimSetup(rng)
+	// Waiting for the depth polls once per second.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts, err := s.CB.NewTransactor(ctx, 21000, s.TxSender.Account)
+	require.NoError(t, err)
+	tx, err := opts.Signer(opts.From, types.NewTransaction(opts.Nonce.Uint64(), common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil))
+	require.NoError(t, err)
+	require.NoError(t, s.SimBackend.SendTransaction(ctx, tx))
+
+	s.CB.SetConfirmationDepth(2)
+	confirmed := make(chan error, 1)
+	go func() {
+		_, err := s.CB.ConfirmTransaction(ctx, tx, s.TxSender.Account)
+		confirmed <- err
+	}()
+
+	s.SimBackend.Commit()
+	select {
+	case err := <-confirmed:
+		t.Fatalf("confirmed one block too early: %v", err)
+	case <-time.After(1500 * time.Millisecond):
+	}
+	s.SimBackend.Commit()
+	select {
+	case err := <-confirmed:
+		assert.NoError(t, err)
+	case <-ctx.Done():
+		t.Fatal("not confirmed")
+	}
+}
+
+func Test_IsErrGasLimitTooLow(t *testing.T) {
+	err := ethchannel.ErrGasLimitTooLow{Needed: 2, Configured: 1}
+	assert.True(t, ethchannel.IsErrGasLimitTooLow(err))
+	assert.True(t, ethchannel.IsErrGasLimitTooLow(errors.WithMessage(err, "wrapped")))
+	assert.False(t, ethchannel.IsErrGasLimitTooLow(errors.New("other")))
+	assert.Contains(t, err.Error(), "needed 2, configured 1")
+}
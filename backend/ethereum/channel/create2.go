@@ -0,0 +1,177 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/backend/ethereum/bindings/adjudicator"
+	"perun.network/go-perun/backend/ethereum/bindings/assetholdereth"
+	cherrors "perun.network/go-perun/backend/ethereum/channel/errors"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/log"
+)
+
+// Create2Factory is the address of the deterministic deployment proxy, a
+// CREATE2 factory that exists at the same address on most Ethereum networks.
+// It deploys the init code passed as call data after a 32 byte salt.
+// On networks where it is missing, it can be deployed with
+// DeployCreate2Factory.
+var Create2Factory = common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+
+// create2FactoryTx is the pre-signed transaction that deploys the
+// Create2Factory. It is not replay-protected, so it can be sent on any network.
+const create2FactoryTx = "0xf8a58085174876e800830186a08080b853604580600e600039806000f350fe7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf31ba02222222222222222222222222222222222222222222222222222222222222222a02222222222222222222222222222222222222222222222222222222222222222"
+
+// DeployAdjudicatorIfAbsent deploys an Adjudicator contract with CREATE2
+// using the given salt. The address only depends on the salt and the contract
+// code, so calling it again with the same salt returns the same address and
+// skips the deployment if the contract already exists.
+//
+// Requires the Create2Factory to be deployed.
+func DeployAdjudicatorIfAbsent(ctx context.Context, backend ContractBackend, deployer accounts.Account, salt [32]byte) (common.Address, error) {
+	return deployIfAbsent(ctx, backend, deployer, "Adjudicator", salt, common.FromHex(adjudicator.AdjudicatorBin))
+}
+
+// DeployETHAssetholderIfAbsent deploys an ETHAssetHolder contract for the
+// given Adjudicator with CREATE2 using the given salt. Like
+// DeployAdjudicatorIfAbsent, it is idempotent.
+//
+// Requires the Create2Factory to be deployed.
+func DeployETHAssetholderIfAbsent(ctx context.Context, backend ContractBackend, adjudicatorAddr common.Address, deployer accounts.Account, salt [32]byte) (common.Address, error) {
+	parsed, err := abi.JSON(strings.NewReader(assetholdereth.AssetHolderETHABI))
+	if err != nil {
+		return common.Address{}, errors.WithStack(err)
+	}
+	args, err := parsed.Pack("", adjudicatorAddr)
+	if err != nil {
+		return common.Address{}, errors.WithMessage(err, "packing constructor arguments")
+	}
+	initCode := append(common.FromHex(assetholdereth.AssetHolderETHBin), args...)
+	return deployIfAbsent(ctx, backend, deployer, "ETHAssetHolder", salt, initCode)
+}
+
+// Create2Address returns the address of a contract that is deployed by the
+// Create2Factory with the given salt and init code.
+func Create2Address(salt [32]byte, initCode []byte) common.Address {
+	return crypto.CreateAddress2(Create2Factory, salt, crypto.Keccak256(initCode))
+}
+
+// deployIfAbsent deploys the init code with the Create2Factory if there is no
+// code at the resulting address yet.
+func deployIfAbsent(ctx context.Context, cb ContractBackend, deployer accounts.Account, name string, salt [32]byte, initCode []byte) (common.Address, error) {
+	addr := Create2Address(salt, initCode)
+	if ok, err := hasCode(ctx, cb, addr); err != nil {
+		return common.Address{}, err
+	} else if ok {
+		log.Infof("%s already deployed at %v.", name, addr.Hex())
+		return addr, nil
+	}
+	if ok, err := hasCode(ctx, cb, Create2Factory); err != nil {
+		return common.Address{}, err
+	} else if !ok {
+		return common.Address{}, errors.Errorf("CREATE2 factory not deployed at %v", Create2Factory.Hex())
+	}
+
+	auth, err := cb.NewTransactor(ctx, deployGasLimit, deployer)
+	if err != nil {
+		return common.Address{}, errors.WithMessage(err, "creating transactor")
+	}
+	factory := bind.NewBoundContract(Create2Factory, abi.ABI{}, cb, cb, cb)
+	tx, err := factory.RawTransact(auth, append(salt[:], initCode...))
+	if err != nil {
+		err = cherrors.CheckIsChainNotReachableError(err)
+		return common.Address{}, errors.WithMessage(err, "creating transaction")
+	}
+	if _, err := cb.ConfirmTransaction(ctx, tx, deployer); err != nil {
+		if errors.Is(err, errTxTimedOut) {
+			err = client.NewTxTimedoutError("deploy "+name, tx.Hash().Hex(), err.Error())
+		}
+		return common.Address{}, errors.WithMessagef(err, "deploying %s", name)
+	}
+	if ok, err := hasCode(ctx, cb, addr); err != nil {
+		return common.Address{}, err
+	} else if !ok {
+		return common.Address{}, errors.Errorf("no code at %v after deploying %s", addr.Hex(), name)
+	}
+	log.Infof("Deployed %s at %v.", name, addr.Hex())
+	return addr, nil
+}
+
+// DeployCreate2Factory deploys the Create2Factory if it does not exist yet.
+// This is only needed on networks without the factory, e.g., local test
+// networks. The funder pays the deployment costs by funding the one-time
+// account that signed the pre-signed deployment transaction.
+func DeployCreate2Factory(ctx context.Context, backend ContractBackend, funder accounts.Account) error {
+	if ok, err := hasCode(ctx, backend, Create2Factory); err != nil || ok {
+		return err
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(hexutil.MustDecode(create2FactoryTx)); err != nil {
+		return errors.WithMessage(err, "decoding factory deployment transaction")
+	}
+	sender, err := types.Sender(types.HomesteadSigner{}, tx)
+	if err != nil {
+		return errors.WithMessage(err, "recovering factory deployer")
+	}
+
+	// Fund the one-time account.
+	auth, err := backend.NewTransactor(ctx, params.TxGas, funder)
+	if err != nil {
+		return errors.WithMessage(err, "creating transactor")
+	}
+	auth.Value = new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+	fundTx, err := bind.NewBoundContract(sender, abi.ABI{}, backend, backend, backend).Transfer(auth)
+	if err != nil {
+		err = cherrors.CheckIsChainNotReachableError(err)
+		return errors.WithMessage(err, "funding factory deployer")
+	}
+	if _, err := backend.ConfirmTransaction(ctx, fundTx, funder); err != nil {
+		return errors.WithMessage(err, "funding factory deployer")
+	}
+
+	if err := backend.SendTransaction(ctx, tx); err != nil {
+		err = cherrors.CheckIsChainNotReachableError(err)
+		return errors.WithMessage(err, "sending factory deployment transaction")
+	}
+	if _, err := backend.ConfirmTransaction(ctx, tx, funder); err != nil {
+		return errors.WithMessage(err, "deploying factory")
+	}
+	log.Infof("Deployed CREATE2 factory at %v.", Create2Factory.Hex())
+	return nil
+}
+
+// hasCode returns whether there is contract code at the given address.
+func hasCode(ctx context.Context, cb ContractBackend, addr common.Address) (bool, error) {
+	code, err := cb.CodeAt(ctx, addr, nil)
+	if err != nil {
+		err = cherrors.CheckIsChainNotReachableError(err)
+		return false, errors.WithMessagef(err, "fetching code at %v", addr.Hex())
+	}
+	return len(code) > 0, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestDeployIfAbsent(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSimSetup(rng)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+	var salt [32]byte
+	rng.Read(salt[:])
+
+	_, err := ethchannel.DeployAdjudicatorIfAbsent(ctx, *s.CB, s.TxSender.Account, salt)
+	assert.Error(t, err, "deploying without factory should fail")
+
+	require.NoError(t, ethchannel.DeployCreate2Factory(ctx, *s.CB, s.TxSender.Account))
+	require.NoError(t, ethchannel.DeployCreate2Factory(ctx, *s.CB, s.TxSender.Account), "redeploying factory should be skipped")
+
+	adj, err := ethchannel.DeployAdjudicatorIfAbsent(ctx, *s.CB, s.TxSender.Account, salt)
+	require.NoError(t, err)
+	code, err := s.CB.CodeAt(ctx, adj, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, code)
+
+	ah, err := ethchannel.DeployETHAssetholderIfAbsent(ctx, *s.CB, adj, s.TxSender.Account, salt)
+	require.NoError(t, err)
+	require.NoError(t, ethchannel.ValidateAssetHolderETH(ctx, *s.CB, ah, adj))
+
+	// Re-running yields the same addresses without sending transactions.
+	diff, err := test.NonceDiff(s.TxSender.Address(), s.CB, func() error {
+		adj2, err := ethchannel.DeployAdjudicatorIfAbsent(ctx, *s.CB, s.TxSender.Account, salt)
+		assert.Equal(t, adj, adj2)
+		if err != nil {
+			return err
+		}
+		ah2, err := ethchannel.DeployETHAssetholderIfAbsent(ctx, *s.CB, adj, s.TxSender.Account, salt)
+		assert.Equal(t, ah, ah2)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Zero(t, diff)
+}
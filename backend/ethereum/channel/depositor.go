@@ -21,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 
 	"perun.network/go-perun/channel"
+	perunwallet "perun.network/go-perun/wallet"
 )
 
 type (
@@ -37,6 +38,16 @@ type (
 		NumTX() uint32
 	}
 
+	// FundingIDDeriver can optionally be implemented by a Depositor whose
+	// AssetHolder uses a different deposit-key scheme than the standard Perun
+	// AssetHolder. The Funder then uses it to derive the funding IDs of the
+	// participants for deposits and deposit verification of this asset.
+	FundingIDDeriver interface {
+		// FundingIDs returns the funding IDs of the given participants in the
+		// channel, in the same order.
+		FundingIDs(channelID channel.ID, participants ...perunwallet.Address) [][32]byte
+	}
+
 	// DepositReq contains all necessary data for a `Depositor` to deposit funds.
 	// It is much smaller than a `FundingReq` and only holds the information
 	// for one Funding-ID.
@@ -0,0 +1,171 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/backend/ethereum/bindings"
+	"perun.network/go-perun/backend/ethereum/bindings/adjudicator"
+	"perun.network/go-perun/backend/ethereum/subscription"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+)
+
+type (
+	// EventCursor is the position of an adjudicator event on the chain.
+	EventCursor struct {
+		Block    uint64 // Block number.
+		LogIndex uint   // Index of the log in the block.
+	}
+
+	// StreamEvent is an adjudicator event delivered by an EventStream,
+	// together with its position on the chain.
+	StreamEvent struct {
+		channel.AdjudicatorEvent
+		Cursor EventCursor
+	}
+
+	// EventStream delivers the channel update events of all channels of an
+	// adjudicator contract, ordered by their position on the chain.
+	EventStream struct {
+		sub    *subscription.EventSub
+		next   chan *StreamEvent
+		err    chan error
+		done   chan struct{}
+		closed sync.Once
+	}
+)
+
+// CursorAtBlock returns the cursor of the first possible event in the given
+// block.
+func CursorAtBlock(block uint64) EventCursor {
+	return EventCursor{Block: block}
+}
+
+// Next returns the cursor directly after c. A stream that is resumed from it
+// continues with the event following the event at c.
+func (c EventCursor) Next() EventCursor {
+	return EventCursor{Block: c.Block, LogIndex: c.LogIndex + 1}
+}
+
+// Before returns whether c is positioned before o.
+func (c EventCursor) Before(o EventCursor) bool {
+	return c.Block < o.Block || c.Block == o.Block && c.LogIndex < o.LogIndex
+}
+
+// SubscribeAll returns a stream of the channel update events of all channels
+// of the adjudicator contract, starting at the given cursor. It is intended
+// for indexers rather than channel participants.
+//
+// Events are delivered in the order of their position on the chain, every
+// event at most once per stream. To resume after a restart, persist the cursor
+// of the last processed event and subscribe from its Next cursor. Persisting
+// the cursor only after processing the event results in at-least-once
+// delivery across restarts.
+func (a *Adjudicator) SubscribeAll(ctx context.Context, from EventCursor) (*EventStream, error) {
+	eFact := func() *subscription.Event {
+		return &subscription.Event{
+			Name: bindings.Events.AdjChannelUpdate,
+			Data: new(adjudicator.AdjudicatorChannelUpdate),
+		}
+	}
+	sub, err := subscription.NewEventSubFrom(ctx, a.ContractBackend, a.bound, eFact, from.Block, a.pollInterval)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating event subscription")
+	}
+
+	s := &EventStream{
+		sub:  sub,
+		next: make(chan *StreamEvent),
+		err:  make(chan error, 1),
+		done: make(chan struct{}),
+	}
+	go s.run(ctx, a, from)
+	return s, nil
+}
+
+func (s *EventStream) run(ctx context.Context, a *Adjudicator, from EventCursor) {
+	if err := s.stream(ctx, a, from); err != nil {
+		s.err <- err
+	}
+	close(s.err)
+	close(s.next)
+}
+
+func (s *EventStream) stream(ctx context.Context, a *Adjudicator, next EventCursor) error {
+	events := make(chan *subscription.Event, 10)
+	subErr := make(chan error, 1)
+	go func() {
+		subErr <- s.sub.Read(ctx, events)
+	}()
+
+	for {
+		select {
+		case e := <-events:
+			cursor := EventCursor{Block: e.Log.BlockNumber, LogIndex: e.Log.Index}
+			// Skip events that were removed by a reorg, already delivered or
+			// read twice by the underlying subscription.
+			if e.Log.Removed || cursor.Before(next) {
+				continue
+			}
+			update, ok := e.Data.(*adjudicator.AdjudicatorChannelUpdate)
+			if !ok {
+				log.Panicf("unexpected event type: %T", e.Data)
+			}
+			update.Raw = e.Log
+			ev, err := a.convertEvent(ctx, update)
+			if err != nil {
+				return errors.WithMessage(err, "converting event")
+			}
+
+			select {
+			case s.next <- &StreamEvent{AdjudicatorEvent: ev, Cursor: cursor}:
+				next = cursor.Next()
+			case <-s.done:
+				return nil
+			}
+		case err := <-subErr:
+			return errors.WithMessage(err, "EventSub closed")
+		case <-s.done:
+			return nil
+		}
+	}
+}
+
+// Next returns the next event of the stream. It blocks until an event is
+// available or the stream is closed. If the stream is closed, Next returns
+// nil.
+func (s *EventStream) Next() *StreamEvent {
+	return <-s.next
+}
+
+// Close closes the stream. Any pending calls to Next will return nil.
+func (s *EventStream) Close() error {
+	s.closed.Do(func() {
+		close(s.done)
+		s.sub.Close()
+	})
+	return nil
+}
+
+// Err returns the error of the stream. Should only be called after Next
+// returned nil.
+func (s *EventStream) Err() error {
+	return <-s.err
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestAdjudicator_SubscribeAll(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	adj := s.Adjs[0]
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+
+	// Register two channels.
+	const numChannels = 2
+	var ids []channel.ID
+	for i := 0; i < numChannels; i++ {
+		params, state := channeltest.NewRandomParamsAndState(rng,
+			channeltest.WithChallengeDuration(uint64(100*time.Second)),
+			channeltest.WithParts(s.Parts...),
+			channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+			channeltest.WithIsFinal(true),
+			channeltest.WithLedgerChannel(true),
+		)
+		reqFund := channel.NewFundingReq(params, state, channel.Index(0), state.Balances)
+		require.NoError(t, s.Funders[0].Fund(ctx, *reqFund))
+		req := channel.AdjudicatorReq{
+			Params: params,
+			Acc:    s.Accs[0],
+			Idx:    channel.Index(0),
+			Tx:     testSignState(t, s.Accs, params, state),
+		}
+		require.NoError(t, adj.Register(ctx, req, nil))
+		ids = append(ids, params.ID())
+	}
+
+	stream, err := adj.SubscribeAll(ctx, ethchannel.CursorAtBlock(0))
+	require.NoError(t, err)
+	var events []*ethchannel.StreamEvent
+	for i := 0; i < numChannels; i++ {
+		e := stream.Next()
+		require.NotNil(t, e)
+		assert.Equal(t, ids[i], e.ID())
+		assert.IsType(t, new(channel.ConcludedEvent), e.AdjudicatorEvent, "final states are concluded directly")
+		if i > 0 {
+			assert.True(t, events[i-1].Cursor.Before(e.Cursor), "events must be ordered")
+		}
+		events = append(events, e)
+	}
+	require.NoError(t, stream.Close())
+	assert.Nil(t, stream.Next())
+	assert.NoError(t, stream.Err())
+
+	// Resume after the first event.
+	resumed, err := adj.SubscribeAll(ctx, events[0].Cursor.Next())
+	require.NoError(t, err)
+	defer resumed.Close()
+	e := resumed.Next()
+	require.NotNil(t, e)
+	assert.Equal(t, events[1].Cursor, e.Cursor)
+	assert.Equal(t, ids[1], e.ID())
+}
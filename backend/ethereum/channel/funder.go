@@ -15,8 +15,10 @@
 package channel
 
 import (
+	"bytes"
 	"context"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts"
@@ -29,6 +31,7 @@ import (
 
 	"perun.network/go-perun/backend/ethereum/bindings"
 	"perun.network/go-perun/backend/ethereum/bindings/assetholder"
+	cherrors "perun.network/go-perun/backend/ethereum/channel/errors"
 	"perun.network/go-perun/backend/ethereum/subscription"
 	"perun.network/go-perun/backend/ethereum/wallet"
 	"perun.network/go-perun/channel"
@@ -60,11 +63,20 @@ type Funder struct {
 	accounts map[Asset]accounts.Account
 	// depositors associates a Depositor to every AssetIndex.
 	depositors map[Asset]Depositor
-	log        log.Logger // structured logger
+	retry      fundingRetry
+	// timeout is the funding timeout in blockchain seconds. If zero, the
+	// channel's challenge duration is used.
+	timeout uint64
+	log     log.Logger // structured logger
 }
 
-// compile time check that we implement the perun funder interface.
-var _ channel.Funder = (*Funder)(nil)
+// compile time checks that we implement the perun funder interfaces.
+var (
+	_ channel.Funder                 = (*Funder)(nil)
+	_ client.FundingIDProvider       = (*Funder)(nil)
+	_ client.DepositReader           = (*Funder)(nil)
+	_ client.ConfirmationDepthSetter = (*Funder)(nil)
+)
 
 // NewFunder creates a new ethereum funder.
 func NewFunder(backend ContractBackend) *Funder {
@@ -116,9 +128,42 @@ func (f *Funder) IsAssetRegistered(asset Asset) (Depositor, accounts.Account, bo
 	return nil, accounts.Account{}, false
 }
 
+// SupportedAssets returns a snapshot of the assets that are registered in the
+// funder, ordered by their address. It can be used to check channel proposals
+// for unsupported assets before funding.
+func (f *Funder) SupportedAssets() []channel.Asset {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	assets := make([]Asset, 0, len(f.accounts))
+	for asset := range f.accounts {
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		return bytes.Compare(assets[i][:], assets[j][:]) < 0
+	})
+
+	ret := make([]channel.Asset, len(assets))
+	for i := range assets {
+		ret[i] = &assets[i]
+	}
+	return ret
+}
+
+// SetFundingTimeout sets the duration in blockchain seconds that Fund waits
+// for all participants to deposit. A timeout of zero, the default, uses the
+// channel's ChallengeDuration.
+func (f *Funder) SetFundingTimeout(timeout uint64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.timeout = timeout
+}
+
 // Fund implements the channel.Funder interface. It funds all assets in
-// parallel. If not all participants successfully fund within a timeframe of
-// ChallengeDuration seconds, Fund returns a FundingTimeoutError.
+// parallel. If not all participants successfully fund within the funding
+// timeout, Fund returns a FundingTimeoutError that identifies the participants
+// whose deposits are missing. The funding timeout is ChallengeDuration
+// seconds, unless set with SetFundingTimeout.
 //
 // If funding on a real blockchain, make sure that the passed context doesn't
 // cancel before the funding period of length ChallengeDuration elapses, or
@@ -132,7 +177,11 @@ func (f *Funder) Fund(ctx context.Context, request channel.FundingReq) error {
 
 	// We wait for the funding timeout in a go routine and cancel the funding
 	// context if the timeout elapses.
-	timeout, err := NewBlockTimeoutDuration(ctx, f.ContractInterface, request.Params.ChallengeDuration)
+	duration := f.timeout
+	if duration == 0 {
+		duration = request.Params.ChallengeDuration
+	}
+	timeout, err := NewBlockTimeoutDuration(ctx, f.ContractInterface, duration)
 	if err != nil {
 		return errors.WithMessage(err, "creating block timeout")
 	}
@@ -185,18 +234,32 @@ func (f *Funder) Fund(ctx context.Context, request channel.FundingReq) error {
 func (f *Funder) fundAssets(ctx context.Context, channelID channel.ID, req channel.FundingReq) ([]types.Transactions, *perror.Gatherer) {
 	txs := make([]types.Transactions, len(req.State.Assets))
 	errg := perror.NewGatherer()
-	fundingIDs := FundingIDs(channelID, req.Params.Parts...)
 
 	for index, asset := range req.State.Assets {
+		fundingIDs := f.fundingIDs(*asset.(*Asset), channelID, req.Params.Parts...)
 		// Bind contract.
 		contract := bindAssetHolder(f.ContractBackend, asset, channel.Index(index))
 		// Wait for the funding event.
 		errg.Go(func() error {
-			return f.waitForFundingConfirmation(ctx, req, contract, fundingIDs)
+			return f.retry.do(ctx, func() error {
+				return f.waitForFundingConfirmation(ctx, req, contract, fundingIDs)
+			})
 		})
 
-		// Send the funding TX.
-		tx, err := f.sendFundingTx(ctx, req, contract, fundingIDs[req.Idx])
+		// Send the funding TX. The sent TXs are recorded, so that a retry does
+		// not send a deposit again whose sending only seemingly failed.
+		rec := &txRecorder{ContractInterface: f.ContractInterface}
+		cb := f.ContractBackend
+		cb.ContractInterface = rec
+		acc := f.accounts[*asset.(*Asset)]
+		var tx types.Transactions
+		err := f.retry.do(ctx, func() (err error) {
+			if err := f.awaitSent(ctx, rec.sent(), acc); err != nil {
+				return err
+			}
+			tx, err = f.sendFundingTx(ctx, cb, req, contract, fundingIDs[req.Idx])
+			return
+		})
 		if err != nil {
 			f.log.WithField("asset", asset).WithError(err).Error("Could not fund asset")
 			errg.Add(errors.WithMessage(err, "funding asset"))
@@ -209,7 +272,7 @@ func (f *Funder) fundAssets(ctx context.Context, channelID channel.ID, req chann
 
 // sendFundingTx sends and returns the TXs that are needed to fulfill the
 // funding request. It is idempotent.
-func (f *Funder) sendFundingTx(ctx context.Context, request channel.FundingReq, contract assetHolder, fundingID [32]byte) (txs []*types.Transaction, fatal error) {
+func (f *Funder) sendFundingTx(ctx context.Context, cb ContractBackend, request channel.FundingReq, contract assetHolder, fundingID [32]byte) (txs []*types.Transaction, fatal error) {
 	bal := request.Agreement[contract.assetIndex][request.Idx]
 	// nolint: gocritic
 	if bal == nil || bal.Sign() <= 0 {
@@ -219,14 +282,15 @@ func (f *Funder) sendFundingTx(ctx context.Context, request channel.FundingReq,
 	} else if alreadyFunded {
 		f.log.WithFields(log.Fields{"channel": request.Params.ID(), "idx": request.Idx}).Debug("Skipped second funding.")
 	} else {
-		return f.deposit(ctx, bal, wallet.Address(*contract.Address), fundingID)
+		return f.deposit(ctx, cb, bal, wallet.Address(*contract.Address), fundingID)
 	}
 	return nil, nil
 }
 
-// deposit deposits funds for one funding-ID by calling the associated Depositor.
+// deposit deposits funds for one funding-ID by calling the associated Depositor,
+// which sends its transactions via cb.
 // Returns an error if no matching Depositor or Account could be found.
-func (f *Funder) deposit(ctx context.Context, bal *big.Int, asset Asset, fundingID [32]byte) (types.Transactions, error) {
+func (f *Funder) deposit(ctx context.Context, cb ContractBackend, bal *big.Int, asset Asset, fundingID [32]byte) (types.Transactions, error) {
 	depositor, ok := f.depositors[asset]
 	if !ok {
 		return nil, errors.Errorf("could not find Depositor for asset #%d", asset)
@@ -236,7 +300,7 @@ func (f *Funder) deposit(ctx context.Context, bal *big.Int, asset Asset, funding
 		return nil, errors.Errorf("could not find account for asset #%d", asset)
 	}
 
-	return depositor.Deposit(ctx, *NewDepositReq(bal, f.ContractBackend, asset, acc, fundingID))
+	return depositor.Deposit(ctx, *NewDepositReq(bal, cb, asset, acc, fundingID))
 }
 
 // checkFunded returns whether `fundingID` holds at least `amount` funds.
@@ -275,7 +339,7 @@ func (f *Funder) depositedSub(ctx context.Context, contract *bind.BoundContract,
 			Filter: [][]interface{}{filter},
 		}
 	}
-	sub, err := subscription.NewEventSub(ctx, f, contract, event, startBlockOffset)
+	sub, err := f.newEventSub(ctx, contract, event)
 	return sub, errors.WithMessage(err, "subscribing to deposited event")
 }
 
@@ -288,7 +352,7 @@ func (f *Funder) waitForFundingConfirmation(ctx context.Context, request channel
 	// Subscribe to events.
 	sub, err := f.depositedSub(ctx, asset.contract, fundingIDs...)
 	if err != nil {
-		return errors.WithMessage(err, "subscribing to deposited event")
+		return errors.WithMessage(cherrors.CheckIsChainNotReachableError(err), "subscribing to deposited event")
 	}
 	defer sub.Close()
 	// Read from the sub.
@@ -336,7 +400,7 @@ func (f *Funder) waitForFundingConfirmation(ctx context.Context, request channel
 			}
 			return nil
 		case err := <-subErr:
-			return err
+			return errors.WithMessage(cherrors.CheckIsChainNotReachableError(err), "reading deposited events")
 		}
 	}
 	return nil
@@ -360,6 +424,49 @@ func countZeroBalances(bals []channel.Bal) (n int) {
 	return
 }
 
+// fundingIDs returns the funding IDs of the participants for the given asset.
+// They are derived by the asset's Depositor if it is a FundingIDDeriver and by
+// FundingIDs otherwise.
+func (f *Funder) fundingIDs(asset Asset, channelID channel.ID, participants ...perunwallet.Address) [][32]byte {
+	if d, ok := f.depositors[asset].(FundingIDDeriver); ok {
+		return d.FundingIDs(channelID, participants...)
+	}
+	return FundingIDs(channelID, participants...)
+}
+
+// FundingIDs returns the funding IDs of the participants, indexed by asset and
+// participant, as used for deposits of each asset. It makes the Funder a
+// client.FundingIDProvider.
+func (f *Funder) FundingIDs(params *channel.Params, assets []channel.Asset) [][][32]byte {
+	ids := make([][][32]byte, len(assets))
+	for a, asset := range assets {
+		ids[a] = f.fundingIDs(*asset.(*Asset), params.ID(), params.Parts...)
+	}
+	return ids
+}
+
+// Deposits returns the on-chain holdings of the participants' funding IDs,
+// indexed by asset and participant. It makes the Funder a client.DepositReader.
+func (f *Funder) Deposits(ctx context.Context, params *channel.Params, assets []channel.Asset) (channel.Balances, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	deposits := make(channel.Balances, len(assets))
+	for a, asset := range assets {
+		contract := bindAssetHolder(f.ContractBackend, asset, channel.Index(a))
+		fundingIDs := f.fundingIDs(*asset.(*Asset), params.ID(), params.Parts...)
+		deposits[a] = make([]channel.Bal, len(fundingIDs))
+		for p, fundingID := range fundingIDs {
+			holdings, err := contract.Holdings(&bind.CallOpts{Context: ctx}, fundingID)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "reading holdings of participant %d for asset %d", p, a)
+			}
+			deposits[a][p] = holdings
+		}
+	}
+	return deposits, nil
+}
+
 // FundingIDs returns a slice the same size as the number of passed participants
 // where each entry contains the hash Keccak256(channel id || participant address).
 func FundingIDs(channelID channel.ID, participants ...perunwallet.Address) [][32]byte {
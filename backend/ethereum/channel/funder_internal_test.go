@@ -0,0 +1,58 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+	perunwallet "perun.network/go-perun/wallet"
+	wallettest "perun.network/go-perun/wallet/test"
+)
+
+// customIDDepositor derives the channel ID as funding ID for everyone.
+type customIDDepositor struct{ *ETHDepositor }
+
+func (customIDDepositor) FundingIDs(channelID channel.ID, participants ...perunwallet.Address) [][32]byte {
+	ids := make([][32]byte, len(participants))
+	for i := range ids {
+		ids[i] = channelID
+	}
+	return ids
+}
+
+func TestFunder_fundingIDs(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	f := NewFunder(ContractBackend{})
+	std, custom := Asset(common.Address{1}), Asset(common.Address{2})
+	f.depositors[std] = NewETHDepositor()
+	f.depositors[custom] = NewVerifyingDepositor(customIDDepositor{NewETHDepositor()}, 0)
+
+	id := channeltest.NewRandomChannelID(rng)
+	parts := []perunwallet.Address{wallettest.NewRandomAddress(rng), wallettest.NewRandomAddress(rng)}
+	assert.Equal(t, FundingIDs(id, parts...), f.fundingIDs(std, id, parts...))
+	assert.Equal(t, [][32]byte{id, id}, f.fundingIDs(custom, id, parts...))
+
+	params := channeltest.NewRandomParams(rng, channeltest.WithParts(parts...))
+	assert.Equal(t,
+		[][][32]byte{FundingIDs(params.ID(), parts...), {params.ID(), params.ID()}},
+		f.FundingIDs(params, []channel.Asset{&std, &custom}),
+		"funding IDs should be derived per asset")
+}
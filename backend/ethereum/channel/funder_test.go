@@ -18,9 +18,11 @@ import (
 	"context"
 	"math/big"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -57,10 +59,18 @@ func TestFunder_RegisterAsset_IsAssetRegistered(t *testing.T) {
 		require.False(t, ok, "on a newly initialzed funder, no assets are registered")
 	}
 
+	assert.Empty(t, funder.SupportedAssets())
+
 	for i := 0; i < n; i++ {
 		require.True(t, funder.RegisterAsset(assets[i], depositors[i], accs[i]), "should not error on registering a new asset")
 	}
 
+	supported := funder.SupportedAssets()
+	require.Len(t, supported, n)
+	for i := 0; i < n; i++ {
+		assert.Contains(t, supported, &assets[i])
+	}
+
 	for i := 0; i < n; i++ {
 		depositor, acc, ok := funder.IsAssetRegistered(assets[i])
 		require.True(t, ok, "registered asset should be returned")
@@ -69,6 +79,19 @@ func TestFunder_RegisterAsset_IsAssetRegistered(t *testing.T) {
 	}
 }
 
+func TestFunder_FundingIDs(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	funder, ethAssets, _, _ := newFunderWithDummy_ETH_ERC20_Assets(rng)
+	params, _ := channeltest.NewRandomParamsAndState(rng, channeltest.WithNumParts(2))
+
+	assets := []channel.Asset{&ethAssets[0], &ethAssets[1]}
+	ids := funder.FundingIDs(params, assets)
+	require.Len(t, ids, len(assets))
+	for _, assetIDs := range ids {
+		assert.Equal(t, ethchannel.FundingIDs(params.ID(), params.Parts...), assetIDs)
+	}
+}
+
 func newFunderWithDummy_ETH_ERC20_Assets(rng *rand.Rand) (
 	*ethchannel.Funder, []ethchannel.Asset, []ethchannel.Depositor, []accounts.Account) {
 	n := 2
@@ -265,6 +288,9 @@ func TestFunder_Multiple(t *testing.T) {
 	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 	assert.NoError(t, compareOnChainAlloc(ctx, params, alloc.Balances, alloc.Assets, &funders[0].ContractBackend))
+	deposits, err := funders[0].Deposits(ctx, params, alloc.Assets)
+	require.NoError(t, err)
+	assert.True(t, deposits.Equal(alloc.Balances), "deposits should equal funded balances")
 }
 
 func TestFunder_PeerTimeout(t *testing.T) {
@@ -328,6 +354,122 @@ func testFundingTimeout(t *testing.T, faultyPeer, n int) {
 	ct.Wait("funding loop")
 }
 
+func TestFunder_SetFundingTimeout(t *testing.T) {
+	const n, faultyPeer = 2, 1
+	// The context outlives the funding timeout, so that Fund only returns
+	// because of the funding timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*defaultTxTimeout)
+	defer cancel()
+	rng := pkgtest.Prng(t)
+
+	_, funders, params, alloc := newNFunders(ctx, t, rng, n)
+	// The funding timeout is much shorter than the challenge duration.
+	fundingTimeout := params.ChallengeDuration
+	params.ChallengeDuration *= 100
+	funders[0].SetFundingTimeout(fundingTimeout)
+
+	errs := make(chan error, 1)
+	go func() {
+		req := channel.NewFundingReq(params, &channel.State{Allocation: *alloc}, 0, alloc.Balances)
+		errs <- funders[0].Fund(ctx, *req)
+	}()
+
+	// Give the funder time to deposit, then let the funding timeout elapse.
+	time.Sleep(time.Duration(n*len(alloc.Balances)) * 200 * time.Millisecond)
+	sb, ok := funders[0].ContractInterface.(*test.SimulatedBackend)
+	require.True(t, ok)
+	require.NoError(t, sb.AdjustTime(time.Duration(fundingTimeout)*time.Second))
+	sb.Commit()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-time.After(defaultTxTimeout):
+		t.Fatal("funding timeout did not elapse")
+	}
+	require.True(t, channel.IsFundingTimeoutError(err), "funder should return FundingTimeoutError: %v", err)
+	for _, e := range errors.Cause(err).(channel.FundingTimeoutError).Errors {
+		assert.Equal(t, []channel.Index{faultyPeer}, e.TimedOutPeers)
+	}
+}
+
+func TestFunder_RetryLostDeposit(t *testing.T) {
+	// Waiting for a pending transaction polls its receipt every second.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*defaultTxTimeout)
+	defer cancel()
+	rng := pkgtest.Prng(t)
+
+	_, funders, params, alloc := newNFunders(ctx, t, rng, 1)
+	// The first transaction to each contract reaches the chain late and its
+	// sending seemingly fails. The first two event subscriptions, which include
+	// the one that waits for the deposits, fail.
+	funders[0].ContractInterface = &lossyContractInterface{
+		ContractInterface: funders[0].ContractInterface,
+		seen:              make(map[common.Address]bool),
+		pending:           make(map[common.Hash]*types.Transaction),
+		failSubs:          2,
+	}
+	funders[0].SetFundingRetry(3, time.Millisecond)
+	// The lost transactions advance the block time further.
+	funders[0].SetFundingTimeout(10 * params.ChallengeDuration)
+
+	req := channel.NewFundingReq(params, &channel.State{Allocation: *alloc}, 0, alloc.Balances)
+	require.NoError(t, funders[0].Fund(ctx, *req))
+	// The deposits were not sent a second time.
+	assert.NoError(t, compareOnChainAlloc(ctx, params, alloc.Balances, alloc.Assets, &funders[0].ContractBackend))
+}
+
+// lossyContractInterface reports the sending of the first transaction to each
+// address as failed and keeps it pending for a while before it sends it. It
+// also fails the first failSubs log subscriptions.
+type lossyContractInterface struct {
+	ethchannel.ContractInterface
+	mtx      sync.Mutex
+	seen     map[common.Address]bool
+	pending  map[common.Hash]*types.Transaction
+	failSubs int
+}
+
+func (c *lossyContractInterface) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.seen[*tx.To()] {
+		return c.ContractInterface.SendTransaction(ctx, tx)
+	}
+	c.seen[*tx.To()] = true
+	c.pending[tx.Hash()] = tx
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		delete(c.pending, tx.Hash())
+		if err := c.ContractInterface.SendTransaction(context.Background(), tx); err != nil {
+			panic(err)
+		}
+	}()
+	return errors.New("connection refused")
+}
+
+func (c *lossyContractInterface) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	c.mtx.Lock()
+	tx, ok := c.pending[hash]
+	c.mtx.Unlock()
+	if ok {
+		return tx, true, nil
+	}
+	return c.ContractInterface.TransactionByHash(ctx, hash)
+}
+
+func (c *lossyContractInterface) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, logs chan<- types.Log) (ethereum.Subscription, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.failSubs > 0 {
+		c.failSubs--
+		return nil, errors.New("connection refused")
+	}
+	return c.ContractInterface.SubscribeFilterLogs(ctx, q, logs)
+}
+
 func TestFunder_Fund_multi(t *testing.T) {
 	t.Run("1-party funding", func(t *testing.T) { testFunderFunding(t, 1) })
 	t.Run("2-party funding", func(t *testing.T) { testFunderFunding(t, 2) })
@@ -0,0 +1,125 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	cherrors "perun.network/go-perun/backend/ethereum/channel/errors"
+	"perun.network/go-perun/client"
+)
+
+// fundingRetry configures the retrying of funding steps that failed because
+// the blockchain was not reachable.
+type fundingRetry struct {
+	maxRetries int
+	backoff    time.Duration
+}
+
+// SetFundingRetry configures the Funder to retry the sending of funding
+// transactions, including the check whether the funding already happened, and
+// the reading of deposit events if they fail with a ChainNotReachableError.
+// Each is retried at most maxRetries times. The first retry happens after
+// backoff, which is doubled after every further attempt. Logical errors are
+// not retried. A maxRetries of zero, the default, disables retrying.
+//
+// Before a deposit is retried, the Funder waits for the transactions that it
+// already sent to be mined, so that a deposit whose sending seemingly failed
+// is not sent a second time.
+func (f *Funder) SetFundingRetry(maxRetries int, backoff time.Duration) {
+	if maxRetries < 0 || backoff < 0 {
+		f.log.Panic("funding retry parameters must not be negative")
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.retry = fundingRetry{maxRetries: maxRetries, backoff: backoff}
+}
+
+// do calls fn until it succeeds, fails with an error that is not a
+// ChainNotReachableError, or the retries are exhausted. It returns the last
+// error of fn or the context error if ctx is done while waiting.
+func (r fundingRetry) do(ctx context.Context, fn func() error) error {
+	backoff := r.backoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.maxRetries || !isChainNotReachableError(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return errors.WithMessagef(ctx.Err(), "waiting for retry (last error: %v)", err)
+		}
+		backoff *= 2
+	}
+}
+
+func isChainNotReachableError(err error) bool {
+	var chainErr client.ChainNotReachableError
+	return errors.As(err, &chainErr)
+}
+
+// txRecorder is a ContractInterface that records all transactions sent
+// through it, including those whose sending failed, because a transaction can
+// reach the node although the connection breaks before the node answers.
+type txRecorder struct {
+	ContractInterface
+	mtx sync.Mutex
+	txs types.Transactions
+}
+
+// SendTransaction records tx and sends it.
+func (r *txRecorder) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	r.mtx.Lock()
+	r.txs = append(r.txs, tx)
+	r.mtx.Unlock()
+	return r.ContractInterface.SendTransaction(ctx, tx)
+}
+
+// sent returns the transactions that were sent so far.
+func (r *txRecorder) sent() types.Transactions {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append(types.Transactions(nil), r.txs...)
+}
+
+// awaitSent waits for the given transactions of acc to be mined. Transactions
+// that the node does not know never reached it; their nonces are released.
+// Failed transactions are ignored because checkFunded decides whether they
+// need to be sent again.
+func (f *Funder) awaitSent(ctx context.Context, txs types.Transactions, acc accounts.Account) error {
+	for _, tx := range txs {
+		_, _, err := f.TransactionByHash(ctx, tx.Hash())
+		if errors.Is(err, ethereum.NotFound) {
+			f.releaseNonce(acc.Address, tx.Nonce())
+			continue
+		} else if err != nil {
+			err = cherrors.CheckIsChainNotReachableError(err)
+			return errors.WithMessage(err, "querying sent transaction")
+		}
+		if _, err := f.ConfirmTransaction(ctx, tx, acc); err != nil && !IsErrTxFailed(err) {
+			return errors.WithMessage(err, "waiting for sent transaction")
+		}
+	}
+	return nil
+}
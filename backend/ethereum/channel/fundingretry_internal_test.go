@@ -0,0 +1,77 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"perun.network/go-perun/client"
+)
+
+func TestFundingRetry(t *testing.T) {
+	ctx := context.Background()
+	chainErr := client.NewChainNotReachableError(errors.New("connection refused"))
+	r := fundingRetry{maxRetries: 2, backoff: time.Millisecond}
+
+	failing := func(errs ...error) (func() error, *int) {
+		var calls int
+		return func() error {
+			calls++
+			if calls <= len(errs) {
+				return errs[calls-1]
+			}
+			return nil
+		}, &calls
+	}
+
+	t.Run("success after retry", func(t *testing.T) {
+		fn, calls := failing(chainErr, chainErr)
+		assert.NoError(t, r.do(ctx, fn))
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		fn, calls := failing(chainErr, chainErr, chainErr)
+		assert.True(t, isChainNotReachableError(r.do(ctx, fn)))
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("logical error", func(t *testing.T) {
+		logicalErr := errors.New("insufficient balance")
+		fn, calls := failing(logicalErr)
+		assert.Equal(t, logicalErr, r.do(ctx, fn))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		fn, calls := failing(chainErr)
+		assert.Error(t, fundingRetry{}.do(ctx, fn))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		fn, calls := failing(chainErr)
+		err := fundingRetry{maxRetries: 1, backoff: time.Hour}.do(ctx, fn)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, 1, *calls)
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// GasLimits configures the gas limits of the transactions sent by the
+// Adjudicator. A zero limit means GasLimit.
+type GasLimits struct {
+	Register      uint64
+	Progress      uint64
+	ConcludeFinal uint64
+	Conclude      uint64
+	Withdraw      uint64
+
+	// Estimate makes the Adjudicator send transactions with their estimated
+	// gas plus EstimateMargin instead of the full limit of their operation.
+	// The limit of the operation then acts as an upper bound. If the
+	// estimation fails, the full limit is used.
+	Estimate bool
+	// EstimateMargin is the safety margin added to estimates as a fraction
+	// of the estimate, e.g., 0.2 adds 20%.
+	EstimateMargin float64
+}
+
+// SetGasLimits sets the gas limits of the Adjudicator's transactions. By
+// default, all operations use GasLimit and no estimates.
+//
+// Should only be called before the Adjudicator is used.
+func (a *Adjudicator) SetGasLimits(limits GasLimits) {
+	if limits.EstimateMargin < 0 {
+		a.log.Panic("gas estimate margin must not be negative")
+	}
+	a.gasLimits = limits
+}
+
+// forType returns the gas limit of the given transaction type.
+func (l GasLimits) forType(txType OnChainTxType) uint64 {
+	var limit uint64
+	switch txType {
+	case Register:
+		limit = l.Register
+	case Progress:
+		limit = l.Progress
+	case ConcludeFinal:
+		limit = l.ConcludeFinal
+	case Conclude:
+		limit = l.Conclude
+	case Withdraw:
+		limit = l.Withdraw
+	}
+	if limit == 0 {
+		return GasLimit
+	}
+	return limit
+}
+
+// withMargin returns the estimate plus the safety margin, capped at limit.
+func (l GasLimits) withMargin(estimate, limit uint64) uint64 {
+	withMargin := math.Ceil(float64(estimate) * (1 + l.EstimateMargin))
+	if withMargin >= float64(limit) {
+		return limit
+	}
+	return uint64(withMargin)
+}
+
+// errDryRun is returned by the signer of a dry run to abort the transaction
+// before it is sent.
+var errDryRun = errors.New("dry run")
+
+// gasLimit returns the gas limit to send the transaction created by `fn`
+// with. It estimates the gas that is needed and returns an ErrGasLimitTooLow
+// if it exceeds the limit of txType. Nothing is sent to the chain.
+// If the estimation fails, e.g., because the call would revert, the limit of
+// txType is returned and the transaction is left to fail on-chain.
+func (a *Adjudicator) gasLimit(ctx context.Context, txType OnChainTxType, fn func(*bind.TransactOpts) (*types.Transaction, error)) (uint64, error) {
+	limit := a.gasLimits.forType(txType)
+	opts, err := a.tr.NewTransactor(a.txSender)
+	if err != nil {
+		return 0, errors.WithMessage(err, "creating transactor")
+	}
+	var rawTx *types.Transaction
+	opts.Context = ctx
+	opts.GasLimit = limit
+	opts.GasPrice = new(big.Int)
+	opts.Nonce = new(big.Int)
+	opts.Signer = func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		rawTx = tx
+		return nil, errDryRun
+	}
+	if _, err := fn(opts); !errors.Is(err, errDryRun) {
+		return 0, errors.WithMessage(err, "packing transaction")
+	}
+
+	needed, err := a.EstimateGas(ctx, ethereum.CallMsg{
+		From:  a.txSender.Address,
+		To:    rawTx.To(),
+		Value: rawTx.Value(),
+		Data:  rawTx.Data(),
+	})
+	if err != nil {
+		a.log.Warn("Estimating gas failed: ", err)
+		return limit, nil
+	}
+	if needed > limit {
+		return 0, errors.WithStack(ErrGasLimitTooLow{Needed: needed, Configured: limit})
+	}
+	if a.gasLimits.Estimate {
+		return a.gasLimits.withMargin(needed, limit), nil
+	}
+	return limit, nil
+}
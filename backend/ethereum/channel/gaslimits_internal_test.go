@@ -0,0 +1,32 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGasLimits(t *testing.T) {
+	l := GasLimits{Register: 2000000, Withdraw: 80000, EstimateMargin: 0.25}
+	assert.Equal(t, uint64(2000000), l.forType(Register))
+	assert.Equal(t, uint64(80000), l.forType(Withdraw))
+	assert.Equal(t, uint64(GasLimit), l.forType(Conclude), "zero limit should default to GasLimit")
+	assert.Equal(t, uint64(GasLimit), GasLimits{}.forType(Progress))
+
+	assert.Equal(t, uint64(125000), l.withMargin(100000, 2000000))
+	assert.Equal(t, uint64(2000000), l.withMargin(1900000, 2000000), "should be capped at limit")
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	ethwallet "perun.network/go-perun/backend/ethereum/wallet"
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestAdjudicator_SetGasLimits(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	params, state := channeltest.NewRandomParamsAndState(
+		rng,
+		channeltest.WithChallengeDuration(uint64(100*time.Second)),
+		channeltest.WithParts(s.Parts...),
+		channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+		channeltest.WithIsFinal(false),
+		channeltest.WithLedgerChannel(true),
+		channeltest.WithVirtualChannel(false),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+	req := channel.AdjudicatorReq{
+		Params: params,
+		Acc:    s.Accs[0],
+		Idx:    channel.Index(0),
+		Tx:     testSignState(t, s.Accs, params, state),
+	}
+	adj := s.Adjs[0]
+	var sent []*types.Transaction
+	adj.SetTxGuard(func(_ context.Context, _ ethchannel.OnChainTxType, tx *types.Transaction) error {
+		sent = append(sent, tx)
+		return nil
+	})
+
+	adj.SetGasLimits(ethchannel.GasLimits{Register: 30000})
+	err := adj.Register(ctx, req, nil)
+	assert.True(t, ethchannel.IsErrGasLimitTooLow(err), "expected ErrGasLimitTooLow, got %v", err)
+	assert.Empty(t, sent)
+
+	adj.SetGasLimits(ethchannel.GasLimits{Estimate: true, EstimateMargin: 0.2})
+	require.NoError(t, adj.Register(ctx, req, nil))
+	require.Len(t, sent, 1)
+	assert.Less(t, sent[0].Gas(), uint64(ethchannel.GasLimit), "gas should be estimated")
+}
+
+func TestAdjudicator_GasLimitTooLow(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	adj := s.Adjs[0]
+	var sent []*types.Transaction
+	adj.SetTxGuard(func(_ context.Context, _ ethchannel.OnChainTxType, tx *types.Transaction) error {
+		sent = append(sent, tx)
+		return nil
+	})
+	// The limits are far below what registering or concluding needs.
+	adj.SetGasLimits(ethchannel.GasLimits{Register: 21000, ConcludeFinal: 21000})
+	sender := ethwallet.AsEthAddr(s.Accs[0].Address())
+
+	for _, final := range []bool{false, true} {
+		params, state := channeltest.NewRandomParamsAndState(
+			rng,
+			channeltest.WithChallengeDuration(uint64(100*time.Second)),
+			channeltest.WithParts(s.Parts...),
+			channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+			channeltest.WithIsFinal(final),
+			channeltest.WithLedgerChannel(true),
+			channeltest.WithVirtualChannel(false),
+		)
+		req := channel.AdjudicatorReq{
+			Params: params,
+			Acc:    s.Accs[0],
+			Idx:    channel.Index(0),
+			Tx:     testSignState(t, s.Accs, params, state),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+		defer cancel()
+		nonce, err := adj.PendingNonceAt(ctx, sender)
+		require.NoError(t, err)
+
+		err = adj.Register(ctx, req, nil)
+		var tooLow ethchannel.ErrGasLimitTooLow
+		require.True(t, errors.As(err, &tooLow), "final: %t, expected ErrGasLimitTooLow, got %v", final, err)
+		assert.Equal(t, uint64(21000), tooLow.Configured)
+		assert.Greater(t, tooLow.Needed, tooLow.Configured)
+		assert.Empty(t, sent, "final: %t, no transaction should be signed", final)
+		newNonce, err := adj.PendingNonceAt(ctx, sender)
+		require.NoError(t, err)
+		assert.Equal(t, nonce, newNonce, "final: %t, no transaction should be sent", final)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/backend/ethereum/bindings/adjudicator"
+	"perun.network/go-perun/backend/ethereum/subscription"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+)
+
+// ProgressionHistory returns all progressions of the channel with the given
+// id, ordered by their position on the chain. Together with the registered
+// state, they can be used to reconstruct the on-chain history of an app
+// channel.
+//
+// All past events of the channel are read, starting at the genesis block.
+func (a *Adjudicator) ProgressionHistory(ctx context.Context, id channel.ID) ([]*channel.ProgressedEvent, error) {
+	sub, err := subscription.NewEventSubFrom(ctx, a.ContractBackend, a.bound, updateEventType(id), 0, a.pollInterval)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating event subscription")
+	}
+	defer sub.Close()
+
+	events := make(chan *subscription.Event, 10)
+	subErr := make(chan error, 1)
+	go func() {
+		defer close(events)
+		subErr <- sub.ReadPast(ctx, events)
+	}()
+
+	// Collect the progressions first, the same event may be read twice.
+	progressions := make(map[EventCursor]*adjudicator.AdjudicatorChannelUpdate)
+	for e := range events {
+		update, ok := e.Data.(*adjudicator.AdjudicatorChannelUpdate)
+		if !ok {
+			log.Panicf("unexpected event type: %T", e.Data)
+		}
+		if e.Log.Removed || update.Phase != phaseForceExec {
+			continue
+		}
+		update.Raw = e.Log
+		progressions[EventCursor{Block: e.Log.BlockNumber, LogIndex: e.Log.Index}] = update
+	}
+	if err := <-subErr; err != nil {
+		return nil, errors.WithMessage(err, "reading past events")
+	}
+
+	cursors := make([]EventCursor, 0, len(progressions))
+	for c := range progressions {
+		cursors = append(cursors, c)
+	}
+	sort.Slice(cursors, func(i, j int) bool { return cursors[i].Before(cursors[j]) })
+
+	history := make([]*channel.ProgressedEvent, len(cursors))
+	for i, c := range cursors {
+		e, err := a.convertEvent(ctx, progressions[c])
+		if err != nil {
+			return nil, errors.WithMessagef(err, "converting progression %d", i)
+		}
+		history[i] = e.(*channel.ProgressedEvent)
+	}
+	return history, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	ethwallet "perun.network/go-perun/backend/ethereum/wallet"
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestAdjudicator_ProgressionHistory(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*defaultTxTimeout)
+	defer cancel()
+
+	appAddr, err := ethchannel.DeployTrivialApp(ctx, *s.CB, s.TxSender.Account)
+	require.NoError(t, err)
+	app := channel.NewMockApp(ethwallet.AsWalletAddr(appAddr))
+	channel.RegisterApp(app)
+
+	params, state := channeltest.NewRandomParamsAndState(rng,
+		channeltest.WithChallengeDuration(60),
+		channeltest.WithParts(s.Parts...),
+		channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+		channeltest.WithApp(app),
+		channeltest.WithAppData(channel.NewMockOp(channel.OpValid)),
+		channeltest.WithNumLocked(0),
+		channeltest.WithIsFinal(false),
+		channeltest.WithLedgerChannel(true),
+		channeltest.WithVirtualChannel(false),
+	)
+	ct := pkgtest.NewConcurrent(t)
+	for i, funder := range s.Funders {
+		i, funder := i, funder
+		go ct.StageN("funding", len(s.Funders), func(rt pkgtest.ConcT) {
+			req := channel.NewFundingReq(params, state, channel.Index(i), state.Balances)
+			require.NoError(rt, funder.Fund(ctx, *req))
+		})
+	}
+	ct.Wait("funding")
+
+	// No progressions before the channel is registered.
+	adj := s.Adjs[0]
+	history, err := adj.ProgressionHistory(ctx, params.ID())
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	req := channel.AdjudicatorReq{
+		Params: params,
+		Acc:    s.Accs[0],
+		Idx:    0,
+		Tx:     testSignState(t, s.Accs, params, state),
+	}
+	sub, err := adj.Subscribe(ctx, params)
+	require.NoError(t, err)
+	defer sub.Close()
+	require.NoError(t, adj.Register(ctx, req, nil))
+	require.NoError(t, sub.Next().Timeout().Wait(ctx))
+
+	// Progress the channel once by each participant.
+	var states []*channel.State
+	for i := range s.Parts {
+		idx := channel.Index(i)
+		newState := req.Tx.State.Clone()
+		newState.Version++
+		newState.Balances[0][0], newState.Balances[0][1] = newState.Balances[0][1], newState.Balances[0][0]
+		sig, err := channel.Sign(s.Accs[i], params, newState)
+		require.NoError(t, err)
+
+		req.Acc, req.Idx = s.Accs[i], idx
+		require.NoError(t, s.Adjs[i].Progress(ctx, *channel.NewProgressReq(req, newState, sig)))
+		req.Tx = channel.Transaction{State: newState}
+		states = append(states, newState)
+	}
+
+	history, err = adj.ProgressionHistory(ctx, params.ID())
+	require.NoError(t, err)
+	require.Len(t, history, len(states))
+	for i, e := range history {
+		assert.Equal(t, params.ID(), e.ID())
+		assert.Equal(t, channel.Index(i), e.Idx)
+		assert.Equal(t, states[i].Version, e.Version())
+		assert.NoError(t, e.State.Allocation.Equal(&states[i].Allocation))
+		assert.Equal(t, states[i].Data, e.State.Data)
+	}
+
+	// Other channels have no progressions.
+	history, err = adj.ProgressionHistory(ctx, channeltest.NewRandomChannelID(rng))
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
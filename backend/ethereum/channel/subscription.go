@@ -21,8 +21,8 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/backend/ethereum/bindings"
@@ -35,23 +35,80 @@ import (
 
 // Subscribe returns a new AdjudicatorSubscription to adjudicator events.
 func (a *Adjudicator) Subscribe(ctx context.Context, params *channel.Params) (channel.AdjudicatorSubscription, error) {
+	return a.subscribe(ctx, params.ID())
+}
+
+// WaitForEvent waits for an adjudicator event of the channel with the given ID
+// that satisfies pred and returns it. A past event that the subscription
+// starts with is also considered. Note that events that are superseded before
+// they are read might be skipped, so pred should accept newer events too, like
+// "registered with version >= n". An error is returned if ctx is done or the
+// subscription fails before a matching event occurs.
+func (a *Adjudicator) WaitForEvent(ctx context.Context, id channel.ID, pred func(channel.AdjudicatorEvent) bool) (channel.AdjudicatorEvent, error) {
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sub, err := a.subscribe(subCtx, id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "subscribing")
+	}
+	defer sub.Close()
+
+	for e := sub.Next(); e != nil; e = sub.Next() {
+		if pred(e) {
+			return e, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), "waiting for event")
+	}
+	if err := sub.Err(); err != nil {
+		return nil, errors.WithMessage(err, "subscription closed")
+	}
+	return nil, errors.New("subscription closed")
+}
+
+// SubscribeFrom is like Subscribe, but reads past events starting at the
+// given block instead of the last startBlockOffset blocks. It can be used to
+// resume watching a channel without missing events, see EventPosition.
+func (a *Adjudicator) SubscribeFrom(ctx context.Context, params *channel.Params, block uint64) (channel.AdjudicatorSubscription, error) {
+	return a.subscribeWith(ctx, params.ID(), func(eFact subscription.EventFactory) (*subscription.EventSub, error) {
+		return subscription.NewEventSubFrom(ctx, a.ContractBackend, a.bound, eFact, block, a.pollInterval)
+	})
+}
+
+// EventPosition returns the number of the block that contains the given event
+// of this backend and the index of its log in the block. It returns false if
+// the event does not stem from this backend.
+func (a *Adjudicator) EventPosition(e channel.AdjudicatorEvent) (block uint64, index uint, ok bool) {
+	l, ok := EventLog(e)
+	if !ok {
+		return 0, 0, false
+	}
+	return l.BlockNumber, l.Index, true
+}
+
+func (a *Adjudicator) subscribe(ctx context.Context, id channel.ID) (*RegisteredSub, error) {
+	return a.subscribeWith(ctx, id, func(eFact subscription.EventFactory) (*subscription.EventSub, error) {
+		return a.newEventSub(ctx, a.bound, eFact)
+	})
+}
+
+// subscribeWith subscribes to the events of the channel with the given ID,
+// using newSub to create the underlying event subscription.
+func (a *Adjudicator) subscribeWith(ctx context.Context, id channel.ID, newSub func(subscription.EventFactory) (*subscription.EventSub, error)) (*RegisteredSub, error) {
 	subErr := make(chan error, 1)
 	events := make(chan *subscription.Event, 10)
 	eFact := func() *subscription.Event {
 		return &subscription.Event{
 			Name:   bindings.Events.AdjChannelUpdate,
 			Data:   new(adjudicator.AdjudicatorChannelUpdate),
-			Filter: [][]interface{}{{params.ID()}},
+			Filter: [][]interface{}{{id}},
 		}
 	}
-	sub, err := subscription.NewEventSub(ctx, a.ContractBackend, a.bound, eFact, startBlockOffset)
+	sub, err := newSub(eFact)
 	if err != nil {
 		return nil, errors.WithMessage(err, "creating filter-watch event subscription")
 	}
-	// Find new events
-	go func() {
-		subErr <- sub.Read(ctx, events)
-	}()
 	rsub := &RegisteredSub{
 		cr:     a.ContractInterface,
 		sub:    sub,
@@ -59,7 +116,16 @@ func (a *Adjudicator) Subscribe(ctx context.Context, params *channel.Params) (ch
 		next:   make(chan channel.AdjudicatorEvent, 1),
 		err:    make(chan error, 1),
 	}
-	go rsub.updateNext(ctx, events, a)
+	rsub.wg.Add(2)
+	// Find new events
+	go func() {
+		defer rsub.wg.Done()
+		subErr <- sub.Read(ctx, events)
+	}()
+	go func() {
+		defer rsub.wg.Done()
+		rsub.updateNext(ctx, events, a)
+	}()
 
 	return rsub, nil
 }
@@ -72,6 +138,7 @@ type RegisteredSub struct {
 	next   chan channel.AdjudicatorEvent // Event sink
 	err    chan error                    // error from subscription
 	closed sync.Once
+	wg     sync.WaitGroup // waits for the reader and updateNext goroutines
 }
 
 func (r *RegisteredSub) updateNext(ctx context.Context, events chan *subscription.Event, a *Adjudicator) {
@@ -113,9 +180,8 @@ func (r *RegisteredSub) processNext(ctx context.Context, a *Adjudicator, _next *
 	select {
 	// drain next-channel on new event
 	case current := <-r.next:
-		currentTimeout := current.Timeout().(*BlockTimeout)
 		// if newer version or same version and newer timeout, replace
-		if current.Version() < next.Version || current.Version() == next.Version && currentTimeout.Time < next.Timeout {
+		if supersedes(next, current) {
 			var e channel.AdjudicatorEvent
 			e, err = a.convertEvent(ctx, next)
 			if err != nil {
@@ -138,6 +204,23 @@ func (r *RegisteredSub) processNext(ctx context.Context, a *Adjudicator, _next *
 	return
 }
 
+// supersedes returns whether the event next replaces the buffered event
+// current, i.e., whether it has a newer version or the same version and a
+// later timeout. Timeouts are compared as block timestamps, which are unix
+// times and thus also comparable between different chains, unlike block
+// numbers. A buffered event with a timeout of another type is always
+// replaced because its expiry cannot be compared.
+func supersedes(next *adjudicator.AdjudicatorChannelUpdate, current channel.AdjudicatorEvent) bool {
+	if current.Version() != next.Version {
+		return current.Version() < next.Version
+	}
+	currentTimeout, ok := current.Timeout().(*BlockTimeout)
+	if !ok {
+		return true
+	}
+	return currentTimeout.Time < next.Timeout
+}
+
 // Next returns the newest past or next blockchain event.
 // It blocks until an event is returned from the blockchain or the subscription
 // is closed. If the subscription is closed, Next immediately returns nil.
@@ -152,8 +235,10 @@ func (r *RegisteredSub) Next() channel.AdjudicatorEvent {
 }
 
 // Close closes this subscription. Any pending calls to Next will return nil.
+// It blocks until all goroutines of the subscription have returned.
 func (r *RegisteredSub) Close() error {
 	r.closed.Do(r.sub.Close)
+	r.wg.Wait()
 	return nil
 }
 
@@ -163,8 +248,23 @@ func (r *RegisteredSub) Err() error {
 	return <-r.err
 }
 
+// EventLog returns the on-chain log from which an adjudicator event of this
+// backend was decoded. It contains, e.g., the block number and the transaction
+// hash of the event. The second return value is false if the event does not
+// stem from this backend.
+func EventLog(e channel.AdjudicatorEvent) (*types.Log, bool) {
+	src, ok := e.(interface{ Source() interface{} })
+	if !ok {
+		return nil, false
+	}
+	l, ok := src.Source().(*types.Log)
+	return l, ok
+}
+
 func (a *Adjudicator) convertEvent(ctx context.Context, e *adjudicator.AdjudicatorChannelUpdate) (channel.AdjudicatorEvent, error) {
 	base := channel.NewAdjudicatorEventBase(e.ChannelID, NewBlockTimeout(a.ContractInterface, e.Timeout), e.Version)
+	raw := e.Raw
+	base.SourceV = &raw
 	switch e.Phase {
 	case phaseDispute:
 		args, err := a.fetchRegisterCallData(ctx, e.Raw.TxHash)
@@ -229,7 +329,7 @@ type progressCallData struct {
 
 func (a *Adjudicator) fetchProgressCallData(ctx context.Context, txHash common.Hash) (*progressCallData, error) {
 	var args progressCallData
-	err := a.fetchCallData(ctx, txHash, abiProgress, &args)
+	err := a.fetchCallData(ctx, txHash, "progress", &args)
 	return &args, errors.WithMessage(err, "fetching call data")
 }
 
@@ -253,17 +353,21 @@ func (args *registerCallData) get(id channel.ID) (*adjudicator.AdjudicatorSigned
 
 func (a *Adjudicator) fetchRegisterCallData(ctx context.Context, txHash common.Hash) (*registerCallData, error) {
 	var args registerCallData
-	err := a.fetchCallData(ctx, txHash, abiRegister, &args)
+	err := a.fetchCallData(ctx, txHash, "register", &args)
 	return &args, errors.WithMessage(err, "fetching call data")
 }
 
-func (a *Adjudicator) fetchCallData(ctx context.Context, txHash common.Hash, method abi.Method, args interface{}) error {
+func (a *Adjudicator) fetchCallData(ctx context.Context, txHash common.Hash, methodName string, args interface{}) error {
 	tx, _, err := a.ContractBackend.TransactionByHash(ctx, txHash)
 	if err != nil {
 		err = cherrors.CheckIsChainNotReachableError(err)
 		return errors.WithMessage(err, "getting transaction")
 	}
 
+	method, err := a.callDataMethod(methodName, tx.Data())
+	if err != nil {
+		return err
+	}
 	argsData := tx.Data()[len(method.ID):]
 
 	argsI, err := method.Inputs.UnpackValues(argsData)
@@ -0,0 +1,45 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"perun.network/go-perun/backend/ethereum/bindings/adjudicator"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestSupersedes(t *testing.T) {
+	rng := test.Prng(t)
+	id := channel.ID{}
+	rng.Read(id[:])
+
+	current := channel.NewAdjudicatorEventBase(id, NewBlockTimeout(nil, 100), 5)
+	update := func(version, timeout uint64) *adjudicator.AdjudicatorChannelUpdate {
+		return &adjudicator.AdjudicatorChannelUpdate{ChannelID: id, Version: version, Timeout: timeout}
+	}
+
+	assert.True(t, supersedes(update(6, 50), current), "newer version")
+	assert.False(t, supersedes(update(4, 200), current), "older version")
+	assert.True(t, supersedes(update(5, 101), current), "same version, later timeout")
+	assert.False(t, supersedes(update(5, 100), current), "same version, same timeout")
+	assert.False(t, supersedes(update(5, 99), current), "same version, earlier timeout")
+
+	foreign := channel.NewAdjudicatorEventBase(id, new(channel.ElapsedTimeout), 5)
+	assert.True(t, supersedes(update(5, 0), foreign), "incomparable timeout")
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/client"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+var _ client.ResumableAdjudicator = (*ethchannel.Adjudicator)(nil)
+
+func TestAdjudicator_SubscribeFrom(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	params, state := channeltest.NewRandomParamsAndState(
+		rng,
+		channeltest.WithChallengeDuration(uint64(100*time.Second)),
+		channeltest.WithParts(s.Parts...),
+		channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+		channeltest.WithIsFinal(false),
+		channeltest.WithLedgerChannel(true),
+		channeltest.WithVirtualChannel(false),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+	adj := s.Adjs[0]
+
+	req := channel.AdjudicatorReq{
+		Params: params,
+		Acc:    s.Accs[0],
+		Idx:    channel.Index(0),
+		Tx:     testSignState(t, s.Accs, params, state),
+	}
+	require.NoError(t, adj.Register(ctx, req, nil))
+	registered, err := adj.WaitForEvent(ctx, params.ID(), func(channel.AdjudicatorEvent) bool { return true })
+	require.NoError(t, err)
+	block, _, ok := adj.EventPosition(registered)
+	require.True(t, ok)
+	_, _, ok = adj.EventPosition(channel.NewConcludedEvent(params.ID(), new(channel.ElapsedTimeout), 0))
+	assert.False(t, ok, "EventPosition of foreign event")
+
+	// Mine enough blocks that a default subscription misses the event.
+	for i := 0; i < 200; i++ {
+		s.SimBackend.Commit()
+	}
+
+	subCtx, subCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer subCancel()
+	sub, err := adj.Subscribe(subCtx, params)
+	require.NoError(t, err)
+	assert.Nil(t, sub.Next(), "default subscription should miss the event")
+	require.NoError(t, sub.Close())
+
+	sub, err = adj.SubscribeFrom(ctx, params, block)
+	require.NoError(t, err)
+	defer sub.Close()
+	e := sub.Next()
+	require.IsType(t, new(channel.RegisteredEvent), e)
+	assert.Equal(t, state.Version, e.Version())
+}
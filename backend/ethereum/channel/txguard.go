@@ -0,0 +1,61 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// TxGuard is consulted by the Adjudicator with every signed transaction right
+// before it is sent. If it returns an error, the transaction is not sent and
+// the error is returned by the Adjudicator call.
+type TxGuard func(ctx context.Context, txType OnChainTxType, tx *types.Transaction) error
+
+// SetTxGuard sets the guard that is consulted before every Register, Progress,
+// Conclude, ConcludeFinal and Withdraw transaction of the Adjudicator is sent.
+// It can be used to inspect, rate-limit or veto transactions. Passing nil
+// removes the guard.
+func (a *Adjudicator) SetTxGuard(guard TxGuard) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.txGuard = guard
+}
+
+// guardTransactor makes opts consult the tx guard, if set, with the signed
+// transaction before it is sent. Must be called while holding a.mu.
+func (a *Adjudicator) guardTransactor(ctx context.Context, opts *bind.TransactOpts, txType OnChainTxType) {
+	guard := a.txGuard
+	if guard == nil {
+		return
+	}
+	signer := opts.Signer
+	opts.Signer = func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		signed, err := signer(addr, tx)
+		if err != nil {
+			return nil, err
+		}
+		if err := guard(ctx, txType, signed); err != nil {
+			// The transaction is not sent, so its nonce can be reused.
+			a.releaseNonce(addr, signed.Nonce())
+			return nil, errors.WithMessagef(err, "%v transaction vetoed by guard", txType)
+		}
+		return signed, nil
+	}
+}
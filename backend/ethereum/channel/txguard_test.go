@@ -0,0 +1,71 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestAdjudicator_SetTxGuard(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	params, state := channeltest.NewRandomParamsAndState(
+		rng,
+		channeltest.WithChallengeDuration(uint64(100*time.Second)),
+		channeltest.WithParts(s.Parts...),
+		channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+		channeltest.WithIsFinal(false),
+		channeltest.WithLedgerChannel(true),
+		channeltest.WithVirtualChannel(false),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+	req := channel.AdjudicatorReq{
+		Params: params,
+		Acc:    s.Accs[0],
+		Idx:    channel.Index(0),
+		Tx:     testSignState(t, s.Accs, params, state),
+	}
+	adj := s.Adjs[0]
+
+	errVeto := errors.New("vetoed")
+	var seen []ethchannel.OnChainTxType
+	adj.SetTxGuard(func(_ context.Context, txType ethchannel.OnChainTxType, tx *types.Transaction) error {
+		require.NotNil(t, tx)
+		seen = append(seen, txType)
+		return errVeto
+	})
+	err := adj.Register(ctx, req, nil)
+	assert.True(t, errors.Is(err, errVeto), "expected guard error, got %v", err)
+	assert.Equal(t, []ethchannel.OnChainTxType{ethchannel.Register}, seen)
+
+	adj.SetTxGuard(func(context.Context, ethchannel.OnChainTxType, *types.Transaction) error {
+		return nil
+	})
+	assert.NoError(t, adj.Register(ctx, req, nil), "approved transaction should be sent")
+}
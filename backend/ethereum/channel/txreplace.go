@@ -0,0 +1,182 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	cherrors "perun.network/go-perun/backend/ethereum/channel/errors"
+	"perun.network/go-perun/log"
+	pcontext "perun.network/go-perun/pkg/context"
+)
+
+// txReplacementPollInterval is the interval in which the receipts of a
+// replaced transaction and its replacements are queried. It matches the
+// interval of bind.WaitMined.
+const txReplacementPollInterval = time.Second
+
+// txReplacement configures the replacement of adjudicator transactions that
+// are not mined in time.
+type txReplacement struct {
+	maxAttempts    int
+	attemptTimeout time.Duration
+	bumpFactor     float64
+}
+
+// SetTxReplacement configures the Adjudicator to replace transactions that
+// were not mined within attemptTimeout. The replacement has the same nonce
+// and calldata as the replaced transaction but its gas price multiplied by
+// bumpFactor. A transaction is replaced at most maxAttempts times before a
+// TxTimedoutError is returned. Until then, the Adjudicator waits for any of
+// the sent transactions to be mined. A maxAttempts of zero, the default,
+// disables replacing. This applies to all transactions of the Adjudicator,
+// including the withdrawals of Withdraw.
+//
+// Geth only accepts replacements that raise the gas price by at least 10%, so
+// bumpFactor should be at least 1.1, e.g., 1.125.
+//
+// Should only be called before the Adjudicator is used.
+func (a *Adjudicator) SetTxReplacement(maxAttempts int, attemptTimeout time.Duration, bumpFactor float64) {
+	if maxAttempts < 0 {
+		a.log.Panic("max replacement attempts must not be negative")
+	}
+	if maxAttempts > 0 && (attemptTimeout <= 0 || bumpFactor <= 1) {
+		a.log.Panic("replacement timeout must be positive and bump factor greater than one")
+	}
+	a.replacement = txReplacement{
+		maxAttempts:    maxAttempts,
+		attemptTimeout: attemptTimeout,
+		bumpFactor:     bumpFactor,
+	}
+}
+
+// bump returns the gas price multiplied by the bump factor, rounded up. It is
+// always greater than the given price.
+func (r txReplacement) bump(price *big.Int) *big.Int {
+	f := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(r.bumpFactor))
+	bumped, acc := f.Int(nil)
+	if acc == big.Below {
+		bumped.Add(bumped, big.NewInt(1))
+	}
+	if bumped.Cmp(price) <= 0 {
+		bumped.Add(price, big.NewInt(1))
+	}
+	return bumped
+}
+
+// confirmReplacing waits for tx or one of its replacements to be mined. Every
+// time an attempt times out, the last sent transaction is replaced by one with
+// a bumped gas price. It returns the mined transaction or, on error, the last
+// sent one.
+func (a *Adjudicator) confirmReplacing(ctx context.Context, tx *types.Transaction, txType OnChainTxType) (*types.Transaction, error) {
+	sent := []*types.Transaction{tx}
+	for attempt := 0; ; attempt++ {
+		last := sent[len(sent)-1]
+		attemptCtx, cancel := context.WithTimeout(ctx, a.replacement.attemptTimeout)
+		mined, err := a.waitMinedAny(attemptCtx, sent)
+		cancel()
+		if err == nil {
+			_, err := a.ConfirmTransaction(ctx, mined, a.txSender)
+			return mined, err
+		}
+		if ctx.Err() != nil || attempt >= a.replacement.maxAttempts {
+			return last, errors.WithMessage(errors.WithStack(errTxTimedOut), "sending transaction")
+		}
+
+		replacement, err := a.replace(ctx, last, txType)
+		if err != nil {
+			// One of the sent transactions might have been mined after the
+			// last poll, which also makes sending the replacement fail.
+			if mined := a.minedAny(ctx, sent); mined != nil {
+				_, err := a.ConfirmTransaction(ctx, mined, a.txSender)
+				return mined, err
+			}
+			return last, errors.WithMessage(err, "replacing transaction")
+		}
+		sent = append(sent, replacement)
+	}
+}
+
+// waitMinedAny waits until one of the given transactions is mined and returns
+// it. It only returns an error if ctx is done.
+func (a *Adjudicator) waitMinedAny(ctx context.Context, txs []*types.Transaction) (*types.Transaction, error) {
+	ticker := time.NewTicker(txReplacementPollInterval)
+	defer ticker.Stop()
+	for {
+		if mined := a.minedAny(ctx, txs); mined != nil {
+			return mined, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// minedAny queries the receipts of the given transactions once and returns the
+// first one that is mined, or nil if none is.
+func (a *Adjudicator) minedAny(ctx context.Context, txs []*types.Transaction) *types.Transaction {
+	for _, tx := range txs {
+		receipt, err := a.TransactionReceipt(ctx, tx.Hash())
+		if receipt != nil {
+			return tx
+		}
+		if err != nil && !pcontext.IsContextError(err) {
+			log.Trace("Receipt retrieval failed: ", err)
+		}
+	}
+	return nil
+}
+
+// replace sends a transaction with the same nonce and calldata as tx but a
+// bumped gas price. The tx guard, if set, is consulted before sending.
+func (a *Adjudicator) replace(ctx context.Context, tx *types.Transaction, txType OnChainTxType) (*types.Transaction, error) {
+	if !a.mu.TryLockCtx(ctx) {
+		return nil, errors.Wrap(ctx.Err(), "context canceled while acquiring tx lock")
+	}
+	defer a.mu.Unlock()
+
+	trans, err := a.tr.NewTransactor(a.txSender)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transactor")
+	}
+	gasPrice := a.replacement.bump(tx.GasPrice())
+	unsigned := types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
+	signed, err := trans.Signer(a.txSender.Address, unsigned)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing replacement")
+	}
+	// The nonce of a vetoed replacement must not be released because the
+	// replaced transaction might still be mined.
+	if a.txGuard != nil {
+		if err := a.txGuard(ctx, txType, signed); err != nil {
+			return nil, errors.WithMessagef(err, "%v replacement vetoed by guard", txType)
+		}
+	}
+	if err := a.SendTransaction(ctx, signed); err != nil {
+		err = cherrors.CheckIsChainNotReachableError(err)
+		return nil, errors.WithMessage(err, "sending replacement")
+	}
+	log.WithFields(log.Fields{"replaced": tx.Hash().Hex(), "gasPrice": gasPrice}).
+		Debugf("Sent replacement transaction %v", signed.Hash().Hex())
+	return signed, nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxReplacement_Bump(t *testing.T) {
+	r := txReplacement{bumpFactor: 1.125}
+	assert.Equal(t, big.NewInt(1125), r.bump(big.NewInt(1000)))
+	assert.Equal(t, big.NewInt(1127), r.bump(big.NewInt(1001)), "should round up")
+	assert.Equal(t, big.NewInt(2), r.bump(big.NewInt(1)))
+	assert.Equal(t, big.NewInt(1), r.bump(big.NewInt(0)), "should always increase")
+}
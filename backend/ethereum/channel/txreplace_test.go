@@ -0,0 +1,210 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/backend/ethereum/bindings/assetholder"
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	"perun.network/go-perun/backend/ethereum/wallet/keystore"
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/client"
+	pkgtest "perun.network/go-perun/pkg/test"
+	wallettest "perun.network/go-perun/wallet/test"
+)
+
+// droppingBackend silently drops the first `drop` sent transactions, as if
+// they were stuck in the mempool because of a too low gas price.
+type droppingBackend struct {
+	*test.SimulatedBackend
+	mu   sync.Mutex
+	drop int
+}
+
+func (b *droppingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.drop > 0 {
+		b.drop--
+		return nil
+	}
+	return b.SimulatedBackend.SendTransaction(ctx, tx)
+}
+
+// lateBackend holds back the first sent transaction and only sends it when
+// it gets replaced, as if it was mined just before the replacement. The
+// replacement is rejected like geth rejects a reused nonce.
+type lateBackend struct {
+	*test.SimulatedBackend
+	mu   sync.Mutex
+	held *types.Transaction
+}
+
+func (b *lateBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.held == nil {
+		b.held = tx
+		return nil
+	}
+	if err := b.SimulatedBackend.SendTransaction(ctx, b.held); err != nil {
+		return err
+	}
+	return errors.New("nonce too low")
+}
+
+func TestAdjudicator_SetTxReplacement(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	params, state := channeltest.NewRandomParamsAndState(
+		rng,
+		channeltest.WithChallengeDuration(uint64(100*time.Second)),
+		channeltest.WithParts(s.Parts...),
+		channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+		channeltest.WithIsFinal(false),
+		channeltest.WithLedgerChannel(true),
+		channeltest.WithVirtualChannel(false),
+	)
+	req := channel.AdjudicatorReq{
+		Params: params,
+		Acc:    s.Accs[0],
+		Idx:    channel.Index(0),
+		Tx:     testSignState(t, s.Accs, params, state),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+	adjAddr, err := ethchannel.DeployAdjudicator(ctx, *s.CB, s.TxSender.Account)
+	require.NoError(t, err)
+	// newAdjWithBackend returns an adjudicator at addr that sends its
+	// transactions over backend and records all sent transactions.
+	newAdjWithBackend := func(addr common.Address, backend ethchannel.ContractInterface) (*ethchannel.Adjudicator, *[]*types.Transaction) {
+		tr := keystore.NewTransactor(*wallettest.RandomWallet().(*keystore.Wallet), types.NewEIP155Signer(big.NewInt(1337)))
+		cb := ethchannel.NewContractBackend(backend, tr)
+		adj := ethchannel.NewAdjudicator(cb, addr, common.Address{}, s.Accs[0].Account)
+		var sent []*types.Transaction
+		adj.SetTxGuard(func(_ context.Context, _ ethchannel.OnChainTxType, tx *types.Transaction) error {
+			sent = append(sent, tx)
+			return nil
+		})
+		return adj, &sent
+	}
+	// newAdj returns an adjudicator at addr whose first transaction gets
+	// dropped and that records all sent transactions.
+	newAdj := func(addr common.Address) (*ethchannel.Adjudicator, *[]*types.Transaction) {
+		return newAdjWithBackend(addr, &droppingBackend{SimulatedBackend: s.SimBackend, drop: 1})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		adj, sent := newAdj(adjAddr)
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		err := adj.Register(ctx, req, nil)
+		assert.True(t, errors.As(err, new(client.TxTimedoutError)), "expected TxTimedoutError, got %v", err)
+		assert.Len(t, *sent, 1)
+	})
+
+	t.Run("replaced", func(t *testing.T) {
+		adj, sent := newAdj(adjAddr)
+		adj.SetTxReplacement(2, 200*time.Millisecond, 1.125)
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+		defer cancel()
+		require.NoError(t, adj.Register(ctx, req, nil))
+
+		require.Len(t, *sent, 2)
+		orig, repl := (*sent)[0], (*sent)[1]
+		assert.Equal(t, orig.Nonce(), repl.Nonce())
+		assert.Equal(t, orig.Data(), repl.Data())
+		assert.Equal(t, 1, repl.GasPrice().Cmp(orig.GasPrice()), "gas price should be bumped")
+		receipt, err := s.SimBackend.TransactionReceipt(ctx, repl.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	})
+
+	t.Run("mined while replacing", func(t *testing.T) {
+		adj, sent := newAdjWithBackend(adjAddr, &lateBackend{SimulatedBackend: s.SimBackend})
+		adj.SetTxReplacement(2, 200*time.Millisecond, 1.125)
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+		defer cancel()
+		require.NoError(t, adj.Register(ctx, req, nil))
+
+		require.Len(t, *sent, 2)
+		receipt, err := s.SimBackend.TransactionReceipt(ctx, (*sent)[0].Hash())
+		require.NoError(t, err)
+		assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		adj, sent := newAdj(adjAddr)
+		adj.ContractInterface.(*droppingBackend).drop = 3
+		adj.SetTxReplacement(2, 100*time.Millisecond, 1.125)
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+		defer cancel()
+		err := adj.Register(ctx, req, nil)
+		assert.True(t, errors.As(err, new(client.TxTimedoutError)), "expected TxTimedoutError, got %v", err)
+		assert.Len(t, *sent, 3)
+	})
+
+	t.Run("withdrawal replaced", func(t *testing.T) {
+		params, state := channeltest.NewRandomParamsAndState(
+			rng,
+			channeltest.WithParts(s.Parts...),
+			channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+			channeltest.WithIsFinal(true),
+			channeltest.WithLedgerChannel(true),
+			channeltest.WithVirtualChannel(false),
+		)
+		req := channel.AdjudicatorReq{
+			Params: params,
+			Acc:    s.Accs[0],
+			Idx:    channel.Index(0),
+			Tx:     testSignState(t, s.Accs, params, state),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+		defer cancel()
+		require.NoError(t, s.Funders[0].Fund(ctx, *channel.NewFundingReq(params, state, 0, state.Balances)))
+		require.NoError(t, s.Adjs[0].Register(ctx, req, nil), "concluding")
+
+		// The asset holder only accepts withdrawals concluded on its own
+		// adjudicator.
+		ah, err := assetholder.NewAssetHolderCaller(common.Address(s.Asset), s.CB)
+		require.NoError(t, err)
+		setupAdjAddr, err := ah.Adjudicator(&bind.CallOpts{Context: ctx})
+		require.NoError(t, err)
+		adj, sent := newAdj(setupAdjAddr)
+		adj.SetTxReplacement(2, 200*time.Millisecond, 1.125)
+		require.NoError(t, adj.Withdraw(ctx, req, nil))
+
+		require.Len(t, *sent, 2)
+		orig, repl := (*sent)[0], (*sent)[1]
+		assert.Equal(t, orig.Nonce(), repl.Nonce())
+		assert.Equal(t, 1, repl.GasPrice().Cmp(orig.GasPrice()), "gas price should be bumped")
+		assertHoldingsZero(ctx, t, s.CB, params, state.Assets)
+	})
+}
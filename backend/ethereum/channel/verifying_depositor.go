@@ -0,0 +1,117 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/backend/ethereum/bindings/assetholder"
+	cherrors "perun.network/go-perun/backend/ethereum/channel/errors"
+	"perun.network/go-perun/channel"
+	perunwallet "perun.network/go-perun/wallet"
+)
+
+type (
+	// VerifyingDepositor wraps a Depositor and verifies that the requested
+	// amount actually arrived at the AssetHolder. This is needed for tokens
+	// that transfer less than the sent amount, like fee-on-transfer tokens.
+	//
+	// After the deposit transactions of the wrapped Depositor are mined, the
+	// holdings of the funding ID are read from the AssetHolder. If they are
+	// less than the requested amount, the difference is deposited again, at
+	// most MaxTopUps times. If the holdings are still short, Deposit fails with
+	// an ErrDepositShort.
+	VerifyingDepositor struct {
+		Depositor
+		MaxTopUps int
+	}
+
+	// ErrDepositShort is returned by a VerifyingDepositor if less than the
+	// requested amount arrived at the AssetHolder.
+	ErrDepositShort struct {
+		Requested *big.Int // Requested is the requested deposit amount.
+		Holdings  *big.Int // Holdings are the on-chain holdings of the funding ID.
+	}
+)
+
+// NewVerifyingDepositor returns a new VerifyingDepositor that wraps d and tops
+// up short deposits at most maxTopUps times.
+func NewVerifyingDepositor(d Depositor, maxTopUps int) *VerifyingDepositor {
+	return &VerifyingDepositor{Depositor: d, MaxTopUps: maxTopUps}
+}
+
+// Deposit deposits the requested amount with the wrapped Depositor, waits for
+// the transactions to be mined and verifies the on-chain holdings. The
+// returned transactions are already mined.
+func (d *VerifyingDepositor) Deposit(ctx context.Context, req DepositReq) (types.Transactions, error) {
+	contract, err := assetholder.NewAssetHolder(common.Address(req.Asset), req.CB)
+	if err != nil {
+		return nil, errors.Wrapf(err, "binding AssetHolder contract at: %x", req.Asset)
+	}
+
+	var allTxs types.Transactions
+	topUp := req
+	for i := 0; ; i++ {
+		txs, err := d.Depositor.Deposit(ctx, topUp)
+		allTxs = append(allTxs, txs...)
+		if err != nil {
+			return allTxs, err
+		}
+		for _, tx := range txs {
+			if _, err := req.CB.ConfirmTransaction(ctx, tx, req.Account); err != nil {
+				return allTxs, errors.WithMessage(err, "confirming deposit transaction")
+			}
+		}
+
+		holdings, err := contract.Holdings(&bind.CallOpts{Context: ctx}, req.FundingID)
+		if err != nil {
+			err = cherrors.CheckIsChainNotReachableError(err)
+			return allTxs, errors.WithMessage(err, "reading holdings")
+		}
+		if holdings.Cmp(req.Balance) >= 0 {
+			return allTxs, nil
+		}
+		if i >= d.MaxTopUps {
+			return allTxs, errors.WithStack(ErrDepositShort{Requested: req.Balance, Holdings: holdings})
+		}
+		topUp.Balance = new(big.Int).Sub(req.Balance, holdings)
+	}
+}
+
+// FundingIDs derives the funding IDs with the wrapped Depositor if it is a
+// FundingIDDeriver and with the standard derivation otherwise.
+func (d *VerifyingDepositor) FundingIDs(channelID channel.ID, participants ...perunwallet.Address) [][32]byte {
+	if fd, ok := d.Depositor.(FundingIDDeriver); ok {
+		return fd.FundingIDs(channelID, participants...)
+	}
+	return FundingIDs(channelID, participants...)
+}
+
+func (e ErrDepositShort) Error() string {
+	return fmt.Sprintf("deposit short: requested %v, holdings %v", e.Requested, e.Holdings)
+}
+
+// IsErrDepositShort returns whether the cause of the error was a short
+// deposit.
+func IsErrDepositShort(err error) bool {
+	return errors.As(err, new(ErrDepositShort))
+}
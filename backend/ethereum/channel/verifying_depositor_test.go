@@ -0,0 +1,82 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	"perun.network/go-perun/backend/ethereum/wallet/keystore"
+	pkgtest "perun.network/go-perun/pkg/test"
+	wallettest "perun.network/go-perun/wallet/test"
+)
+
+// feeDepositor is an ETHDepositor that deposits one wei less than requested
+// on its first deposit, simulating a transfer fee.
+type feeDepositor struct {
+	ethchannel.ETHDepositor
+	calls int
+}
+
+func (d *feeDepositor) Deposit(ctx context.Context, req ethchannel.DepositReq) (types.Transactions, error) {
+	d.calls++
+	if d.calls == 1 {
+		req.Balance = new(big.Int).Sub(req.Balance, big.NewInt(1))
+	}
+	return d.ETHDepositor.Deposit(ctx, req)
+}
+
+func TestVerifyingDepositor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 4*defaultTxTimeout)
+	defer cancel()
+	rng := pkgtest.Prng(t)
+
+	simBackend := test.NewSimulatedBackend()
+	ksWallet := wallettest.RandomWallet().(*keystore.Wallet)
+	acc := ksWallet.NewRandomAccount(rng).(*keystore.Account).Account
+	simBackend.FundAddress(ctx, acc.Address)
+	cb := ethchannel.NewContractBackend(simBackend, keystore.NewTransactor(*ksWallet, types.NewEIP155Signer(big.NewInt(1337))))
+	assetAddr, err := ethchannel.DeployETHAssetholder(ctx, cb, acc.Address, acc)
+	require.NoError(t, err)
+	asset := ethchannel.Asset(assetAddr)
+
+	deposit := func(maxTopUps int) (*feeDepositor, error) {
+		var fundingID [32]byte
+		rng.Read(fundingID[:])
+		fd := new(feeDepositor)
+		req := ethchannel.NewDepositReq(big.NewInt(100), cb, asset, acc, fundingID)
+		_, err := ethchannel.NewVerifyingDepositor(fd, maxTopUps).Deposit(ctx, *req)
+		return fd, err
+	}
+
+	t.Run("short", func(t *testing.T) {
+		fd, err := deposit(0)
+		assert.True(t, ethchannel.IsErrDepositShort(err))
+		assert.Equal(t, 1, fd.calls)
+	})
+
+	t.Run("top-up", func(t *testing.T) {
+		fd, err := deposit(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, fd.calls)
+	})
+}
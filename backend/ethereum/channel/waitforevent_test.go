@@ -0,0 +1,89 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	"perun.network/go-perun/backend/ethereum/channel/test"
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestAdjudicator_WaitForEvent(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	params, state := channeltest.NewRandomParamsAndState(
+		rng,
+		channeltest.WithChallengeDuration(uint64(100*time.Second)),
+		channeltest.WithParts(s.Parts...),
+		channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)),
+		channeltest.WithIsFinal(false),
+		channeltest.WithLedgerChannel(true),
+		channeltest.WithVirtualChannel(false),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+	adj := s.Adjs[0]
+	registered := func(version uint64) func(channel.AdjudicatorEvent) bool {
+		return func(e channel.AdjudicatorEvent) bool {
+			_, ok := e.(*channel.RegisteredEvent)
+			return ok && e.Version() >= version
+		}
+	}
+
+	events := make(chan channel.AdjudicatorEvent, 1)
+	go func() {
+		e, err := adj.WaitForEvent(ctx, params.ID(), registered(state.Version))
+		assert.NoError(t, err)
+		events <- e
+	}()
+
+	req := channel.AdjudicatorReq{
+		Params: params,
+		Acc:    s.Accs[0],
+		Idx:    channel.Index(0),
+		Tx:     testSignState(t, s.Accs, params, state),
+	}
+	require.NoError(t, adj.Register(ctx, req, nil))
+
+	select {
+	case e := <-events:
+		require.NotNil(t, e)
+		assert.Equal(t, params.ID(), e.ID())
+		assert.Equal(t, state.Version, e.Version())
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for event")
+	}
+
+	// Past events are considered.
+	e, err := adj.WaitForEvent(ctx, params.ID(), registered(state.Version))
+	require.NoError(t, err)
+	assert.Equal(t, state.Version, e.Version())
+
+	// No newer version gets registered.
+	waitCtx, waitCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer waitCancel()
+	_, err = adj.WaitForEvent(waitCtx, params.ID(), registered(state.Version+1))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline exceeded, got %v", err)
+}
@@ -30,21 +30,40 @@ import (
 	"perun.network/go-perun/backend/ethereum/subscription"
 	"perun.network/go-perun/backend/ethereum/wallet"
 	"perun.network/go-perun/channel"
-	"perun.network/go-perun/client"
 	"perun.network/go-perun/log"
+	perunwallet "perun.network/go-perun/wallet"
 )
 
 // Withdraw ensures that a channel has been concluded and the final outcome
 // withdrawn from the asset holders.
 func (a *Adjudicator) Withdraw(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error {
-	if err := a.ensureConcluded(ctx, req, subStates); err != nil {
+	return a.withdrawTo(ctx, req, subStates.Resolver(), a.Receiver)
+}
+
+// WithdrawWithResolver is like Withdraw, but the states of the sub-channels
+// are fetched with resolve when they are needed to conclude the channel
+// instead of being passed in a StateMap. An error is returned if a required
+// sub-state cannot be resolved.
+func (a *Adjudicator) WithdrawWithResolver(ctx context.Context, req channel.AdjudicatorReq, resolve channel.StateResolver) error {
+	return a.withdrawTo(ctx, req, resolve, a.Receiver)
+}
+
+// WithdrawTo is like Withdraw, but the funds of the withdrawing participant
+// are sent to the given receiver instead of the Adjudicator's Receiver. Each
+// participant withdraws its own funds, so the receiver only applies to the
+// participant of req. It makes the Adjudicator a client.ReceiverWithdrawer.
+func (a *Adjudicator) WithdrawTo(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap, receiver perunwallet.Address) error {
+	return a.withdrawTo(ctx, req, subStates.Resolver(), wallet.AsEthAddr(receiver))
+}
+
+func (a *Adjudicator) withdrawTo(ctx context.Context, req channel.AdjudicatorReq, resolve channel.StateResolver, receiver common.Address) error {
+	if err := a.ensureConcluded(ctx, req, resolve); err != nil {
 		return errors.WithMessage(err, "ensure Concluded")
 	}
-
-	return errors.WithMessage(a.ensureWithdrawn(ctx, req), "ensure Withdrawn")
+	return errors.WithMessage(a.ensureWithdrawn(ctx, req, receiver), "ensure Withdrawn")
 }
 
-func (a *Adjudicator) ensureWithdrawn(ctx context.Context, req channel.AdjudicatorReq) error {
+func (a *Adjudicator) ensureWithdrawn(ctx context.Context, req channel.AdjudicatorReq, receiver common.Address) error {
 	g, ctx := errgroup.WithContext(ctx)
 
 	for index, asset := range req.Tx.Allocation.Assets {
@@ -55,12 +74,17 @@ func (a *Adjudicator) ensureWithdrawn(ctx context.Context, req channel.Adjudicat
 		}
 		index, asset := index, asset // Capture variables locally for usage in closure
 		g.Go(func() error {
+			if err := a.limit.acquire(ctx); err != nil {
+				return err
+			}
+			defer a.limit.release()
+
 			// Create subscription
 			contract := bindAssetHolder(a.ContractBackend, asset, channel.Index(index))
 			fundingID := FundingIDs(req.Params.ID(), req.Params.Parts[req.Idx])[0]
 			events := make(chan *subscription.Event, 10)
 			subErr := make(chan error, 1)
-			sub, err := subscription.NewEventSub(ctx, a.ContractBackend, contract.contract, withdrawnEventType(fundingID), startBlockOffset)
+			sub, err := a.newEventSub(ctx, contract.contract, withdrawnEventType(fundingID))
 			if err != nil {
 				return errors.WithMessage(err, "subscribing")
 			}
@@ -77,7 +101,7 @@ func (a *Adjudicator) ensureWithdrawn(ctx context.Context, req channel.Adjudicat
 			}
 
 			// No withdrawn event found in the past, send transaction.
-			if err := a.callAssetWithdraw(ctx, req, contract); err != nil {
+			if err := a.callAssetWithdraw(ctx, req, contract, receiver); err != nil {
 				return errors.WithMessage(err, "withdrawing assets failed")
 			}
 
@@ -123,8 +147,8 @@ func bindAssetHolder(cb ContractBackend, asset channel.Asset, assetIndex channel
 	return assetHolder{ctr, &assetAddr, contract, assetIndex}
 }
 
-func (a *Adjudicator) callAssetWithdraw(ctx context.Context, request channel.AdjudicatorReq, asset assetHolder) error {
-	auth, sig, err := a.newWithdrawalAuth(request, asset)
+func (a *Adjudicator) callAssetWithdraw(ctx context.Context, request channel.AdjudicatorReq, asset assetHolder, receiver common.Address) error {
+	auth, sig, err := a.newWithdrawalAuth(request, asset, receiver)
 	if err != nil {
 		return errors.WithMessage(err, "creating withdrawal auth")
 	}
@@ -133,10 +157,17 @@ func (a *Adjudicator) callAssetWithdraw(ctx context.Context, request channel.Adj
 			return nil, errors.Wrap(ctx.Err(), "context canceled while acquiring tx lock")
 		}
 		defer a.mu.Unlock()
-		trans, err := a.NewTransactor(ctx, GasLimit, a.txSender)
+		gasLimit, err := a.gasLimit(ctx, Withdraw, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return asset.Withdraw(opts, auth, sig)
+		})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "determining gas limit for asset %d", asset.assetIndex)
+		}
+		trans, err := a.NewTransactor(ctx, gasLimit, a.txSender)
 		if err != nil {
 			return nil, errors.WithMessagef(err, "creating transactor for asset %d", asset.assetIndex)
 		}
+		a.guardTransactor(ctx, trans, Withdraw)
 
 		tx, err := asset.Withdraw(trans, auth, sig)
 		if err != nil {
@@ -149,18 +180,14 @@ func (a *Adjudicator) callAssetWithdraw(ctx context.Context, request channel.Adj
 	if err != nil {
 		return err
 	}
-	_, err = a.ConfirmTransaction(ctx, tx, a.txSender)
-	if err != nil && errors.Is(err, errTxTimedOut) {
-		err = client.NewTxTimedoutError(Withdraw.String(), tx.Hash().Hex(), err.Error())
-	}
-	return errors.WithMessage(err, "mining transaction")
+	return a.confirm(ctx, tx, Withdraw)
 }
 
-func (a *Adjudicator) newWithdrawalAuth(request channel.AdjudicatorReq, asset assetHolder) (assetholder.AssetHolderWithdrawalAuth, []byte, error) {
+func (a *Adjudicator) newWithdrawalAuth(request channel.AdjudicatorReq, asset assetHolder, receiver common.Address) (assetholder.AssetHolderWithdrawalAuth, []byte, error) {
 	auth := assetholder.AssetHolderWithdrawalAuth{
 		ChannelID:   request.Params.ID(),
 		Participant: wallet.AsEthAddr(request.Acc.Address()),
-		Receiver:    a.Receiver,
+		Receiver:    receiver,
 		Amount:      request.Tx.Allocation.Balances[asset.assetIndex][request.Idx],
 	}
 	enc, err := encodeAssetHolderWithdrawalAuth(auth)
@@ -21,12 +21,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	ethchannel "perun.network/go-perun/backend/ethereum/channel"
 	"perun.network/go-perun/backend/ethereum/channel/test"
 	ethwallet "perun.network/go-perun/backend/ethereum/wallet"
+	ethwallettest "perun.network/go-perun/backend/ethereum/wallet/test"
 	"perun.network/go-perun/channel"
 	channeltest "perun.network/go-perun/channel/test"
 	pkgtest "perun.network/go-perun/pkg/test"
@@ -228,6 +230,33 @@ func TestWithdraw(t *testing.T) {
 	})
 }
 
+func TestWithdrawTo(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	s := test.NewSetup(t, rng, 1)
+	params, state := channeltest.NewRandomParamsAndState(rng, channeltest.WithParts(s.Parts...), channeltest.WithAssets((*ethchannel.Asset)(&s.Asset)), channeltest.WithIsFinal(true), channeltest.WithLedgerChannel(true))
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTxTimeout)
+	defer cancel()
+	fundingReq := channel.NewFundingReq(params, state, channel.Index(0), state.Balances)
+	require.NoError(t, s.Funders[0].Fund(ctx, *fundingReq), "funding should succeed")
+
+	req := channel.AdjudicatorReq{
+		Params: params,
+		Acc:    s.Accs[0],
+		Idx:    channel.Index(0),
+		Tx:     testSignState(t, s.Accs, params, state),
+	}
+	receiver := common.Address(ethwallettest.NewRandomAddress(rng))
+	require.NoError(t, s.Adjs[0].WithdrawTo(ctx, req, nil, ethwallet.AsWalletAddr(receiver)))
+
+	bal, err := s.SimBackend.BalanceAt(ctx, receiver, nil)
+	require.NoError(t, err)
+	assert.Zero(t, bal.Cmp(state.Balances[0][0]), "receiver should get the withdrawn funds")
+	defaultBal, err := s.SimBackend.BalanceAt(ctx, ethwallet.AsEthAddr(s.Recvs[0]), nil)
+	require.NoError(t, err)
+	assert.Zero(t, defaultBal.Sign(), "default receiver should get nothing")
+}
+
 func TestWithdrawNonFinal(t *testing.T) {
 	assert := assert.New(t)
 	rng := pkgtest.Prng(t)
@@ -16,6 +16,7 @@ package subscription
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -61,22 +62,59 @@ type (
 // `pastBlocks` can be used to define how many blocks into the past the sub
 // should query.
 func NewEventSub(ctx context.Context, chain ethereum.ChainReader, contract *bind.BoundContract, eFact EventFactory, pastBlocks uint64) (*EventSub, error) {
+	return NewPollingEventSub(ctx, chain, contract, eFact, pastBlocks, 0)
+}
+
+// NewPollingEventSub creates a new `EventSub` that polls the chain for future
+// events every `pollInterval` instead of relying on a push-based log
+// subscription. A `pollInterval` of zero behaves like `NewEventSub`.
+// Should always be closed with `Close`.
+func NewPollingEventSub(ctx context.Context, chain ethereum.ChainReader, contract *bind.BoundContract, eFact EventFactory, pastBlocks uint64, pollInterval time.Duration) (*EventSub, error) {
 	// Get start block number.
-	startBlock, err := calcStartBlock(ctx, chain, pastBlocks)
+	current, err := currentBlock(ctx, chain)
 	if err != nil {
 		return nil, errors.WithMessage(err, "calculating starting block number")
 	}
-	// Watch for future events.
-	event := eFact()
-	watchOpts := &bind.WatchOpts{Start: &startBlock}
-	watchLogs, watchSub, err := contract.WatchLogs(watchOpts, event.Name, event.Filter...)
+	return newEventSub(chain, contract, eFact, calcStartBlock(current, pastBlocks), current, pollInterval)
+}
+
+// NewEventSubFrom creates a new `EventSub` that reads all events starting at
+// block `startBlock`. Future events are polled every `pollInterval`, a
+// `pollInterval` of zero uses a push-based log subscription instead.
+// Should always be closed with `Close`.
+func NewEventSubFrom(ctx context.Context, chain ethereum.ChainReader, contract *bind.BoundContract, eFact EventFactory, startBlock uint64, pollInterval time.Duration) (*EventSub, error) {
+	current, err := currentBlock(ctx, chain)
 	if err != nil {
-		err = cherrors.CheckIsChainNotReachableError(err)
-		return nil, errors.WithMessage(err, "watching logs")
+		return nil, errors.WithMessage(err, "retrieving current block number")
+	}
+	return newEventSub(chain, contract, eFact, startBlock, current, pollInterval)
+}
+
+func newEventSub(chain ethereum.ChainReader, contract *bind.BoundContract, eFact EventFactory, startBlock, current uint64, pollInterval time.Duration) (*EventSub, error) {
+	var err error
+	// Watch for future events.
+	e := eFact()
+	var (
+		watchLogs chan types.Log
+		watchSub  event.Subscription
+	)
+	if pollInterval > 0 {
+		pollStart := current + 1
+		if startBlock > pollStart {
+			pollStart = startBlock
+		}
+		watchLogs, watchSub = pollLogs(chain, contract, e, pollStart, pollInterval)
+	} else {
+		watchOpts := &bind.WatchOpts{Start: &startBlock}
+		watchLogs, watchSub, err = contract.WatchLogs(watchOpts, e.Name, e.Filter...)
+		if err != nil {
+			err = cherrors.CheckIsChainNotReachableError(err)
+			return nil, errors.WithMessage(err, "watching logs")
+		}
 	}
 	// Read past events.
 	filterOpts := &bind.FilterOpts{Start: startBlock}
-	filterLogs, filterSub, err := contract.FilterLogs(filterOpts, event.Name, event.Filter...)
+	filterLogs, filterSub, err := contract.FilterLogs(filterOpts, e.Name, e.Filter...)
 	if err != nil {
 		watchSub.Unsubscribe()
 		err = cherrors.CheckIsChainNotReachableError(err)
@@ -94,16 +132,97 @@ func NewEventSub(ctx context.Context, chain ethereum.ChainReader, contract *bind
 	}, nil
 }
 
-func calcStartBlock(ctx context.Context, chain ethereum.ChainReader, pastBlocks uint64) (uint64, error) {
+func currentBlock(ctx context.Context, chain ethereum.ChainReader) (uint64, error) {
 	current, err := chain.HeaderByNumber(ctx, nil)
 	if err != nil {
 		err = cherrors.CheckIsChainNotReachableError(err)
 		return 0, errors.WithMessage(err, "retrieving latest block")
 	}
-	if current.Number.Uint64() <= pastBlocks {
-		return 1, nil
+	return current.Number.Uint64(), nil
+}
+
+func calcStartBlock(current, pastBlocks uint64) uint64 {
+	if current <= pastBlocks {
+		return 1
+	}
+	return current - pastBlocks
+}
+
+// pollLogs queries the chain every `interval` for new logs of the given event,
+// starting at block `from`. The returned subscription fails with the first
+// error that occurs while polling.
+func pollLogs(chain ethereum.ChainReader, contract *bind.BoundContract, e *Event, from uint64, interval time.Duration) (chan types.Log, event.Subscription) {
+	logs := make(chan types.Log)
+	sub := event.NewSubscription(func(quit <-chan struct{}) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-quit
+			cancel()
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-quit:
+				return nil
+			}
+
+			head, err := currentBlock(ctx, chain)
+			if err != nil {
+				return err
+			}
+			if head < from {
+				continue
+			}
+			filterOpts := &bind.FilterOpts{Start: from, End: &head, Context: ctx}
+			newLogs, err := queryLogs(contract, filterOpts, e)
+			if err != nil {
+				return err
+			}
+			for _, log := range newLogs {
+				select {
+				case logs <- log:
+				case <-quit:
+					return nil
+				}
+			}
+			from = head + 1
+		}
+	})
+	return logs, sub
+}
+
+// queryLogs returns all logs of the given event that match `opts`.
+func queryLogs(contract *bind.BoundContract, opts *bind.FilterOpts, e *Event) ([]types.Log, error) {
+	logsChan, sub, err := contract.FilterLogs(opts, e.Name, e.Filter...)
+	if err != nil {
+		return nil, cherrors.CheckIsChainNotReachableError(err)
+	}
+	defer sub.Unsubscribe()
+
+	var logs []types.Log
+	for {
+		select {
+		case log := <-logsChan:
+			logs = append(logs, log)
+		case err := <-sub.Err():
+			if err != nil {
+				return nil, cherrors.CheckIsChainNotReachableError(err)
+			}
+			// The subscription is done but logs may still be buffered.
+			for {
+				select {
+				case log := <-logsChan:
+					logs = append(logs, log)
+				default:
+					return logs, nil
+				}
+			}
+		}
 	}
-	return current.Number.Uint64() - pastBlocks, nil
 }
 
 // Read reads all past and future events into `sink`.
@@ -198,3 +198,71 @@ func TestEventSub_Filter(t *testing.T) {
 	// We do not check here that <-sink returns nil, since the EventSub
 	// can receive events more than once.
 }
+
+// TestEventSub_Polling checks that a polling EventSub reads past and future
+// events.
+func TestEventSub_Polling(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	rng := pkgtest.Prng(t)
+
+	// Simulated chain setup.
+	sb := test.NewSimulatedBackend()
+	ksWallet := wallettest.RandomWallet().(*keystore.Wallet)
+	account := &ksWallet.NewRandomAccount(rng).(*keystore.Account).Account
+	sb.FundAddress(ctx, account.Address)
+	cb := ethchannel.NewContractBackend(sb, keystore.NewTransactor(*ksWallet, types.NewEIP155Signer(big.NewInt(1337))))
+
+	// Setup ETH AssetHolder.
+	adjAddr, err := ethchannel.DeployAdjudicator(ctx, cb, *account)
+	require.NoError(t, err)
+	ahAddr, err := ethchannel.DeployETHAssetholder(ctx, cb, adjAddr, *account)
+	require.NoError(t, err)
+	ah, err := assetholdereth.NewAssetHolder(ahAddr, cb)
+	require.NoError(t, err)
+	ct := pkgtest.NewConcurrent(t)
+
+	fundingID := channeltest.NewRandomChannelID(rng)
+	deposit := func(amount int64) {
+		opts, err := cb.NewTransactor(ctx, txGasLimit, *account)
+		require.NoError(t, err)
+		opts.Value = big.NewInt(amount)
+		tx, err := ah.Deposit(opts, fundingID, big.NewInt(amount))
+		require.NoError(t, err)
+		_, err = cb.ConfirmTransaction(ctx, tx, *account)
+		require.NoError(t, err)
+	}
+	// Emit one event before the sub is created.
+	deposit(1)
+
+	sink := make(chan *subscription.Event, 2)
+	eFact := func() *subscription.Event {
+		return &subscription.Event{
+			Name:   bindings.Events.AhDeposited,
+			Data:   new(assetholder.AssetHolderDeposited),
+			Filter: [][]interface{}{{fundingID}},
+		}
+	}
+	contract := bind.NewBoundContract(ahAddr, bindings.ABI.AssetHolder, cb, cb, cb)
+	sub, err := subscription.NewPollingEventSub(ctx, cb, contract, eFact, 100, 10*time.Millisecond)
+	require.NoError(t, err)
+	go ct.Stage("sub", func(t pkgtest.ConcT) {
+		defer close(sink)
+		require.NoError(t, sub.Read(context.Background(), sink))
+	})
+
+	// Emit one event after the sub is created.
+	deposit(2)
+
+	for i := int64(1); i <= 2; i++ {
+		e := <-sink
+		require.NotNil(t, e)
+		want := &assetholder.AssetHolderDeposited{
+			FundingID: fundingID,
+			Amount:    big.NewInt(i),
+		}
+		require.Equal(t, want, e.Data)
+	}
+	sub.Close()
+	ct.Wait("sub")
+}
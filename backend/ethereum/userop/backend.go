@@ -0,0 +1,239 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userop
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+	cherrors "perun.network/go-perun/backend/ethereum/channel/errors"
+)
+
+const (
+	// DefaultVerificationGasLimit is the default gas limit for the validation
+	// of a UserOperation by the smart account.
+	DefaultVerificationGasLimit = 100000
+	// DefaultPreVerificationGas is the default gas that compensates the
+	// bundler for the overhead of including a UserOperation.
+	DefaultPreVerificationGas = 50000
+)
+
+var (
+	// entryPointABI contains the used functions of the EntryPoint contract.
+	entryPointABI = mustParseABI(`[{"name":"getNonce","type":"function","stateMutability":"view",
+		"inputs":[{"name":"sender","type":"address"},{"name":"key","type":"uint192"}],
+		"outputs":[{"name":"nonce","type":"uint256"}]}]`)
+	// accountABI contains the used functions of the smart account contract.
+	accountABI = mustParseABI(`[{"name":"execute","type":"function","stateMutability":"nonpayable",
+		"inputs":[{"name":"dest","type":"address"},{"name":"value","type":"uint256"},{"name":"func","type":"bytes"}],
+		"outputs":[]}]`)
+)
+
+type (
+	// Signer signs the hash of a UserOperation on behalf of a smart account.
+	Signer interface {
+		SignUserOp(hash common.Hash) ([]byte, error)
+	}
+
+	// Backend is a ContractInterface that sends transactions as UserOperations
+	// of a smart account through an ERC-4337 bundler. All other calls are
+	// forwarded to the wrapped ContractInterface.
+	//
+	// Transactions must be created with a Transactor for the same smart
+	// account, since the Backend ignores their signature.
+	Backend struct {
+		ethchannel.ContractInterface
+
+		// VerificationGasLimit is the verification gas limit that is set on all
+		// UserOperations.
+		VerificationGasLimit uint64
+		// PreVerificationGas is the pre-verification gas that is set on all
+		// UserOperations.
+		PreVerificationGas uint64
+
+		bundler    *rpc.Client
+		entryPoint common.Address
+		account    common.Address
+		chainID    *big.Int
+		signer     Signer
+
+		mtx     sync.Mutex
+		userOps map[common.Hash]common.Hash // Maps transaction hashes to UserOperation hashes.
+	}
+
+	// receiptResult is the result of an eth_getUserOperationReceipt call.
+	receiptResult struct {
+		Success bool           `json:"success"`
+		Receipt *types.Receipt `json:"receipt"`
+	}
+)
+
+// NewBackend creates a new Backend that sends the transactions of smart
+// account `account` via `bundler` to `entryPoint`. All other calls are
+// forwarded to `cb`. The UserOperations are signed using `signer`.
+func NewBackend(
+	cb ethchannel.ContractInterface,
+	bundler *rpc.Client,
+	entryPoint common.Address,
+	account common.Address,
+	chainID *big.Int,
+	signer Signer,
+) *Backend {
+	return &Backend{
+		ContractInterface:    cb,
+		VerificationGasLimit: DefaultVerificationGasLimit,
+		PreVerificationGas:   DefaultPreVerificationGas,
+		bundler:              bundler,
+		entryPoint:           entryPoint,
+		account:              account,
+		chainID:              chainID,
+		signer:               signer,
+		userOps:              make(map[common.Hash]common.Hash),
+	}
+}
+
+// SendTransaction wraps the transaction into a UserOperation that lets the
+// smart account execute it and sends the UserOperation to the bundler.
+// Contract creations are not supported.
+func (b *Backend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if tx.To() == nil {
+		return errors.New("contract creation not supported")
+	}
+	callData, err := accountABI.Pack("execute", *tx.To(), tx.Value(), tx.Data())
+	if err != nil {
+		return errors.Wrap(err, "packing call data")
+	}
+	nonce, err := b.nonce(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "fetching nonce")
+	}
+
+	op := &UserOperation{
+		Sender:               b.account,
+		Nonce:                (*hexutil.Big)(nonce),
+		InitCode:             []byte{},
+		CallData:             callData,
+		CallGasLimit:         (*hexutil.Big)(new(big.Int).SetUint64(tx.Gas())),
+		VerificationGasLimit: (*hexutil.Big)(new(big.Int).SetUint64(b.VerificationGasLimit)),
+		PreVerificationGas:   (*hexutil.Big)(new(big.Int).SetUint64(b.PreVerificationGas)),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasPrice()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasPrice()),
+		PaymasterAndData:     []byte{},
+	}
+	hash, err := op.Hash(b.entryPoint, b.chainID)
+	if err != nil {
+		return errors.Wrap(err, "hashing UserOperation")
+	}
+	if op.Signature, err = b.signer.SignUserOp(hash); err != nil {
+		return errors.WithMessage(err, "signing UserOperation")
+	}
+
+	var opHash common.Hash
+	if err := b.bundler.CallContext(ctx, &opHash, "eth_sendUserOperation", op, b.entryPoint); err != nil {
+		err = cherrors.CheckIsChainNotReachableError(err)
+		return errors.WithMessage(err, "sending UserOperation")
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.userOps[tx.Hash()] = opHash
+	return nil
+}
+
+// TransactionReceipt returns the receipt of the transaction that included the
+// UserOperation of the given transaction. Its status is failed if the
+// execution of the UserOperation failed. Transactions that were not sent by
+// this Backend are looked up on the wrapped ContractInterface.
+func (b *Backend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.mtx.Lock()
+	opHash, ok := b.userOps[txHash]
+	b.mtx.Unlock()
+	if !ok {
+		return b.ContractInterface.TransactionReceipt(ctx, txHash)
+	}
+
+	var res *receiptResult
+	if err := b.bundler.CallContext(ctx, &res, "eth_getUserOperationReceipt", opHash); err != nil {
+		return nil, cherrors.CheckIsChainNotReachableError(err)
+	}
+	if res == nil || res.Receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	if !res.Success {
+		res.Receipt.Status = types.ReceiptStatusFailed
+	}
+	return res.Receipt, nil
+}
+
+// nonce fetches the next UserOperation nonce of the smart account from the
+// EntryPoint.
+func (b *Backend) nonce(ctx context.Context) (*big.Int, error) {
+	data, err := entryPointABI.Pack("getNonce", b.account, new(big.Int))
+	if err != nil {
+		return nil, errors.Wrap(err, "packing call")
+	}
+	res, err := b.CallContract(ctx, ethereum.CallMsg{To: &b.entryPoint, Data: data}, nil)
+	if err != nil {
+		return nil, cherrors.CheckIsChainNotReachableError(err)
+	}
+	out, err := entryPointABI.Unpack("getNonce", res)
+	if err != nil {
+		return nil, errors.Wrap(err, "unpacking nonce")
+	}
+	return out[0].(*big.Int), nil
+}
+
+// walletSigner signs UserOperations with an owner account of a wallet.
+type walletSigner struct {
+	wallet accounts.Wallet
+	owner  accounts.Account
+}
+
+// NewWalletSigner returns a Signer that signs the UserOperation hash as an
+// Ethereum signed message with the given owner account, as expected by the
+// common smart account implementations.
+func NewWalletSigner(w accounts.Wallet, owner accounts.Account) Signer {
+	return &walletSigner{wallet: w, owner: owner}
+}
+
+// SignUserOp signs the given UserOperation hash.
+func (s *walletSigner) SignUserOp(hash common.Hash) ([]byte, error) {
+	sig, err := s.wallet.SignText(s.owner, hash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27 // Smart accounts expect a V value of 27 or 28.
+	return sig, nil
+}
+
+func mustParseABI(def string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userop
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchannel "perun.network/go-perun/backend/ethereum/channel"
+)
+
+const testNonce = 7
+
+// nonceBackend is a ContractInterface that only answers getNonce calls.
+type nonceBackend struct {
+	ethchannel.ContractInterface
+}
+
+func (nonceBackend) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return entryPointABI.Methods["getNonce"].Outputs.Pack(big.NewInt(testNonce))
+}
+
+// mockBundler implements the used methods of the bundler RPC API.
+type mockBundler struct {
+	ops        []UserOperation
+	entryPoint common.Address
+	result     *receiptResult
+}
+
+func (b *mockBundler) SendUserOperation(op UserOperation, entryPoint common.Address) common.Hash {
+	b.ops = append(b.ops, op)
+	b.entryPoint = entryPoint
+	return common.Hash{byte(len(b.ops))}
+}
+
+func (b *mockBundler) GetUserOperationReceipt(common.Hash) *receiptResult {
+	return b.result
+}
+
+func TestBackend(t *testing.T) {
+	ctx := context.Background()
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	sk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	owner, err := ks.ImportECDSA(sk, "")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(owner, ""))
+
+	bundler := &mockBundler{}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", bundler))
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	entryPoint, account := common.Address{1}, common.Address{2}
+	chainID := big.NewInt(1337)
+	b := NewBackend(nonceBackend{}, client, entryPoint, account, chainID, NewWalletSigner(ks.Wallets()[0], owner))
+
+	// Create a transaction with the Transactor.
+	opts, err := NewTransactor(account).NewTransactor(accounts.Account{Address: account})
+	require.NoError(t, err)
+	to, value, data := common.Address{3}, big.NewInt(4), []byte{5, 6}
+	tx, err := opts.Signer(account, types.NewTransaction(0, to, value, 100000, big.NewInt(1), data))
+	require.NoError(t, err)
+	require.NoError(t, b.SendTransaction(ctx, tx))
+
+	// Check the UserOperation.
+	require.Len(t, bundler.ops, 1)
+	op := bundler.ops[0]
+	assert.Equal(t, entryPoint, bundler.entryPoint)
+	assert.Equal(t, account, op.Sender)
+	assert.Equal(t, int64(testNonce), op.Nonce.ToInt().Int64())
+	assert.Equal(t, tx.Gas(), op.CallGasLimit.ToInt().Uint64())
+	wantCallData, err := accountABI.Pack("execute", to, value, data)
+	require.NoError(t, err)
+	assert.Equal(t, wantCallData, []byte(op.CallData))
+
+	// Check the signature.
+	hash, err := op.Hash(entryPoint, chainID)
+	require.NoError(t, err)
+	sig := append([]byte{}, op.Signature...)
+	sig[64] -= 27
+	pk, err := crypto.SigToPub(accounts.TextHash(hash.Bytes()), sig)
+	require.NoError(t, err)
+	assert.Equal(t, owner.Address, crypto.PubkeyToAddress(*pk))
+
+	// Check the receipt.
+	_, err = b.TransactionReceipt(ctx, tx.Hash())
+	assert.Equal(t, ethereum.NotFound, err)
+	bundler.result = &receiptResult{
+		Success: false,
+		Receipt: &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}},
+	}
+	receipt, err := b.TransactionReceipt(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusFailed, receipt.Status)
+}
+
+func TestTransactor(t *testing.T) {
+	account := common.Address{1}
+	tr := NewTransactor(account)
+
+	_, err := tr.NewTransactor(accounts.Account{Address: common.Address{2}})
+	assert.Error(t, err)
+
+	opts, err := tr.NewTransactor(accounts.Account{Address: account})
+	require.NoError(t, err)
+	assert.Equal(t, account, opts.From)
+	_, err = opts.Signer(common.Address{2}, new(types.Transaction))
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package userop allows ERC-4337 smart accounts to send the on-chain
+// transactions of the Ethereum backend.
+//
+// Instead of broadcasting raw transactions, the Backend wraps every outgoing
+// transaction into a UserOperation that lets the smart account execute the
+// call, and submits it to a bundler. Together with the Transactor, it can be
+// used in place of a regular ContractInterface and Transactor when creating a
+// channel.ContractBackend, so that the Funder and Adjudicator work unchanged.
+package userop // import "perun.network/go-perun/backend/ethereum/userop"
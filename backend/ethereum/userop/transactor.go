@@ -0,0 +1,52 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userop
+
+import (
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// Transactor can be used to make TransactOpts for a smart account. The
+// transactions are not signed, because the Backend authorizes them by signing
+// the UserOperation instead.
+type Transactor struct {
+	Account common.Address
+}
+
+// NewTransactor returns a new Transactor for the given smart account.
+func NewTransactor(account common.Address) *Transactor {
+	return &Transactor{Account: account}
+}
+
+// NewTransactor returns a TransactOpts for the given account. It errors if the
+// account is not the Transactor's smart account.
+func (t *Transactor) NewTransactor(account accounts.Account) (*bind.TransactOpts, error) {
+	if account.Address != t.Account {
+		return nil, errors.New("the transactor is not responsible for the given account")
+	}
+	return &bind.TransactOpts{
+		From: account.Address,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != account.Address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return tx, nil
+		},
+	}, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userop
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// UserOperation is an ERC-4337 user operation as defined by version 0.6 of
+// the EntryPoint contract.
+type UserOperation struct {
+	Sender               common.Address `json:"sender"`
+	Nonce                *hexutil.Big   `json:"nonce"`
+	InitCode             hexutil.Bytes  `json:"initCode"`
+	CallData             hexutil.Bytes  `json:"callData"`
+	CallGasLimit         *hexutil.Big   `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big   `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big   `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	PaymasterAndData     hexutil.Bytes  `json:"paymasterAndData"`
+	Signature            hexutil.Bytes  `json:"signature"`
+}
+
+var (
+	abiAddress, _ = abi.NewType("address", "", nil)
+	abiUint256, _ = abi.NewType("uint256", "", nil)
+	abiBytes32, _ = abi.NewType("bytes32", "", nil)
+
+	// userOpArgs are the arguments of the packed user operation, excluding
+	// the signature.
+	userOpArgs = abi.Arguments{
+		{Type: abiAddress}, {Type: abiUint256}, {Type: abiBytes32}, {Type: abiBytes32},
+		{Type: abiUint256}, {Type: abiUint256}, {Type: abiUint256}, {Type: abiUint256},
+		{Type: abiUint256}, {Type: abiBytes32},
+	}
+	hashArgs = abi.Arguments{{Type: abiBytes32}, {Type: abiAddress}, {Type: abiUint256}}
+)
+
+// Hash returns the hash of the user operation that is signed by the smart
+// account's owner. It depends on the EntryPoint and the chain ID.
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) (common.Hash, error) {
+	packed, err := userOpArgs.Pack(
+		op.Sender,
+		op.Nonce.ToInt(),
+		crypto.Keccak256Hash(op.InitCode),
+		crypto.Keccak256Hash(op.CallData),
+		op.CallGasLimit.ToInt(),
+		op.VerificationGasLimit.ToInt(),
+		op.PreVerificationGas.ToInt(),
+		op.MaxFeePerGas.ToInt(),
+		op.MaxPriorityFeePerGas.ToInt(),
+		crypto.Keccak256Hash(op.PaymasterAndData),
+	)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	enc, err := hashArgs.Pack(crypto.Keccak256Hash(packed), entryPoint, chainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(enc), nil
+}
@@ -16,6 +16,7 @@ package keystore
 
 import (
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/pkg/errors"
 
 	ethwallet "perun.network/go-perun/backend/ethereum/wallet"
@@ -33,10 +34,22 @@ func (a *Account) Address() wallet.Address {
 	return (*ethwallet.Address)(&a.Account.Address)
 }
 
-// SignData is used to sign data with this account.
+// SignData is used to sign data with this account. If the account is locked
+// and the wallet has an UnlockPolicy, the account is unlocked and the signing
+// is retried.
+//
+// Signatures are deterministic: the ECDSA nonce is derived from the private
+// key and the data as specified in RFC 6979. Hence, signing the same data with
+// the same account always yields the same signature.
 func (a *Account) SignData(data []byte) ([]byte, error) {
 	hash := ethwallet.PrefixedHash(data)
 	sig, err := a.wallet.Ks.SignHash(a.Account, hash)
+	if errors.Is(err, keystore.ErrLocked) && a.wallet.unlockPolicy != nil {
+		if err := a.wallet.unlockPolicy.Unlock(a.wallet.Ks, a.Account); err != nil {
+			return nil, errors.WithMessage(err, "unlocking locked account")
+		}
+		sig, err = a.wallet.Ks.SignHash(a.Account, hash)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "SignHash")
 	}
@@ -0,0 +1,69 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keystore_test
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	accsKeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethwallet "perun.network/go-perun/backend/ethereum/wallet"
+	"perun.network/go-perun/backend/ethereum/wallet/keystore"
+)
+
+// TestAccount_SignDataDeterministic checks that signatures are reproducible
+// across wallets, i.e., that the nonce is derived as specified in RFC 6979.
+func TestAccount_SignDataDeterministic(t *testing.T) {
+	const (
+		pw     = "secret"
+		keyHex = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+		sigHex = "37fc265cabe24a6c2e457eee780d4de1afd318c7116d21ee7f102c9e2524929f06897a74da7d70760586eda3bcaae0513b5936cdff434bdb9c1732c100cd0f1c1b"
+	)
+	key, err := crypto.HexToECDSA(keyHex)
+	require.NoError(t, err)
+	addr := ethwallet.Address(crypto.PubkeyToAddress(key.PublicKey))
+
+	var sigs [2][]byte
+	for i := range sigs {
+		dir, err := ioutil.TempDir("", "go-perun-test-eth-keystore-")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		ks := accsKeystore.NewKeyStore(dir, 2, 1)
+		_, err = ks.ImportECDSA(key, pw)
+		require.NoError(t, err)
+		w, err := keystore.NewWallet(ks, pw)
+		require.NoError(t, err)
+		acc, err := w.Unlock(&addr)
+		require.NoError(t, err)
+
+		sigs[i], err = acc.SignData(dataToSign)
+		require.NoError(t, err)
+		again, err := acc.SignData(dataToSign)
+		require.NoError(t, err)
+		assert.Equal(t, sigs[i], again, "signing twice should yield the same signature")
+	}
+	assert.Equal(t, sigs[0], sigs[1], "signatures of different wallets should be equal")
+	assert.Equal(t, sigHex, hex.EncodeToString(sigs[0]))
+
+	valid, err := new(ethwallet.Backend).VerifySignature(dataToSign, sigs[0], &addr)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keystore
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/pkg/errors"
+)
+
+type (
+	// UnlockPolicy unlocks accounts that are found to be locked when they are
+	// used for signing, e.g., because they were unlocked with a timeout that
+	// expired. The signing is retried once after a successful unlock.
+	UnlockPolicy interface {
+		// Unlock unlocks account acc in keystore ks or returns an error if the
+		// account cannot be unlocked.
+		Unlock(ks *keystore.KeyStore, acc accounts.Account) error
+	}
+
+	// UnlockPolicyFunc is an adapter type to allow the use of functions as
+	// UnlockPolicy.
+	UnlockPolicyFunc func(ks *keystore.KeyStore, acc accounts.Account) error
+
+	// PassphraseProvider returns the passphrase of the given account, e.g., by
+	// prompting the user.
+	PassphraseProvider func(acc accounts.Account) (string, error)
+)
+
+// Unlock calls the unlock policy function.
+func (f UnlockPolicyFunc) Unlock(ks *keystore.KeyStore, acc accounts.Account) error {
+	return f(ks, acc)
+}
+
+// UnlockWithPassphrase returns an UnlockPolicy that unlocks accounts with the
+// passphrase returned by pp. The accounts are unlocked for the given duration,
+// a zero timeout unlocks them until the wallet is locked.
+func UnlockWithPassphrase(pp PassphraseProvider, timeout time.Duration) UnlockPolicy {
+	return UnlockPolicyFunc(func(ks *keystore.KeyStore, acc accounts.Account) error {
+		pw, err := pp(acc)
+		if err != nil {
+			return errors.WithMessage(err, "getting passphrase")
+		}
+		return errors.Wrap(ks.TimedUnlock(acc, pw, timeout), "unlocking account")
+	})
+}
+
+// SetUnlockPolicy sets the policy that is used to unlock the wallet's accounts
+// when they are locked during signing. By default, no policy is set and
+// signing with a locked account fails. This method is expected to be called
+// once during the setup of the wallet and is hence not thread-safe.
+func (w *Wallet) SetUnlockPolicy(p UnlockPolicy) {
+	w.unlockPolicy = p
+}
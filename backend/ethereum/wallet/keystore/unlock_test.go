@@ -0,0 +1,60 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keystore_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	gethks "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/backend/ethereum/wallet/keystore"
+)
+
+func TestAccount_SignData_UnlockPolicy(t *testing.T) {
+	const pw = "secret"
+	w, err := keystore.NewWallet(gethks.NewKeyStore(t.TempDir(), 2, 1), pw)
+	require.NoError(t, err)
+	acc := w.NewAccount()
+	require.NoError(t, w.Ks.Lock(acc.Account.Address))
+
+	// Without policy, signing with a locked account fails.
+	_, err = acc.SignData(dataToSign)
+	assert.True(t, errors.Is(err, gethks.ErrLocked))
+
+	// A failing policy surfaces its error.
+	errProvider := errors.New("no passphrase")
+	w.SetUnlockPolicy(keystore.UnlockWithPassphrase(func(accounts.Account) (string, error) {
+		return "", errProvider
+	}, 0))
+	_, err = acc.SignData(dataToSign)
+	assert.True(t, errors.Is(err, errProvider))
+
+	// A working policy unlocks the account.
+	var calls int
+	w.SetUnlockPolicy(keystore.UnlockWithPassphrase(func(a accounts.Account) (string, error) {
+		assert.Equal(t, acc.Account.Address, a.Address)
+		calls++
+		return pw, nil
+	}, 0))
+	_, err = acc.SignData(dataToSign)
+	assert.NoError(t, err)
+	_, err = acc.SignData(dataToSign)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls, "account should stay unlocked")
+}
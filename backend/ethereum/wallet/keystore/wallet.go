@@ -38,6 +38,8 @@ var _ wallet.Wallet = (*Wallet)(nil)
 type Wallet struct {
 	Ks *keystore.KeyStore
 	pw string
+
+	unlockPolicy UnlockPolicy
 }
 
 // NewWallet creates a new Wallet from a keystore and password.
@@ -38,7 +38,7 @@ type Account struct {
 // NewRandomAccount generates a new account, reading randomness form the given
 // rng. It is not saved to any wallet.
 func NewRandomAccount(rng io.Reader) *Account {
-	privateKey, err := ecdsa.GenerateKey(curve, rng)
+	privateKey, err := newRandomKey(rng)
 
 	if err != nil {
 		log.Panicf("Creation of account failed with error", err)
@@ -49,6 +49,28 @@ func NewRandomAccount(rng io.Reader) *Account {
 	}
 }
 
+// newRandomKey generates a new private key, reading randomness from the given
+// rng. Unlike ecdsa.GenerateKey, it only reads from rng, so that keys
+// generated from a seeded rng are reproducible.
+func newRandomKey(rng io.Reader) (*ecdsa.PrivateKey, error) {
+	params := curve.Params()
+	b := make([]byte, params.BitSize/8+8)
+	if _, err := io.ReadFull(rng, b); err != nil {
+		return nil, errors.Wrap(err, "reading randomness")
+	}
+
+	// d = b mod (N-1) + 1, so that d is in [1, N-1].
+	one := big.NewInt(1)
+	d := new(big.Int).SetBytes(b)
+	d.Mod(d, new(big.Int).Sub(params.N, one))
+	d.Add(d, one)
+
+	key := &ecdsa.PrivateKey{D: d}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d.Bytes())
+	return key, nil
+}
+
 // Address returns the address of this account.
 func (a *Account) Address() wallet.Address {
 	return wallet.Address((*Address)(&a.privKey.PublicKey))
@@ -38,7 +38,7 @@ var _ wallet.Address = (*Address)(nil)
 // NewRandomAddress creates a new address using the randomness
 // provided by rng.
 func NewRandomAddress(rng io.Reader) *Address {
-	privateKey, err := ecdsa.GenerateKey(curve, rng)
+	privateKey, err := newRandomKey(rng)
 
 	if err != nil {
 		log.Panicf("Creation of account failed with error", err)
@@ -16,6 +16,7 @@ package wallet
 
 import (
 	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -121,3 +122,11 @@ func CloneAddr(addr *Address) *Address {
 		Y:     cloneY,
 	}
 }
+
+func TestNewRandomAddress_Reproducible(t *testing.T) {
+	seed := test.Prng(t).Int63()
+	a := NewRandomAddress(rand.New(rand.NewSource(seed)))
+	b := NewRandomAddress(rand.New(rand.NewSource(seed)))
+	assert.True(t, a.Equals(b), "same seed should yield same address")
+	assert.True(t, curve.IsOnCurve(a.X, a.Y))
+}
@@ -131,6 +131,10 @@ type (
 		IDV      ID      // Channel ID
 		TimeoutV Timeout // Current phase timeout
 		VersionV uint64  // Registered version
+		// SourceV is the backend-specific origin of the event, e.g., the
+		// on-chain log it was decoded from. It is nil if the backend does not
+		// provide it.
+		SourceV interface{}
 	}
 
 	// ProgressedEvent is the abstract event that signals an on-chain progression.
@@ -167,6 +171,11 @@ type (
 
 	// StateMap represents a channel state tree.
 	StateMap map[ID]*State
+
+	// StateResolver returns the state of the channel with the given ID. It can
+	// be used to fetch the states of a channel tree lazily, e.g., from
+	// persistence, instead of collecting them in a StateMap upfront.
+	StateResolver func(ID) (*State, error)
 )
 
 // NewProgressReq creates a new ProgressReq object.
@@ -192,6 +201,10 @@ func (b AdjudicatorEventBase) Timeout() Timeout { return b.TimeoutV }
 // Version returns the channel version.
 func (b AdjudicatorEventBase) Version() uint64 { return b.VersionV }
 
+// Source returns the backend-specific origin of the event, or nil if the
+// backend does not provide it.
+func (b AdjudicatorEventBase) Source() interface{} { return b.SourceV }
+
 // NewRegisteredEvent creates a new RegisteredEvent.
 func NewRegisteredEvent(id ID, timeout Timeout, version uint64, state *State, sigs []wallet.Sig) *RegisteredEvent {
 	return &RegisteredEvent{
@@ -273,3 +286,15 @@ func (m StateMap) Add(states ...*State) {
 		m[s.ID] = s
 	}
 }
+
+// Resolver returns a StateResolver that looks up the states in the state map.
+// It returns an error for states that are not contained.
+func (m StateMap) Resolver() StateResolver {
+	return func(id ID) (*State, error) {
+		s, ok := m[id]
+		if !ok {
+			return nil, errors.Errorf("state not found (ID: %x)", id)
+		}
+		return s, nil
+	}
+}
@@ -422,6 +422,18 @@ func (a Allocation) Valid() error {
 // allocations.
 func (a Allocation) Sum() []Bal {
 	totals := a.Balances.Sum()
+	for i, bal := range a.SumLocked() {
+		totals[i].Add(totals[i], bal)
+	}
+	return totals
+}
+
+// SumLocked returns the sum of each asset over all locked allocations.
+func (a Allocation) SumLocked() []Bal {
+	totals := make([]Bal, len(a.Balances))
+	for i := range totals {
+		totals[i] = new(big.Int)
+	}
 	// Locked is allowed to have zero length, in which case there's nothing locked
 	// and the loop is empty.
 	for _, a := range a.Locked {
@@ -429,7 +441,6 @@ func (a Allocation) Sum() []Bal {
 			totals[i].Add(totals[i], bal)
 		}
 	}
-
 	return totals
 }
 
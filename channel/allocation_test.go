@@ -410,6 +410,19 @@ func TestAllocation_Sum(t *testing.T) {
 	}
 }
 
+func TestAllocation_SumLocked(t *testing.T) {
+	rng := pkgtest.Prng(t)
+
+	alloc := test.NewRandomAllocation(rng, test.WithNumAssets(2), test.WithNumParts(2), test.WithLocked(
+		*test.NewRandomSubAlloc(rng, test.WithLockedBals(big.NewInt(1), big.NewInt(2))),
+		*test.NewRandomSubAlloc(rng, test.WithLockedBals(big.NewInt(4), big.NewInt(8))),
+	))
+	assert.Equal(t, []channel.Bal{big.NewInt(5), big.NewInt(10)}, alloc.SumLocked())
+
+	alloc.Locked = nil
+	assert.Equal(t, []channel.Bal{big.NewInt(0), big.NewInt(0)}, alloc.SumLocked())
+}
+
 func TestAllocation_Valid(t *testing.T) {
 	rng := pkgtest.Prng(t)
 	// note that all valid branches are already indirectly tested in TestAllocation_Sum
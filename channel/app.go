@@ -15,7 +15,9 @@
 package channel
 
 import (
+	"bytes"
 	"io"
+	"math"
 
 	"github.com/pkg/errors"
 
@@ -38,6 +40,10 @@ type (
 		// defined on an application-level because every app can have completely
 		// different data; during decoding the application needs to be known to
 		// know how to decode the data.
+		// The reader only contains the bytes written by the Data's Encode
+		// method, see AppDataEnc. Bytes that are not read are skipped, so an app
+		// can append fields to its data encoding in later versions without
+		// breaking older decoders.
 		DecodeData(io.Reader) (Data, error)
 	}
 
@@ -162,6 +168,64 @@ func (d OptAppDec) Decode(r io.Reader) (err error) {
 	return errors.WithMessage(err, "resolve app")
 }
 
+// AppDataEnc makes app Data encodable. The encoding produced by the Data's
+// Encode method is prefixed with its length, so the app fully owns the format
+// of its data and other values can be decoded independently of it.
+//
+// States and channel proposals encode their app data without a length prefix
+// to stay compatible with existing peers and persisted channels. AppDataEnc
+// is meant for new message types and persistence formats.
+type AppDataEnc struct {
+	Data
+}
+
+// Encode encodes the length-prefixed app data.
+func (e AppDataEnc) Encode(w io.Writer) error {
+	var buf bytes.Buffer
+	if err := e.Data.Encode(&buf); err != nil {
+		return errors.WithMessage(err, "encoding app data")
+	}
+	if buf.Len() > math.MaxUint32 {
+		return errors.Errorf("app data length exceeded: %d", buf.Len())
+	}
+	if err := perunio.Encode(w, uint32(buf.Len())); err != nil {
+		return errors.WithMessage(err, "encoding app data length")
+	}
+	_, err := buf.WriteTo(w)
+	return errors.Wrap(err, "writing app data")
+}
+
+// AppDataDec makes app Data decodable. The data is decoded with
+// App.DecodeData from a reader that only contains the length-prefixed bytes
+// written by AppDataEnc.
+type AppDataDec struct {
+	App  App
+	Data *Data
+}
+
+// Decode decodes length-prefixed app data.
+func (d AppDataDec) Decode(r io.Reader) error {
+	var l uint32
+	if err := perunio.Decode(r, &l); err != nil {
+		return errors.WithMessage(err, "decoding app data length")
+	}
+	if err := perunio.AllocBudget(r, int(l)); err != nil {
+		return err
+	}
+	// Copy instead of allocating l bytes up front, so that a forged length
+	// cannot trigger a large allocation.
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(l)); err != nil {
+		return errors.Wrap(err, "reading app data")
+	}
+	data, err := d.App.DecodeData(&buf)
+	if err != nil {
+		return errors.WithMessage(err, "decoding app data")
+	}
+	*d.Data = data
+	return nil
+}
+
 // AppShouldEqual compares two Apps for equality.
 func AppShouldEqual(expected, actual App) error {
 	if IsNoApp(expected) && IsNoApp(actual) {
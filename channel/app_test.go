@@ -15,12 +15,18 @@
 package channel_test
 
 import (
+	"bytes"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/channel/test"
+	perunio "perun.network/go-perun/pkg/io"
 	pkgtest "perun.network/go-perun/pkg/test"
+	wallettest "perun.network/go-perun/wallet/test"
 )
 
 func TestAppShouldEqual(t *testing.T) {
@@ -36,3 +42,51 @@ func TestAppShouldEqual(t *testing.T) {
 	assert.EqualError(t, channel.AppShouldEqual(napp, app1), "(non-)nil App definitions")
 	assert.NoError(t, channel.AppShouldEqual(napp, napp))
 }
+
+// mockOpV2 is a later version of the MockApp's data that appends a field.
+type mockOpV2 struct {
+	channel.MockOp
+	Extra uint64
+}
+
+func (o mockOpV2) Encode(w io.Writer) error {
+	return perunio.Encode(w, o.MockOp, o.Extra)
+}
+
+func (o mockOpV2) Clone() channel.Data {
+	return &o
+}
+
+func TestAppData(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	app := channel.NewMockApp(wallettest.NewRandomAddress(rng))
+
+	t.Run("serialization", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, perunio.Encode(&buf, channel.AppDataEnc{Data: channel.NewMockOp(channel.OpErr)}))
+		var data channel.Data
+		require.NoError(t, channel.AppDataDec{App: app, Data: &data}.Decode(&buf))
+		assert.Equal(t, channel.NewMockOp(channel.OpErr), data)
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("unread bytes are skipped", func(t *testing.T) {
+		var buf bytes.Buffer
+		v2 := mockOpV2{MockOp: channel.OpTransitionErr, Extra: 42}
+		require.NoError(t, perunio.Encode(&buf, channel.AppDataEnc{Data: v2}, uint64(7)))
+
+		var data channel.Data
+		var next uint64
+		require.NoError(t, perunio.Decode(&buf, channel.AppDataDec{App: app, Data: &data}, &next))
+		assert.Equal(t, channel.NewMockOp(channel.OpTransitionErr), data)
+		assert.Equal(t, uint64(7), next)
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, perunio.Encode(&buf, channel.AppDataEnc{Data: channel.NewMockOp(channel.OpValid)}))
+		var data channel.Data
+		err := channel.AppDataDec{App: app, Data: &data}.Decode(perunio.NewBudgetReader(&buf, 7))
+		assert.ErrorIs(t, err, perunio.ErrDecodeBudgetExceeded)
+	})
+}
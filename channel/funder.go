@@ -86,8 +86,7 @@ func (e FundingTimeoutError) Error() string {
 
 // IsFundingTimeoutError checks whether an error is a FundingTimeoutError.
 func IsFundingTimeoutError(err error) bool {
-	_, ok := errors.Cause(err).(FundingTimeoutError)
-	return ok
+	return errors.As(err, new(FundingTimeoutError))
 }
 
 func (e AssetFundingError) Error() string {
@@ -0,0 +1,167 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	perunio "perun.network/go-perun/pkg/io"
+)
+
+// The JSON representations of channel types. They are meant for logging and
+// analysis and can not be decoded. Byte strings, like IDs, addresses, encoded
+// assets and app data, are hex encoded with a 0x prefix. Balances are decimal
+// strings to not lose precision.
+type (
+	jsonParams struct {
+		ID                string   `json:"id"`
+		ChallengeDuration uint64   `json:"challengeDuration"`
+		Parts             []string `json:"parts"`
+		App               *string  `json:"app"`
+		Nonce             string   `json:"nonce"`
+		LedgerChannel     bool     `json:"ledgerChannel"`
+		VirtualChannel    bool     `json:"virtualChannel"`
+	}
+
+	jsonState struct {
+		ID         string         `json:"id"`
+		Version    uint64         `json:"version"`
+		App        *string        `json:"app"`
+		Allocation jsonAllocation `json:"allocation"`
+		Data       string         `json:"data"`
+		IsFinal    bool           `json:"isFinal"`
+	}
+
+	jsonAllocation struct {
+		Assets   []string       `json:"assets"`
+		Balances [][]string     `json:"balances"`
+		Locked   []jsonSubAlloc `json:"locked"`
+	}
+
+	jsonSubAlloc struct {
+		ID       string   `json:"id"`
+		Bals     []string `json:"bals"`
+		IndexMap []Index  `json:"indexMap"`
+	}
+)
+
+// MarshalJSON returns the JSON representation of the parameters. The app is
+// null for payment channels.
+func (p Params) MarshalJSON() ([]byte, error) {
+	parts := make([]string, len(p.Parts))
+	for i, part := range p.Parts {
+		parts[i] = hexString(part.Bytes())
+	}
+	return json.Marshal(jsonParams{
+		ID:                hexString(p.id[:]),
+		ChallengeDuration: p.ChallengeDuration,
+		Parts:             parts,
+		App:               appJSON(p.App),
+		Nonce:             p.Nonce.String(),
+		LedgerChannel:     p.LedgerChannel,
+		VirtualChannel:    p.VirtualChannel,
+	})
+}
+
+// MarshalJSON returns the JSON representation of the state. The app is null
+// for payment channels. It has a value receiver so that it takes precedence
+// over the promoted method of the embedded Allocation.
+func (s State) MarshalJSON() ([]byte, error) {
+	alloc, err := s.Allocation.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	if s.Data != nil {
+		if data, err = encodeToBytes(s.Data); err != nil {
+			return nil, errors.WithMessage(err, "encoding app data")
+		}
+	}
+	return json.Marshal(jsonState{
+		ID:         hexString(s.ID[:]),
+		Version:    s.Version,
+		App:        appJSON(s.App),
+		Allocation: alloc,
+		Data:       hexString(data),
+		IsFinal:    s.IsFinal,
+	})
+}
+
+// MarshalJSON returns the JSON representation of the allocation. The assets
+// are represented by their hex encoded binary encoding.
+func (a Allocation) MarshalJSON() ([]byte, error) {
+	alloc, err := a.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(alloc)
+}
+
+func (a Allocation) toJSON() (jsonAllocation, error) {
+	alloc := jsonAllocation{
+		Assets:   make([]string, len(a.Assets)),
+		Balances: make([][]string, len(a.Balances)),
+		Locked:   make([]jsonSubAlloc, len(a.Locked)),
+	}
+	for i, asset := range a.Assets {
+		enc, err := encodeToBytes(asset)
+		if err != nil {
+			return alloc, errors.WithMessagef(err, "encoding asset %d", i)
+		}
+		alloc.Assets[i] = hexString(enc)
+	}
+	for i, bals := range a.Balances {
+		alloc.Balances[i] = balsJSON(bals)
+	}
+	for i, sub := range a.Locked {
+		alloc.Locked[i] = jsonSubAlloc{
+			ID:       hexString(sub.ID[:]),
+			Bals:     balsJSON(sub.Bals),
+			IndexMap: sub.IndexMap,
+		}
+	}
+	return alloc, nil
+}
+
+func balsJSON(bals []Bal) []string {
+	strs := make([]string, len(bals))
+	for i, bal := range bals {
+		strs[i] = bal.String()
+	}
+	return strs
+}
+
+// appJSON returns the hex encoded app definition or nil for payment channels.
+func appJSON(app App) *string {
+	if app == nil || IsNoApp(app) {
+		return nil
+	}
+	def := hexString(app.Def().Bytes())
+	return &def
+}
+
+func encodeToBytes(enc perunio.Encoder) ([]byte, error) {
+	var buf bytes.Buffer
+	err := enc.Encode(&buf)
+	return buf.Bytes(), err
+}
+
+func hexString(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
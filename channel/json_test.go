@@ -0,0 +1,88 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+func TestStateMarshalJSON(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	params, state := test.NewRandomParamsAndState(rng, test.WithNumLocked(2))
+
+	var s struct {
+		ID         string  `json:"id"`
+		Version    uint64  `json:"version"`
+		App        *string `json:"app"`
+		Allocation struct {
+			Assets   []string   `json:"assets"`
+			Balances [][]string `json:"balances"`
+			Locked   []struct {
+				ID       string          `json:"id"`
+				Bals     []string        `json:"bals"`
+				IndexMap []channel.Index `json:"indexMap"`
+			} `json:"locked"`
+		} `json:"allocation"`
+		Data    string `json:"data"`
+		IsFinal bool   `json:"isFinal"`
+	}
+	enc, err := json.Marshal(state)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(enc, &s))
+
+	assert.Equal(t, "0x"+hex.EncodeToString(state.ID[:]), s.ID)
+	assert.Equal(t, state.Version, s.Version)
+	assert.Equal(t, state.IsFinal, s.IsFinal)
+	require.Len(t, s.Allocation.Assets, len(state.Assets))
+	require.Len(t, s.Allocation.Balances, len(state.Balances))
+	for i, bals := range state.Balances {
+		for j, bal := range bals {
+			assert.Equal(t, bal.String(), s.Allocation.Balances[i][j])
+		}
+	}
+	require.Len(t, s.Allocation.Locked, 2)
+	for i, sub := range state.Locked {
+		assert.Equal(t, "0x"+hex.EncodeToString(sub.ID[:]), s.Allocation.Locked[i].ID)
+		assert.Equal(t, sub.IndexMap, s.Allocation.Locked[i].IndexMap)
+	}
+	if channel.IsNoApp(state.App) {
+		assert.Nil(t, s.App)
+	} else {
+		require.NotNil(t, s.App)
+		assert.Equal(t, "0x"+hex.EncodeToString(state.App.Def().Bytes()), *s.App)
+	}
+
+	// The encoding is deterministic and independent of the indirection.
+	enc2, err := json.Marshal(*state.Clone())
+	require.NoError(t, err)
+	assert.Equal(t, enc, enc2)
+
+	var p map[string]interface{}
+	enc, err = json.Marshal(params)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(enc, &p))
+	assert.Equal(t, "0x"+hex.EncodeToString(state.ID[:]), p["id"])
+	assert.Equal(t, params.Nonce.String(), p["nonce"])
+	assert.Len(t, p["parts"], len(params.Parts))
+}
@@ -382,6 +382,70 @@ func (m *machine) SetRegistered() error {
 	return nil
 }
 
+// SetRegisteredTX moves the machine into the Registered phase and sets the
+// current transaction to the given one. It is used when a newer state than the
+// current one was registered, e.g., because the newest state got lost locally.
+// The transaction's state must be newer than the current state and must be
+// signed by all participants.
+func (m *machine) SetRegisteredTX(tx Transaction) error {
+	if m.phase < Funding {
+		return m.phaseErrorf(m.selfTransition(), "can only register after init phases")
+	}
+	if err := m.validNewerTX(tx); err != nil {
+		return errors.WithMessage(err, "registered transaction")
+	}
+
+	m.setPhase(Registered)
+	m.addTx(&Transaction{State: tx.State.Clone(), Sigs: tx.Sigs})
+	return nil
+}
+
+// SyncTX sets the current transaction to the given one, which a peer sent
+// during channel synchronization because the own machine missed it, e.g.,
+// because an update message got lost. The machine must be in phase Acting or
+// Signing, in which case the staged update is discarded. The machine moves to
+// phase Final if the transaction's state is final, and to Acting otherwise.
+// The transaction's state must be newer than the current state and must be
+// signed by all participants.
+func (m *machine) SyncTX(tx Transaction) error {
+	if !inPhase(m.phase, []Phase{Acting, Signing}) {
+		return m.phaseErrorf(m.selfTransition(), "can only sync when acting or signing")
+	}
+	if err := m.validNewerTX(tx); err != nil {
+		return errors.WithMessage(err, "synced transaction")
+	}
+
+	if tx.State.IsFinal {
+		m.setPhase(Final)
+	} else {
+		m.setPhase(Acting)
+	}
+	m.addTx(&Transaction{State: tx.State.Clone(), Sigs: tx.Sigs})
+	return nil
+}
+
+// validNewerTX checks that the transaction belongs to the channel, is newer
+// than the current transaction and is signed by all participants.
+func (m *machine) validNewerTX(tx Transaction) error {
+	if tx.State == nil || tx.State.ID != m.ID() {
+		return errors.New("state does not belong to channel")
+	}
+	if tx.State.Version <= m.currentTX.Version {
+		return errors.Errorf("version %d not newer than current version %d", tx.State.Version, m.currentTX.Version)
+	}
+	if len(tx.Sigs) != int(m.N()) {
+		return errors.Errorf("expected %d signatures, got %d", m.N(), len(tx.Sigs))
+	}
+	for i, sig := range tx.Sigs {
+		if ok, err := Verify(m.params.Parts[i], &m.params, tx.State, sig); err != nil {
+			return errors.WithMessagef(err, "verifying signature %d", i)
+		} else if !ok {
+			return errors.Errorf("invalid signature %d", i)
+		}
+	}
+	return nil
+}
+
 // SetProgressing sets the machine phase to Progressing and the staging state to
 // the given state.
 func (m *machine) SetProgressing(state *State) error {
@@ -17,10 +17,13 @@ package channel_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
 	"perun.network/go-perun/channel/test"
 	pkgtest "perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wallet"
 	wtest "perun.network/go-perun/wallet/test"
 )
 
@@ -38,3 +41,136 @@ func TestMachineClone(t *testing.T) {
 	require.NoError(t, err)
 	pkgtest.VerifyClone(t, am)
 }
+
+func TestMachine_SetRegisteredTX(t *testing.T) {
+	rng := pkgtest.Prng(t)
+
+	accs := []wallet.Account{wtest.NewRandomAccount(rng), wtest.NewRandomAccount(rng)}
+	params, state := test.NewRandomParamsAndState(rng,
+		test.WithParts(accs[0].Address(), accs[1].Address()), test.WithoutApp())
+	signTx := func(s *channel.State) channel.Transaction {
+		tx := channel.Transaction{State: s, Sigs: make([]wallet.Sig, len(accs))}
+		for i, acc := range accs {
+			var err error
+			tx.Sigs[i], err = channel.Sign(acc, params, s)
+			require.NoError(t, err)
+		}
+		return tx
+	}
+
+	src := persistence.NewChannel()
+	src.ParamsV = params
+	src.CurrentTXV = signTx(state)
+	src.PhaseV = channel.Acting
+	sm, err := channel.RestoreStateMachine(accs[0], src)
+	require.NoError(t, err)
+
+	// Older versions are rejected.
+	assert.Error(t, sm.SetRegisteredTX(signTx(state.Clone())))
+
+	newer := state.Clone()
+	newer.Version++
+	tx := signTx(newer)
+	tx.Sigs[1] = tx.Sigs[0]
+	assert.Error(t, sm.SetRegisteredTX(tx), "invalid signature")
+
+	require.NoError(t, sm.SetRegisteredTX(signTx(newer)))
+	assert.Equal(t, channel.Registered, sm.Phase())
+	assert.Equal(t, newer, sm.State())
+}
+
+func TestMachine_SyncTX(t *testing.T) {
+	rng := pkgtest.Prng(t)
+
+	accs := []wallet.Account{wtest.NewRandomAccount(rng), wtest.NewRandomAccount(rng)}
+	params, state := test.NewRandomParamsAndState(rng,
+		test.WithParts(accs[0].Address(), accs[1].Address()), test.WithoutApp(), test.WithIsFinal(false))
+	signTx := func(s *channel.State) channel.Transaction {
+		tx := channel.Transaction{State: s, Sigs: make([]wallet.Sig, len(accs))}
+		for i, acc := range accs {
+			var err error
+			tx.Sigs[i], err = channel.Sign(acc, params, s)
+			require.NoError(t, err)
+		}
+		return tx
+	}
+
+	src := persistence.NewChannel()
+	src.ParamsV = params
+	src.CurrentTXV = signTx(state)
+	src.PhaseV = channel.Acting
+	sm, err := channel.RestoreStateMachine(accs[0], src)
+	require.NoError(t, err)
+
+	// Older versions are rejected.
+	assert.Error(t, sm.SyncTX(signTx(state.Clone())))
+
+	// A staged update is discarded.
+	staged := state.Clone()
+	staged.Version++
+	require.NoError(t, sm.Update(staged, 0))
+	newer := staged.Clone()
+	newer.Balances = test.NewRandomBalances(rng, test.WithNumAssets(len(newer.Assets)), test.WithNumParts(2))
+	require.NoError(t, sm.SyncTX(signTx(newer)))
+	assert.Equal(t, channel.Acting, sm.Phase())
+	assert.Equal(t, newer, sm.State())
+	assert.Nil(t, sm.StagingState())
+
+	final := newer.Clone()
+	final.Version++
+	final.IsFinal = true
+	require.NoError(t, sm.SyncTX(signTx(final)))
+	assert.Equal(t, channel.Final, sm.Phase())
+
+	// A final channel cannot be synced anymore.
+	afterFinal := final.Clone()
+	afterFinal.Version++
+	assert.Error(t, sm.SyncTX(signTx(afterFinal)))
+}
+
+func TestMachine_UpdateBatch(t *testing.T) {
+	rng := pkgtest.Prng(t)
+
+	accs := []wallet.Account{wtest.NewRandomAccount(rng), wtest.NewRandomAccount(rng)}
+	params, state := test.NewRandomParamsAndState(rng,
+		test.WithParts(accs[0].Address(), accs[1].Address()), test.WithoutApp(), test.WithIsFinal(false))
+	sign := func(acc wallet.Account, s *channel.State) wallet.Sig {
+		sig, err := channel.Sign(acc, params, s)
+		require.NoError(t, err)
+		return sig
+	}
+
+	src := persistence.NewChannel()
+	src.ParamsV = params
+	src.CurrentTXV = channel.Transaction{State: state, Sigs: []wallet.Sig{sign(accs[0], state), sign(accs[1], state)}}
+	src.PhaseV = channel.Acting
+	sm, err := channel.RestoreStateMachine(accs[0], src)
+	require.NoError(t, err)
+
+	const n = 3
+	states := make([]*channel.State, n)
+	sigs := make([]wallet.Sig, n)
+	prev := state
+	for i := range states {
+		states[i] = prev.Clone()
+		states[i].Version++
+		sigs[i] = sign(accs[1], states[i])
+		prev = states[i]
+	}
+
+	assert.NoError(t, sm.CheckUpdates(states, 1, sigs, 1))
+	assert.Error(t, sm.CheckUpdates(states, 1, sigs[:n-1], 1), "missing signature")
+	assert.Error(t, sm.CheckUpdates(states, 1, []wallet.Sig{sigs[0], sigs[1], sigs[0]}, 1), "invalid signature")
+	assert.Error(t, sm.CheckUpdates(states[1:], 1, sigs[1:], 1), "chain must start at the current state")
+	assert.Error(t, sm.UpdateBatch([]*channel.State{states[0], states[2]}, 1), "gap in chain")
+	assert.Error(t, sm.UpdateBatch(nil, 1), "empty batch")
+	assert.Equal(t, channel.Acting, sm.Phase(), "failed batches should not be staged")
+
+	require.NoError(t, sm.UpdateBatch(states, 1))
+	assert.Equal(t, states[n-1], sm.StagingState())
+	require.NoError(t, sm.AddSig(1, sigs[n-1]))
+	_, err = sm.Sig()
+	require.NoError(t, err)
+	require.NoError(t, sm.EnableUpdate())
+	assert.Equal(t, states[n-1], sm.State())
+}
@@ -66,6 +66,24 @@ func (m StateMachine) SetRegistered(ctx context.Context) error {
 	return errors.WithMessage(m.pr.PhaseChanged(ctx, m.StateMachine), "Persister.PhaseChanged")
 }
 
+// SetRegisteredTX calls SetRegisteredTX on the channel.StateMachine and then
+// persists the changed state.
+func (m StateMachine) SetRegisteredTX(ctx context.Context, tx channel.Transaction) error {
+	if err := m.StateMachine.SetRegisteredTX(tx); err != nil {
+		return err
+	}
+	return errors.WithMessage(m.pr.Enabled(ctx, m.StateMachine), "Persister.Enabled")
+}
+
+// SyncTX calls SyncTX on the channel.StateMachine and then persists the
+// changed state.
+func (m StateMachine) SyncTX(ctx context.Context, tx channel.Transaction) error {
+	if err := m.StateMachine.SyncTX(tx); err != nil {
+		return err
+	}
+	return errors.WithMessage(m.pr.Enabled(ctx, m.StateMachine), "Persister.Enabled")
+}
+
 // SetProgressing calls SetProgressing on the channel.StateMachine and then
 // persists the changed state.
 func (m StateMachine) SetProgressing(ctx context.Context, s *channel.State) error {
@@ -137,6 +155,19 @@ func (m StateMachine) ForceUpdate(
 	return errors.WithMessage(m.pr.Staged(ctx, m.StateMachine), "Persister.Staged")
 }
 
+// UpdateBatch calls UpdateBatch on the channel.StateMachine and then persists
+// the changed staging state.
+func (m StateMachine) UpdateBatch(
+	ctx context.Context,
+	states []*channel.State,
+	actor channel.Index,
+) error {
+	if err := m.StateMachine.UpdateBatch(states, actor); err != nil {
+		return err
+	}
+	return errors.WithMessage(m.pr.Staged(ctx, m.StateMachine), "Persister.Staged")
+}
+
 // Sig calls Sig on the channel.StateMachine and then persists the added
 // signature.
 func (m StateMachine) Sig(ctx context.Context) (sig wallet.Sig, err error) {
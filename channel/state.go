@@ -108,6 +108,13 @@ func (s State) Encode(w io.Writer) error {
 	return errors.WithMessage(err, "state encode")
 }
 
+// EncodeTo appends the encoding of the state to buf and returns the extended
+// buffer. It produces the same encoding as Encode.
+func (s State) EncodeTo(buf []byte) ([]byte, error) {
+	buf, err := perunio.EncodeTo(buf, s)
+	return buf, errors.WithMessage(err, "state encode")
+}
+
 // Decode decodes a state from an `io.Reader` or returns an `error`.
 func (s *State) Decode(r io.Reader) error {
 	// Decode ID, Version, Allocation, IsFinal, App
@@ -15,11 +15,16 @@
 package channel_test
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	_ "perun.network/go-perun/backend/sim" // backend init
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/channel/test"
+	perunio "perun.network/go-perun/pkg/io"
 	iotest "perun.network/go-perun/pkg/io/test"
 	pkgtest "perun.network/go-perun/pkg/test"
 )
@@ -32,7 +37,32 @@ func TestStateSerialization(t *testing.T) {
 	iotest.GenericSerializerTest(t, state)
 	test.GenericStateEqualTest(t, state, state2)
 
+	var enc bytes.Buffer
+	require.NoError(t, state.Encode(&enc))
+	buf, err := state.EncodeTo(nil)
+	require.NoError(t, err)
+	assert.Equal(t, enc.Bytes(), buf, "EncodeTo and Encode should produce the same encoding")
+
 	state.App = channel.NoApp()
 	state.Data = channel.NoData()
 	iotest.GenericSerializerTest(t, state)
 }
+
+func TestStateEncodingLayout(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	state := test.NewRandomState(rng)
+
+	// The app data must be encoded inline, so that persisted states and
+	// peers of earlier versions stay compatible.
+	var baseline bytes.Buffer
+	require.NoError(t, perunio.Encode(&baseline,
+		state.ID, state.Version, state.Allocation, state.IsFinal, channel.OptAppEnc{App: state.App}, state.Data))
+
+	var enc bytes.Buffer
+	require.NoError(t, state.Encode(&enc))
+	assert.Equal(t, baseline.Bytes(), enc.Bytes())
+
+	var decoded channel.State
+	require.NoError(t, decoded.Decode(&baseline))
+	assert.NoError(t, decoded.Equal(state))
+}
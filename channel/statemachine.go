@@ -124,6 +124,57 @@ func (m *StateMachine) CheckUpdate(
 	return nil
 }
 
+// UpdateBatch makes the last of the provided states the staging state. The
+// states must form a chain of valid transitions by the given actor, starting
+// at the current state. Enabling the staging state thus skips the intermediate
+// states.
+func (m *StateMachine) UpdateBatch(states []*State, actor Index) error {
+	if err := m.expect(PhaseTransition{Acting, Signing}); err != nil {
+		return err
+	}
+	if len(states) == 0 {
+		return errors.New("empty batch")
+	}
+
+	if err := m.checkChain(states, func(chain *StateMachine, i int) error {
+		return chain.validTransition(states[i], actor)
+	}); err != nil {
+		return err
+	}
+
+	m.setStaging(Signing, states[len(states)-1])
+	return nil
+}
+
+// CheckUpdates checks if the given states form a chain of valid transitions
+// starting at the current state, and if each state is signed by participant
+// sigIdx with the respective signature. It is a read-only operation that does
+// not advance the state machine.
+func (m *StateMachine) CheckUpdates(
+	states []*State, actor Index,
+	sigs []wallet.Sig, sigIdx Index,
+) error {
+	if len(states) != len(sigs) {
+		return errors.Errorf("got %d states but %d signatures", len(states), len(sigs))
+	}
+	return m.checkChain(states, func(chain *StateMachine, i int) error {
+		return chain.CheckUpdate(states[i], actor, sigs[i], sigIdx)
+	})
+}
+
+// checkChain calls check for each of the given states on a copy of the state
+// machine whose current state is the predecessor of the checked state.
+func (m *StateMachine) checkChain(states []*State, check func(chain *StateMachine, i int) error) error {
+	chain := m.Clone()
+	for i, s := range states {
+		if err := check(chain, i); err != nil {
+			return errors.WithMessagef(err, "checking transition %d of batch", i)
+		}
+		chain.currentTX = *chain.newTransaction(s)
+	}
+	return nil
+}
+
 // validTransition makes all the default transition checks and additionally
 // checks for a valid application specific transition.
 // This is where a StateMachine and ActionMachine differ. In an ActionMachine,
@@ -16,11 +16,17 @@ package client
 
 import (
 	"context"
+	"math/big"
+	"reflect"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/pkg/clock"
 	"perun.network/go-perun/pkg/sync"
+	"perun.network/go-perun/wallet"
 	"perun.network/go-perun/wire"
 )
 
@@ -29,25 +35,127 @@ type AdjudicatorEventHandler interface {
 	HandleAdjudicatorEvent(channel.AdjudicatorEvent)
 }
 
+type (
+	// WatchOption configures the behavior of Channel.Watch.
+	WatchOption func(*watchOpts)
+
+	watchOpts struct {
+		noAutoRefute bool
+		checkpointer Checkpointer
+		strategy     DisputeStrategy
+	}
+
+	// Checkpointer persists the progress of a channel watcher, so that a
+	// restarted watcher does not process the same events again and resumes
+	// where it stopped.
+	Checkpointer interface {
+		// Checkpoint is called by the watcher after it processed an event of
+		// the channel.
+		Checkpoint(ctx context.Context, id channel.ID, cp WatchCheckpoint) error
+
+		// LastCheckpoint returns the last checkpoint of the channel, or nil if
+		// there is none. It is called when the watcher starts.
+		LastCheckpoint(ctx context.Context, id channel.ID) (*WatchCheckpoint, error)
+	}
+
+	// WatchCheckpoint is the progress of a channel watcher.
+	WatchCheckpoint struct {
+		// Event is the last processed event.
+		Event channel.AdjudicatorEvent
+		// Block is the block number of Event, if the adjudicator is a
+		// ResumableAdjudicator. It is zero otherwise.
+		Block uint64
+		// Index is the index of Event among the events of its block, if the
+		// adjudicator is a ResumableAdjudicator. It is zero otherwise.
+		Index uint
+	}
+
+	// ResumableAdjudicator is an adjudicator whose subscriptions can start at
+	// a given block. If the client's adjudicator implements it, a watcher
+	// with a Checkpointer resumes at the block of the last checkpoint, so that
+	// no events are missed while the watcher was not running. The events up
+	// to the position of the last checkpoint are not notified again.
+	ResumableAdjudicator interface {
+		channel.Adjudicator
+
+		// SubscribeFrom is like Subscribe, but reads past events starting at
+		// the given block.
+		SubscribeFrom(ctx context.Context, params *channel.Params, block uint64) (channel.AdjudicatorSubscription, error)
+
+		// EventPosition returns the number of the block that contains an
+		// event of this adjudicator and the index of the event among the
+		// events of the block. It returns false if the position is unknown.
+		EventPosition(e channel.AdjudicatorEvent) (block uint64, index uint, ok bool)
+	}
+)
+
+// WithoutAutoRefute makes Channel.Watch only observe the adjudicator. If an
+// outdated state is registered, the handler is notified with the
+// RegisteredEvent as usual, but the watcher does not refute it. It is then up
+// to the caller, e.g., an external watchtower, to call Channel.Register.
+func WithoutAutoRefute() WatchOption {
+	return func(o *watchOpts) { o.noAutoRefute = true }
+}
+
+// WithCheckpointer makes Channel.Watch checkpoint every processed event with
+// the given Checkpointer. On startup, the watcher reads the last checkpoint.
+// If the client's adjudicator is a ResumableAdjudicator, the watcher resumes
+// at the block of the checkpointed event. If the subscription replays the
+// checkpointed event or events before it, the watcher processes them as
+// usual, but does not notify the handler about them again.
+func WithCheckpointer(cp Checkpointer) WatchOption {
+	return func(o *watchOpts) { o.checkpointer = cp }
+}
+
 // Watch watches the adjudicator for channel events and responds accordingly.
 // The handler is notified about the corresponding events.
 //
 // The routine takes care that if an old state is registered, the on-chain state
 // is refuted with the most recent event available by registering the channel
 // tree. In such a case, the handler may receive multiple registered events in
-// short succession.
+// short succession. This can be disabled with the WithoutAutoRefute option, or
+// replaced by a custom reaction with the WithDisputeStrategy option.
+//
+// If a newer state than the local one is registered, it is adopted as the
+// current state if it is signed by all participants.
 //
+// The handler is notified asynchronously. Use Client.SetEventHandlerPool to
+// bound the number of concurrent handler calls and deliver the events of the
+// channel in order.
+//
+// Returns FutureVersionRegisteredError if a newer state than the local one is
+// registered that cannot be adopted.
 // Returns TxTimedoutError when watcher refutes with the most recent state and
 // the program times out waiting for a transaction to be mined.
 // Returns ChainNotReachableError if the connection to the blockchain network
 // fails when sending a transaction to / reading from the blockchain.
-func (c *Channel) Watch(h AdjudicatorEventHandler) error {
+func (c *Channel) Watch(h AdjudicatorEventHandler, opts ...WatchOption) error {
+	if !c.client.watchers.add() {
+		return errors.New("client closed")
+	}
+	defer c.client.watchers.done()
+
+	var o watchOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	log := c.Log().WithField("proc", "watcher")
 	defer log.Info("Watcher returned.")
 
-	// Subscribe to state changes
 	ctx := c.Ctx()
-	sub, err := c.adjudicator.Subscribe(ctx, c.Params())
+	var (
+		last *WatchCheckpoint
+		err  error
+	)
+	if o.checkpointer != nil {
+		if last, err = o.checkpointer.LastCheckpoint(ctx, c.ID()); err != nil {
+			return errors.WithMessage(err, "reading last checkpoint")
+		}
+	}
+
+	// Subscribe to state changes
+	sub, err := c.subscribe(ctx, last)
 	if err != nil {
 		return errors.WithMessage(err, "subscribing to adjudicator state changes")
 	}
@@ -56,6 +164,9 @@ func (c *Channel) Watch(h AdjudicatorEventHandler) error {
 	// nolint:errcheck,gosec
 	c.OnCloseAlways(func() { sub.Close() })
 
+	notify := c.client.eventDispatcher(h)
+	strategy := o.disputeStrategy()
+
 	// Wait for state changed event
 	for e := sub.Next(); e != nil; e = sub.Next() {
 		log.Infof("event %v", e)
@@ -67,22 +178,34 @@ func (c *Channel) Watch(h AdjudicatorEventHandler) error {
 
 		// Special handling of RegisteredEvent
 		if e, ok := e.(*channel.RegisteredEvent); ok {
-			// Assert backend version not greater than local version.
+			// A registered version greater than the local version should never
+			// happen, unless we lost the newest state. Adopt the registered state
+			// if it is signed by all participants.
 			if e.Version() > c.State().Version {
-				// If the implementation works as intended, this should never happen.
-				log.Panicf("watch: registered: expected version less than or equal to %d, got version %d", c.machine.State().Version, e.Version)
-			}
-
-			// If local version greater than backend version, register local state.
-			if e.Version() < c.State().Version {
-				if err := c.Register(ctx); err != nil {
-					return errors.WithMessage(err, "registering")
+				log.Errorf("Registered version %d greater than local version %d", e.Version(), c.State().Version)
+				if err := c.adoptRegistered(ctx, e); err != nil {
+					return err
 				}
 			}
 		}
 
-		// Notify handler
-		go h.HandleAdjudicatorEvent(e)
+		// React to the event, e.g., refute an outdated registration.
+		if err := strategy.OnAdjudicatorEvent(ctx, c, e); err != nil {
+			return err
+		}
+
+		// Notify handler, unless the event was already processed before.
+		if c.isCheckpointed(last, e) {
+			log.Debugf("Not notifying about checkpointed event %v", e)
+			continue
+		}
+		notify(e)
+
+		if o.checkpointer != nil {
+			if err := o.checkpointer.Checkpoint(ctx, c.ID(), c.checkpoint(e)); err != nil {
+				return errors.WithMessage(err, "checkpointing event")
+			}
+		}
 	}
 
 	err = sub.Err()
@@ -90,13 +213,78 @@ func (c *Channel) Watch(h AdjudicatorEventHandler) error {
 	return errors.WithMessage(err, "subscription closed")
 }
 
+// subscribe subscribes to the adjudicator events of the channel. If the
+// adjudicator is a ResumableAdjudicator and the last checkpoint has a block
+// number, the subscription starts at this block.
+func (c *Channel) subscribe(ctx context.Context, last *WatchCheckpoint) (channel.AdjudicatorSubscription, error) {
+	if adj, ok := c.adjudicator.(ResumableAdjudicator); ok && last != nil && last.Block > 0 {
+		return adj.SubscribeFrom(ctx, c.Params(), last.Block)
+	}
+	return c.adjudicator.Subscribe(ctx, c.Params())
+}
+
+// checkpoint returns the checkpoint of the processed event e.
+func (c *Channel) checkpoint(e channel.AdjudicatorEvent) WatchCheckpoint {
+	cp := WatchCheckpoint{Event: e}
+	if adj, ok := c.adjudicator.(ResumableAdjudicator); ok {
+		cp.Block, cp.Index, _ = adj.EventPosition(e)
+	}
+	return cp
+}
+
+// isCheckpointed returns whether e was processed before checkpoint cp was
+// taken. If the adjudicator is a ResumableAdjudicator that knows the
+// positions of cp and e, this is the case if e is at or before the position
+// of cp. Otherwise, e must be the event of cp.
+func (c *Channel) isCheckpointed(cp *WatchCheckpoint, e channel.AdjudicatorEvent) bool {
+	if cp == nil {
+		return false
+	}
+	if adj, ok := c.adjudicator.(ResumableAdjudicator); ok && cp.Block > 0 {
+		if block, index, ok := adj.EventPosition(e); ok {
+			return block < cp.Block || (block == cp.Block && index <= cp.Index)
+		}
+	}
+	return cp.Event != nil && sameEvent(cp.Event, e)
+}
+
+// sameEvent returns whether a and b are the same event. Events are compared by
+// their type, channel ID, version and, for registered and progressed events,
+// their state. Timeouts are not compared because they are not comparable by
+// value, e.g., a timeout on a blockchain holds a connection to it, which a
+// restored timeout lacks.
+func sameEvent(a, b channel.AdjudicatorEvent) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) ||
+		a.ID() != b.ID() ||
+		a.Version() != b.Version() {
+		return false
+	}
+
+	switch a := a.(type) {
+	case *channel.RegisteredEvent:
+		return sameState(a.State, b.(*channel.RegisteredEvent).State)
+	case *channel.ProgressedEvent:
+		b := b.(*channel.ProgressedEvent)
+		return a.Idx == b.Idx && sameState(a.State, b.State)
+	}
+	return true
+}
+
+// sameState returns whether a and b are equal or both nil.
+func sameState(a, b *channel.State) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b) == nil
+}
+
 // Register registers the channel and all its relatives on the adjudicator.
 //
 // Returns TxTimedoutError when the program times out waiting for a transaction
 // to be mined.
 // Returns ChainNotReachableError if the connection to the blockchain network
 // fails when sending a transaction to / reading from the blockchain.
-func (c *Channel) Register(ctx context.Context) error {
+func (c *Channel) Register(ctx context.Context) (err error) {
 	// If this is not the root, go up one level.
 	// Once we are at the root, we register the whole channel tree together.
 	if c.parent != nil {
@@ -120,6 +308,14 @@ func (c *Channel) Register(ctx context.Context) error {
 		return errors.WithMessage(err, "gathering sub-channel states")
 	}
 
+	alog := c.logAdjudicate("register", c.machine.State().Version)
+	alog.WithField("subChannels", len(subStates)).Debug("Registering.")
+	defer func() {
+		if err != nil {
+			alog.WithError(err).Warn("Registering failed.")
+		}
+	}()
+
 	err = c.adjudicator.Register(ctx, c.machine.AdjudicatorReq(), subStates)
 	if err != nil {
 		return errors.WithMessage(err, "calling Register")
@@ -130,16 +326,49 @@ func (c *Channel) Register(ctx context.Context) error {
 		return errors.WithMessage(err, "setting phase `Registered` recursive")
 	}
 
+	alog.Info("Registered.")
 	return nil
 }
 
+type (
+	// ProgressOption configures the behavior of Channel.ProgressBy.
+	ProgressOption func(*progressOpts)
+
+	progressOpts struct {
+		deadline time.Time
+	}
+)
+
+// WithProgressDeadline makes Channel.ProgressBy give up if the progression is
+// not done by the given deadline, e.g., because the turn of the own
+// participant ends then. It only shortens the deadline of the context passed
+// to ProgressBy.
+func WithProgressDeadline(deadline time.Time) ProgressOption {
+	return func(o *progressOpts) { o.deadline = deadline }
+}
+
 // ProgressBy progresses the channel state in the adjudicator backend.
 //
 // Returns TxTimedoutError when the program times out waiting for a transaction
 // to be mined.
 // Returns ChainNotReachableError if the connection to the blockchain network
 // fails when sending a transaction to / reading from the blockchain.
-func (c *Channel) ProgressBy(ctx context.Context, update func(*channel.State)) error {
+func (c *Channel) ProgressBy(ctx context.Context, update func(*channel.State), opts ...ProgressOption) (err error) {
+	if c.watchOnly {
+		return errors.WithStack(ErrWatchOnly)
+	}
+
+	var o progressOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.deadline.IsZero() {
+		var cancel context.CancelFunc
+		clk := c.client.clock
+		ctx, cancel = clock.WithTimeout(ctx, clk, o.deadline.Sub(clk.Now()))
+		defer cancel()
+	}
+
 	// Lock machine
 	if !c.machMtx.TryLockCtx(ctx) {
 		return errors.Errorf("locking machine mutex in time: %v", ctx.Err())
@@ -154,6 +383,14 @@ func (c *Channel) ProgressBy(ctx context.Context, update func(*channel.State)) e
 	state.Version++
 	update(state)
 
+	alog := c.logAdjudicate("progress", state.Version)
+	alog.Debug("Progressing.")
+	defer func() {
+		if err != nil {
+			alog.WithError(err).Warn("Progressing failed.")
+		}
+	}()
+
 	// Apply state in machine and generate signature
 	if err := c.machine.SetProgressing(ctx, state); err != nil {
 		return errors.WithMessage(err, "updating machine")
@@ -165,7 +402,11 @@ func (c *Channel) ProgressBy(ctx context.Context, update func(*channel.State)) e
 
 	// Create and send request
 	pr := channel.NewProgressReq(ar, state, sig)
-	return errors.WithMessage(c.adjudicator.Progress(ctx, *pr), "progressing")
+	if err := c.adjudicator.Progress(ctx, *pr); err != nil {
+		return errors.WithMessage(err, "progressing")
+	}
+	alog.Info("Progressed.")
+	return nil
 }
 
 // Settle concludes the channel and withdraws the funds.
@@ -181,14 +422,92 @@ func (c *Channel) ProgressBy(ctx context.Context, update func(*channel.State)) e
 // to be mined.
 // Returns ChainNotReachableError if the connection to the blockchain network
 // fails when sending a transaction to / reading from the blockchain.
-func (c *Channel) Settle(ctx context.Context, secondary bool) (err error) {
+func (c *Channel) Settle(ctx context.Context, secondary bool, opts ...SettleOption) error {
+	_, err := c.SettleWithResult(ctx, secondary, opts...)
+	return err
+}
+
+type (
+	// SettleOption configures the behavior of Channel.Settle.
+	SettleOption func(*settleOpts)
+
+	settleOpts struct {
+		receiver wallet.Address
+	}
+
+	// ReceiverWithdrawer can optionally be implemented by a
+	// channel.Adjudicator to withdraw the funds of the withdrawing
+	// participant to a given receiver. It is used by Channel.Settle with
+	// WithReceiver.
+	ReceiverWithdrawer interface {
+		// WithdrawTo is like Withdraw, but sends the funds of the
+		// participant of req to receiver.
+		WithdrawTo(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap, receiver wallet.Address) error
+	}
+
+	// Concluder can optionally be implemented by a channel.Adjudicator to
+	// conclude a channel without withdrawing its funds. It is used by
+	// Channel.ConcludeForced.
+	Concluder interface {
+		// Conclude concludes the channel with the state of req, which must be
+		// the registered or last progressed state of the channel. The funds
+		// stay in the adjudicator until they are withdrawn.
+		Conclude(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error
+	}
+)
+
+// WithReceiver makes Channel.Settle withdraw our funds to the given receiver
+// instead of the default receiver of the adjudicator. It only applies to
+// ledger channels and requires that the adjudicator implements
+// ReceiverWithdrawer.
+func WithReceiver(receiver wallet.Address) SettleOption {
+	return func(o *settleOpts) { o.receiver = receiver }
+}
+
+// SettleResult is returned by SettleWithResult. It states the amounts that
+// were withdrawn when the channel was settled.
+type SettleResult struct {
+	// Outcome contains the withdrawn amounts per asset and participant. For
+	// ledger channels, it includes the outcome of all sub-channels. For
+	// sub-channels and virtual channels, it contains the amounts that were
+	// withdrawn into the parent channel.
+	Outcome channel.Balances
+	// Withdrawn contains the own withdrawn amount per asset.
+	Withdrawn []channel.Bal
+}
+
+// SettleWithResult is like Settle, but additionally returns the amounts that
+// were withdrawn. They are derived from the concluded state, so that they can
+// be reconciled with the on-chain transfers without reading the chain.
+func (c *Channel) SettleWithResult(ctx context.Context, secondary bool, opts ...SettleOption) (_ *SettleResult, err error) {
+	var o settleOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.receiver != nil {
+		if !c.IsLedgerChannel() {
+			return nil, errors.New("receiver only applies to ledger channels")
+		}
+		if _, ok := c.adjudicator.(ReceiverWithdrawer); !ok {
+			return nil, errors.New("adjudicator cannot withdraw to a receiver")
+		}
+	}
+
 	// Lock machines of channel and all subchannels recursively.
 	l, err := c.tryLockRecursive(ctx)
 	defer l.Unlock()
 	if err != nil {
-		return errors.WithMessage(err, "locking recursive")
+		return nil, errors.WithMessage(err, "locking recursive")
 	}
 
+	alog := c.logAdjudicate("settle", c.machine.State().Version)
+	alog.WithField("secondary", secondary).Debug("Settling.")
+	defer func() {
+		if err != nil {
+			alog.WithError(err).Warn("Settling failed.")
+		}
+	}()
+
 	// Set phase `Withdrawing`.
 	if err = c.applyRecursive(func(c *Channel) error {
 		if c.machine.Phase() == channel.Withdrawn {
@@ -196,13 +515,17 @@ func (c *Channel) Settle(ctx context.Context, secondary bool) (err error) {
 		}
 		return c.machine.SetWithdrawing(ctx)
 	}); err != nil {
-		return errors.WithMessage(err, "setting phase `Withdrawing` recursive")
+		return nil, errors.WithMessage(err, "setting phase `Withdrawing` recursive")
 	}
 
 	// Settle.
-	err = c.settle(ctx, secondary)
+	res, err := c.settleResult()
 	if err != nil {
-		return
+		return nil, err
+	}
+	err = c.settle(ctx, secondary, o)
+	if err != nil {
+		return nil, err
 	}
 
 	// Set phase `Withdrawn`.
@@ -213,11 +536,15 @@ func (c *Channel) Settle(ctx context.Context, secondary bool) (err error) {
 		}
 		return c.machine.SetWithdrawn(ctx)
 	}); err != nil {
-		return errors.WithMessage(err, "setting phase `Withdrawn` recursive")
+		return nil, errors.WithMessage(err, "setting phase `Withdrawn` recursive")
 	}
 
 	// Decrement account usage.
 	if err = c.applyRecursive(func(c *Channel) (err error) {
+		// Skip watch-only channels, they do not use the account.
+		if c.watchOnly {
+			return
+		}
 		// Skip if we are not a participant, e.g., if this is a virtual channel and we are the hub.
 		if c.IsVirtualChannel() {
 			ourID := c.parent.Peers()[c.parent.Idx()]
@@ -228,14 +555,116 @@ func (c *Channel) Settle(ctx context.Context, secondary bool) (err error) {
 		c.wallet.DecrementUsage(c.machine.Account().Address())
 		return
 	}); err != nil {
-		return errors.WithMessage(err, "decrementing account usage")
+		return nil, errors.WithMessage(err, "decrementing account usage")
 	}
 
-	c.Log().Info("Withdrawal successful.")
-	return nil
+	alog.WithField("withdrawn", res.Withdrawn).Info("Withdrawal successful.")
+	return res, nil
+}
+
+// logAdjudicate returns a logger for the adjudicator operation op on the state
+// with the given version.
+func (c *Channel) logAdjudicate(op string, version uint64) log.Logger {
+	return c.Log().WithFields(log.Fields{
+		"adjudicate": op,
+		"version":    version,
+	})
+}
+
+// ConcludeForced concludes a registered ledger channel whose participants do
+// not agree on a final state, e.g., an app channel stuck mid-game. The
+// registered or last progressed state becomes the outcome of the channel.
+// This is only possible after the challenge timeout of that state has elapsed
+// and requires that the adjudicator implements Concluder.
+//
+// Unlike Settle, the conclusion is built from the state that is registered
+// on-chain, which may differ from the local state if another participant
+// progressed the channel, and the funds are not withdrawn. Call Settle
+// afterwards to withdraw them.
+//
+// Returns ChallengeNotElapsedError if the challenge timeout has not elapsed
+// yet.
+func (c *Channel) ConcludeForced(ctx context.Context) error {
+	if !c.IsLedgerChannel() {
+		return errors.New("only ledger channels can be concluded on-chain")
+	}
+	concluder, ok := c.adjudicator.(Concluder)
+	if !ok {
+		return errors.New("adjudicator cannot conclude without withdrawing")
+	}
+	if phase := c.Phase(); phase != channel.Registered && phase != channel.Progressed {
+		return errors.Errorf("channel must be registered, but is in phase %v", phase)
+	}
+
+	e, err := c.latestAdjudicatorEvent(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "reading registered state")
+	}
+	if !e.Timeout().IsElapsed(ctx) {
+		return errors.WithStack(ChallengeNotElapsedError{Version: e.Version()})
+	}
+
+	// Lock machines of channel and all subchannels recursively.
+	l, err := c.tryLockRecursive(ctx)
+	defer l.Unlock()
+	if err != nil {
+		return errors.WithMessage(err, "locking recursive")
+	}
+
+	req, err := c.registeredAdjudicatorReq(e)
+	if err != nil {
+		return err
+	}
+	subStates, err := c.subChannelStateMap()
+	if err != nil {
+		return errors.WithMessage(err, "creating sub-channel state map")
+	}
+
+	c.logAdjudicate("conclude", req.Tx.Version).Debug("Concluding forced.")
+	return errors.WithMessage(concluder.Conclude(ctx, req, subStates), "calling Conclude")
+}
+
+// registeredAdjudicatorReq returns the AdjudicatorReq of the channel for the
+// state that was registered or progressed into with event e.
+func (c *Channel) registeredAdjudicatorReq(e channel.AdjudicatorEvent) (channel.AdjudicatorReq, error) {
+	req := c.machine.AdjudicatorReq()
+	switch e := e.(type) {
+	case *channel.RegisteredEvent:
+		req.Tx = channel.Transaction{State: e.State, Sigs: e.Sigs}
+	case *channel.ProgressedEvent:
+		req.Tx = channel.Transaction{State: e.State}
+	default:
+		return req, errors.Errorf("unexpected adjudicator event %T", e)
+	}
+	if req.Tx.State == nil {
+		return req, errors.Errorf("%T carries no state", e)
+	}
+	return req, nil
+}
+
+// latestAdjudicatorEvent returns the latest adjudicator event of the channel.
+func (c *Channel) latestAdjudicatorEvent(ctx context.Context) (channel.AdjudicatorEvent, error) {
+	sub, err := c.adjudicator.Subscribe(ctx, c.Params())
+	if err != nil {
+		return nil, errors.WithMessage(err, "subscribing to adjudicator events")
+	}
+	// nolint:errcheck
+	defer sub.Close()
+
+	next := make(chan channel.AdjudicatorEvent, 1)
+	go func() { next <- sub.Next() }()
+	select {
+	case e := <-next:
+		if e == nil {
+			return nil, errors.WithMessage(sub.Err(), "subscription closed")
+		}
+		return e, nil
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "waiting for adjudicator event")
+	}
 }
 
-func (c *Channel) settle(ctx context.Context, secondary bool) error {
+func (c *Channel) settle(ctx context.Context, secondary bool, o settleOpts) error {
 	switch {
 	case c.IsLedgerChannel():
 		subStates, err := c.subChannelStateMap()
@@ -244,7 +673,12 @@ func (c *Channel) settle(ctx context.Context, secondary bool) error {
 		}
 		req := c.machine.AdjudicatorReq()
 		req.Secondary = secondary
-		if err := c.adjudicator.Withdraw(ctx, req, subStates); err != nil {
+		if o.receiver != nil {
+			// Checked by SettleWithResult.
+			if err := c.adjudicator.(ReceiverWithdrawer).WithdrawTo(ctx, req, subStates, o.receiver); err != nil {
+				return errors.WithMessage(err, "calling WithdrawTo")
+			}
+		} else if err := c.adjudicator.Withdraw(ctx, req, subStates); err != nil {
 			return errors.WithMessage(err, "calling Withdraw")
 		}
 
@@ -270,6 +704,45 @@ func (c *Channel) settle(ctx context.Context, secondary bool) error {
 	return nil
 }
 
+// settleResult returns the amounts that are withdrawn when the channel is
+// settled in its current state.
+func (c *Channel) settleResult() (*SettleResult, error) {
+	outcome := c.state().Balances.Clone()
+	if c.IsLedgerChannel() {
+		subStates, err := c.subChannelStateMap()
+		if err != nil {
+			return nil, errors.WithMessage(err, "creating sub-channel state map")
+		}
+		outcome = outcomeRecursive(c.state(), subStates)
+	}
+
+	withdrawn := make([]channel.Bal, len(outcome))
+	for a, bals := range outcome {
+		withdrawn[a] = new(big.Int).Set(bals[c.Idx()])
+	}
+	return &SettleResult{Outcome: outcome, Withdrawn: withdrawn}, nil
+}
+
+// outcomeRecursive returns the accumulated outcome of the channel and its
+// sub-channels, mapping the outcome of each sub-channel to the participants of
+// the channel.
+func outcomeRecursive(state *channel.State, subStates channel.StateMap) channel.Balances {
+	outcome := state.Balances.Clone()
+	for _, subAlloc := range state.Locked {
+		subOutcome := outcomeRecursive(subStates[subAlloc.ID], subStates)
+		for a, bals := range subOutcome {
+			for p, bal := range bals {
+				_p := p
+				if len(subAlloc.IndexMap) > 0 {
+					_p = int(subAlloc.IndexMap[p])
+				}
+				outcome[a][_p].Add(outcome[a][_p], bal)
+			}
+		}
+	}
+	return outcome
+}
+
 // hasParticipant returns we are participating in the channel.
 func (c *Channel) hasParticipant(id wire.Address) bool {
 	for _, p := range c.Peers() {
@@ -301,6 +774,28 @@ func (c *Channel) setMachinePhase(ctx context.Context, e channel.AdjudicatorEven
 	return
 }
 
+// adoptRegistered sets the registered state of a RegisteredEvent as current
+// state if it is newer than the current state and signed by all participants.
+// Otherwise, a FutureVersionRegisteredError is returned.
+func (c *Channel) adoptRegistered(ctx context.Context, e *channel.RegisteredEvent) error {
+	if !c.machMtx.TryLockCtx(ctx) {
+		return errors.WithMessage(ctx.Err(), "locking machine")
+	}
+	defer c.machMtx.Unlock()
+
+	localVersion := c.machine.State().Version
+	if e.State == nil {
+		return errors.WithStack(FutureVersionRegisteredError{localVersion, e.Version()})
+	}
+	tx := channel.Transaction{State: e.State, Sigs: e.Sigs}
+	if err := c.machine.SetRegisteredTX(ctx, tx); err != nil {
+		return errors.WithMessage(
+			errors.WithStack(FutureVersionRegisteredError{localVersion, e.Version()}), err.Error())
+	}
+	c.Log().Warnf("Adopted registered state with version %d", e.Version())
+	return nil
+}
+
 type mutexList []*sync.Mutex
 
 func (a mutexList) Unlock() {
@@ -309,6 +804,32 @@ func (a mutexList) Unlock() {
 	}
 }
 
+// refundUnfunded registers the initial state of a ledger channel whose funding
+// timed out and withdraws the deposits of its participants.
+func (c *Channel) refundUnfunded(ctx context.Context) error {
+	if !c.machMtx.TryLockCtx(ctx) {
+		return errors.Errorf("locking machine mutex in time: %v", ctx.Err())
+	}
+	defer c.machMtx.Unlock()
+
+	if err := c.machine.SetRegistering(ctx); err != nil {
+		return errors.WithMessage(err, "setting phase `Registering`")
+	}
+	if err := c.adjudicator.Register(ctx, c.machine.AdjudicatorReq(), nil); err != nil {
+		return errors.WithMessage(err, "calling Register")
+	}
+	if err := c.machine.SetRegistered(ctx); err != nil {
+		return errors.WithMessage(err, "setting phase `Registered`")
+	}
+	if err := c.machine.SetWithdrawing(ctx); err != nil {
+		return errors.WithMessage(err, "setting phase `Withdrawing`")
+	}
+	if err := c.adjudicator.Withdraw(ctx, c.machine.AdjudicatorReq(), nil); err != nil {
+		return errors.WithMessage(err, "calling Withdraw")
+	}
+	return errors.WithMessage(c.machine.SetWithdrawn(ctx), "setting phase `Withdrawn`")
+}
+
 // tryLockRecursive tries to lock the channel and all of its sub-channels.
 // It returns a list of all the mutexes that have been locked.
 func (c *Channel) tryLockRecursive(ctx context.Context) (l mutexList, err error) {
@@ -322,10 +843,25 @@ func (c *Channel) tryLockRecursive(ctx context.Context) (l mutexList, err error)
 	return
 }
 
-// applyToSubChannelsRecursive applies the function to all sub-channels recursively.
-func (c *Channel) applyToSubChannelsRecursive(f func(*Channel) error) (err error) {
+// applyToSubChannelsRecursive applies the function to all sub-channels
+// recursively. The sub-channels are visited in depth-first pre-order, in the
+// order of the sub-allocations of the current state. This is the order in
+// which the adjudicator expects the sub-channel states.
+//
+// Returns an error if a sub-channel occurs more than once in the channel tree,
+// e.g., because the tree contains a cycle.
+func (c *Channel) applyToSubChannelsRecursive(f func(*Channel) error) error {
+	visited := map[channel.ID]struct{}{c.ID(): {}}
+	return c.applyToSubChannelsDFS(f, visited)
+}
+
+func (c *Channel) applyToSubChannelsDFS(f func(*Channel) error, visited map[channel.ID]struct{}) (err error) {
 	for _, subAlloc := range c.state().Locked {
 		subID := subAlloc.ID
+		if _, ok := visited[subID]; ok {
+			return errors.Errorf("sub-channel %x occurs more than once in channel tree", subID)
+		}
+		visited[subID] = struct{}{}
 		var subCh *Channel
 		subCh, err = c.client.Channel(subID)
 		if err != nil {
@@ -336,7 +872,7 @@ func (c *Channel) applyToSubChannelsRecursive(f func(*Channel) error) (err error
 		if err != nil {
 			return
 		}
-		err = subCh.applyToSubChannelsRecursive(f)
+		err = subCh.applyToSubChannelsDFS(f, visited)
 		if err != nil {
 			return
 		}
@@ -371,7 +907,8 @@ func (c *Channel) setRegisteredRecursive(ctx context.Context) (err error) {
 	})
 }
 
-// gatherSubChannelStates gathers the state of all sub-channels recursively.
+// gatherSubChannelStates gathers the state of all sub-channels recursively, in
+// depth-first pre-order as described at applyToSubChannelsRecursive.
 // Assumes sub-channels are locked.
 func (c *Channel) gatherSubChannelStates() (states []channel.SignedState, err error) {
 	states = []channel.SignedState{}
@@ -386,7 +923,7 @@ func (c *Channel) gatherSubChannelStates() (states []channel.SignedState, err er
 	return
 }
 
-// gatherSubChannelStates gathers the state of all sub-channels recursively.
+// subChannelStateMap gathers the state of all sub-channels recursively.
 // Assumes sub-channels are locked.
 func (c *Channel) subChannelStateMap() (states channel.StateMap, err error) {
 	states = channel.MakeStateMap()
@@ -0,0 +1,89 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/pkg/sync"
+	pkgtest "perun.network/go-perun/pkg/test"
+	wallettest "perun.network/go-perun/wallet/test"
+)
+
+// newTreeChannel creates a random channel and adds it to the registry of c.
+func newTreeChannel(t *testing.T, rng *rand.Rand, c *Client) *Channel {
+	t.Helper()
+	src := mkRndChan(rng)
+	acc, err := wallettest.RandomWallet().Unlock(src.ParamsV.Parts[src.IdxV])
+	require.NoError(t, err)
+	m, err := channel.RestoreStateMachine(acc, src)
+	require.NoError(t, err)
+	ch := &Channel{client: c, machine: persistence.FromStateMachine(m, nil), OnCloser: new(sync.Closer)}
+	require.True(t, c.channels.Put(ch.ID(), ch))
+	return ch
+}
+
+// lock makes the current state of parent lock the given sub-channels.
+func lock(parent *Channel, subs ...*Channel) {
+	parent.state().Locked = nil
+	for _, sub := range subs {
+		parent.state().Locked = append(parent.state().Locked, *channel.NewSubAlloc(sub.ID(), nil, nil))
+	}
+}
+
+func TestChannel_gatherSubChannelStates(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	c := &Client{channels: makeChanRegistry()}
+	root, a, b, aa, ab := newTreeChannel(t, rng, c), newTreeChannel(t, rng, c),
+		newTreeChannel(t, rng, c), newTreeChannel(t, rng, c), newTreeChannel(t, rng, c)
+
+	lock(root, a, b)
+	lock(a, aa, ab)
+	lock(b)
+	lock(aa)
+	lock(ab)
+
+	t.Run("depth-first order", func(t *testing.T) {
+		states, err := root.gatherSubChannelStates()
+		require.NoError(t, err)
+		var ids []channel.ID
+		for _, s := range states {
+			ids = append(ids, s.State.ID)
+		}
+		assert.Equal(t, []channel.ID{a.ID(), aa.ID(), ab.ID(), b.ID()}, ids)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		lock(ab, root)
+		defer lock(ab)
+		_, err := root.gatherSubChannelStates()
+		assert.Error(t, err)
+		_, err = root.subChannelStateMap()
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		lock(b, aa)
+		defer lock(b)
+		_, err := root.gatherSubChannelStates()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/log"
+	plogrus "perun.network/go-perun/log/logrus"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestChannel_AdjudicateLogs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	chAlice, _ := openAcceptingChannel(ctx, t, rng, clients[0], clients[1])
+	require.NoError(t, transfer(ctx, chAlice, 3))
+
+	logger, hook := logrustest.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	chAlice.Embedding = log.MakeEmbedding(plogrus.FromLogrus(logger))
+
+	require.NoError(t, chAlice.Register(ctx))
+	require.NoError(t, chAlice.Settle(ctx, false))
+
+	type entry struct {
+		op, msg string
+	}
+	var entries []entry
+	for _, e := range hook.AllEntries() {
+		if op, ok := e.Data["adjudicate"]; ok {
+			assert.Equal(t, uint64(1), e.Data["version"])
+			entries = append(entries, entry{op.(string), e.Message})
+		}
+	}
+	assert.Equal(t, []entry{
+		{"register", "Registering."},
+		{"register", "Registered."},
+		{"settle", "Settling."},
+		{"settle", "Withdrawal successful."},
+	}, entries)
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+// registerCountingAdjudicator counts the calls to Register.
+type registerCountingAdjudicator struct {
+	channel.Adjudicator
+	registers int32
+}
+
+func (a *registerCountingAdjudicator) Register(ctx context.Context, req channel.AdjudicatorReq, subStates []channel.SignedState) error {
+	atomic.AddInt32(&a.registers, 1)
+	return a.Adjudicator.Register(ctx, req, subStates)
+}
+
+func TestChannel_Watch_WithoutAutoRefute(t *testing.T) {
+	t.Run("refutes by default", func(t *testing.T) {
+		assert.EqualValues(t, 1, watchOutdatedRegistration(t), "watcher should refute")
+	})
+	t.Run("WithoutAutoRefute", func(t *testing.T) {
+		assert.Zero(t, watchOutdatedRegistration(t, client.WithoutAutoRefute()), "watcher should not refute")
+	})
+}
+
+// watchOutdatedRegistration lets Bob register an outdated state of a channel
+// that Alice watches with the given options. It returns how often Alice's
+// watcher registered, after the handler was notified about the outdated
+// registration.
+func watchOutdatedRegistration(t *testing.T, opts ...client.WatchOption) int32 {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	setups := NewSetups(rng, []string{"Alice", "Bob"})
+	adj := &registerCountingAdjudicator{Adjudicator: setups[0].Adjudicator}
+	setups[0].Adjudicator = adj
+	clients := newClientsFromSetups(rng, setups, t)
+	alice, bob := clients[0], clients[1]
+	chAlice, chBob := openAcceptingChannel(ctx, t, rng, alice, bob)
+	defer alice.Close() // nolint:errcheck
+
+	req0 := client.NewTestChannel(chBob).AdjudicatorReq()
+	require.NoError(t, transfer(ctx, chAlice, 1))
+
+	h := make(chanAdjEventHandler, 10)
+	go chAlice.Watch(h, opts...) // nolint:errcheck
+	require.NoError(t, bob.Adjudicator.Register(ctx, req0, nil))
+
+	// The dispute strategy reacts to an event before the handler is notified.
+	// The handler may be notified about a refutation first.
+	for {
+		select {
+		case e := <-h:
+			require.IsType(t, new(channel.RegisteredEvent), e)
+			if e.Version() == req0.Tx.Version {
+				return atomic.LoadInt32(&adj.registers)
+			}
+		case <-ctx.Done():
+			t.Fatal("handler not notified")
+		}
+	}
+}
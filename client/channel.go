@@ -23,6 +23,7 @@ import (
 	"perun.network/go-perun/channel/persistence"
 	"perun.network/go-perun/log"
 	perunsync "perun.network/go-perun/pkg/sync"
+	"perun.network/go-perun/pkg/sync/atomic"
 	"perun.network/go-perun/wallet"
 	"perun.network/go-perun/wire"
 )
@@ -40,12 +41,17 @@ type Channel struct {
 	machine     persistence.StateMachine
 	machMtx     perunsync.Mutex
 	onUpdate    func(from, to *channel.State)
+	invariant   func(prev, next *channel.State) error
+	reserve     []channel.Bal // own minimum balance per asset, may be nil
 	adjudicator channel.Adjudicator
 	wallet      wallet.Wallet
 
 	parent                *Channel            // must be nil for ledger channel
 	subChannelFundings    *updateInterceptors // awaited subchannel funding updates
 	subChannelWithdrawals *updateInterceptors // awaited subchannel settlement updates
+	updateQueue           updateQueue         // queued incoming updates
+	updatePending         atomic.Bool         // whether an incoming update is deferred
+	watchOnly             bool                // whether the channel can only be used on-chain
 }
 
 // newChannel is internally used by the Client to create a new channel
@@ -165,12 +171,48 @@ func (c *Channel) Phase() channel.Phase {
 	return c.machine.Phase()
 }
 
+// Snapshot returns clones of the current and the staging state together with
+// the phase of the channel state machine, all read at the same time. The
+// staging state is nil if there is no staged update. Blocks while an update is
+// in progress.
+// Can not be called from an update handler.
+func (c *Channel) Snapshot() (current, staging *channel.State, phase channel.Phase) {
+	c.machMtx.Lock()
+	defer c.machMtx.Unlock()
+
+	return c.machine.State().Clone(), c.machine.StagingState().Clone(), c.machine.Phase()
+}
+
 // Peers returns the Perun network addresses of all peers, in the order
 // of the channel participants.
 func (c *Channel) Peers() []wire.Address {
 	return c.conn.Peers()
 }
 
+// Participant is a participant of a channel, identified by its Perun network
+// address and its index in the channel.
+type Participant struct {
+	Address wire.Address  // Perun network address of the participant.
+	Idx     channel.Index // Index of the participant in the channel.
+	IsMe    bool          // Whether the participant is the local client.
+}
+
+// Participants returns all participants of the channel, in the order of their
+// channel indices. The local client is marked with IsMe.
+func (c *Channel) Participants() []Participant {
+	peers := c.Peers()
+	idx := c.Idx()
+	parts := make([]Participant, len(peers))
+	for i, peer := range peers {
+		parts[i] = Participant{
+			Address: peer,
+			Idx:     channel.Index(i),
+			IsMe:    channel.Index(i) == idx,
+		}
+	}
+	return parts
+}
+
 // Parent returns the parent channel. Can be nil.
 func (c *Channel) Parent() *Channel {
 	return c.parent
@@ -214,7 +256,7 @@ func (c *Channel) initExchangeSigsAndEnable(ctx context.Context) error {
 		})
 	}()
 
-	pidx, cm, err := resRecv.Next(ctx)
+	pidx, cm, err := resRecv.NextFrom(ctx, []channel.Index{1 - c.machine.Idx()})
 	if err != nil {
 		return errors.WithMessage(err, "receiving initial state sig")
 	}
@@ -235,6 +277,13 @@ func (c *Channel) initExchangeSigsAndEnable(ctx context.Context) error {
 	return errors.WithMessage(<-send, "sending initial signature")
 }
 
+// LockedFunds returns the funds of each asset that are locked in sub-channels.
+// Sub-allocations do not record the participants' shares, so the amounts are
+// summed over all participants.
+func (c *Channel) LockedFunds() []channel.Bal {
+	return c.State().SumLocked()
+}
+
 func (c *Channel) hasLockedFunds() bool {
 	return len(c.machine.State().Locked) > 0
 }
@@ -32,8 +32,9 @@ import (
 type channelConn struct {
 	sync.OnCloser
 
-	pub   wire.Publisher // outgoing message publisher
-	r     *wire.Relay    // update response relay/incoming messages
+	pub   wire.Publisher          // outgoing message publisher
+	dn    wire.DisconnectNotifier // reports lost connections, may be nil
+	r     *wire.Relay             // update response relay/incoming messages
 	peers []wire.Address
 	idx   channel.Index // our index
 
@@ -59,7 +60,8 @@ func newChannelConn(id channel.ID, peers []wire.Address, idx channel.Index, sub
 	}()
 
 	isUpdateRes := func(e *wire.Envelope) bool {
-		ok := e.Msg.Type() == wire.ChannelUpdateAcc || e.Msg.Type() == wire.ChannelUpdateRej
+		ok := e.Msg.Type() == wire.ChannelUpdateAcc || e.Msg.Type() == wire.ChannelUpdateRej ||
+			e.Msg.Type() == wire.ChannelUpdateRejWithCode
 		return ok && e.Msg.(ChannelMsg).ID() == id
 	}
 
@@ -67,10 +69,12 @@ func newChannelConn(id channel.ID, peers []wire.Address, idx channel.Index, sub
 		return nil, errors.WithMessagef(err, "subscribing relay")
 	}
 
+	dn, _ := pub.(wire.DisconnectNotifier)
 	return &channelConn{
 		OnCloser: relay,
 		r:        relay,
 		pub:      pub,
+		dn:       dn,
 		peers:    peers,
 		idx:      idx,
 		log:      log.WithField("channel", id),
@@ -92,7 +96,8 @@ func (c *channelConn) Close() error {
 	return c.r.Close()
 }
 
-// Send broadcasts the message to all channel participants.
+// Send broadcasts the message to all channel participants. Returns an
+// ErrPeerDisconnected if the connection to a participant was lost.
 func (c *channelConn) Send(ctx context.Context, msg wire.Msg) error {
 	var eg errgroup.Group
 	for i, peer := range c.peers {
@@ -105,7 +110,9 @@ func (c *channelConn) Send(ctx context.Context, msg wire.Msg) error {
 			Recipient: peer,
 			Msg:       msg,
 		}
-		eg.Go(func() error { return c.pub.Publish(ctx, env) })
+		eg.Go(func() error {
+			return checkPeerDisconnected(c.pub.Publish(ctx, env), env.Recipient)
+		})
 	}
 	return errors.WithMessage(eg.Wait(), "publishing message")
 }
@@ -131,6 +138,7 @@ func (c *channelConn) NewUpdateResRecv(version uint64) (*channelMsgRecv, error)
 	return &channelMsgRecv{
 		Receiver: recv,
 		peers:    c.peers,
+		dn:       c.dn,
 		log:      c.log.WithField("version", version),
 	}, nil
 }
@@ -141,6 +149,7 @@ type (
 	channelMsgRecv struct {
 		*wire.Receiver
 		peers []wire.Address
+		dn    wire.DisconnectNotifier
 		log   log.Logger
 	}
 )
@@ -152,6 +161,24 @@ func (r *channelMsgRecv) Next(ctx context.Context) (channel.Index, ChannelMsg, e
 	if err != nil {
 		return 0, nil, err
 	}
+	return r.channelMsg(env)
+}
+
+// NextFrom returns the next message like Next. If the connection to one of
+// the participants from is lost before, it returns an ErrPeerDisconnected.
+func (r *channelMsgRecv) NextFrom(ctx context.Context, from []channel.Index) (channel.Index, ChannelMsg, error) {
+	peers := make([]wire.Address, len(from))
+	for i, idx := range from {
+		peers[i] = r.peers[idx]
+	}
+	env, err := nextFrom(ctx, r.Receiver, r.dn, peers...)
+	if err != nil {
+		return 0, nil, err
+	}
+	return r.channelMsg(env)
+}
+
+func (r *channelMsgRecv) channelMsg(env *wire.Envelope) (channel.Index, ChannelMsg, error) {
 	idx := wire.IndexOfAddr(r.peers, env.Sender)
 	if idx == -1 {
 		return 0, nil, errors.Errorf("channel connection received message from unexpected peer %v", env.Sender)
@@ -0,0 +1,162 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	ctest "perun.network/go-perun/client/test"
+	"perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wire"
+)
+
+// desyncedChannels opens a channel between Alice and Bob and lets Alice's
+// next update time out before Bob accepts it, so that Bob's state is one
+// version ahead of Alice's.
+func desyncedChannels(ctx context.Context, t *testing.T) (alice *Client, chAlice, chBob *client.Channel) {
+	t.Helper()
+	rng := test.Prng(t)
+	return desyncedChannelsFromSetups(ctx, t, rng, NewSetups(rng, []string{"Alice", "Bob"}))
+}
+
+// desyncedChannelsFromSetups is like desyncedChannels, but creates Alice and
+// Bob from the given setups.
+func desyncedChannelsFromSetups(ctx context.Context, t *testing.T, rng *rand.Rand, setups []ctest.RoleSetup) (alice *Client, chAlice, chBob *client.Channel) {
+	t.Helper()
+	clients := newClientsFromSetups(rng, setups, t)
+	alice, bob := clients[0], clients[1]
+
+	aliceGaveUp := make(chan struct{})
+	bobAccepted := make(chan struct{}, 1)
+	first := true
+	chAlice, chBob = openChannel(ctx, t, rng, alice, bob, client.UpdateHandlerFunc(
+		func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+			if first {
+				first = false
+				<-aliceGaveUp
+			}
+			assert.NoError(t, ur.Accept(ctx))
+			bobAccepted <- struct{}{}
+		}))
+
+	upCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err := transfer(upCtx, chAlice, 1)
+	require.True(t, errors.As(err, new(client.RequestTimedOutError)), "update should time out: %v", err)
+	close(aliceGaveUp)
+	<-bobAccepted
+
+	require.Equal(t, uint64(0), chAlice.State().Version)
+	require.Equal(t, uint64(1), chBob.State().Version)
+	return alice, chAlice, chBob
+}
+
+// syncGateBus holds back each sync request published over it until the gate
+// is passed by all participants.
+type syncGateBus struct {
+	wire.Bus
+	gate *sync.WaitGroup
+}
+
+func (b syncGateBus) Publish(ctx context.Context, e *wire.Envelope) error {
+	if e.Msg.Type() == wire.ChannelSync {
+		b.gate.Done()
+		b.gate.Wait()
+	}
+	return b.Bus.Publish(ctx, e)
+}
+
+func TestChannel_Sync(t *testing.T) {
+	t.Run("behind", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+		defer cancel()
+		_, chAlice, chBob := desyncedChannels(ctx, t)
+
+		require.NoError(t, chAlice.Sync(ctx))
+		assert.NoError(t, chAlice.State().Equal(chBob.State()))
+
+		// The channel can be updated again.
+		require.NoError(t, transfer(ctx, chAlice, 1))
+		assert.Equal(t, uint64(2), chBob.State().Version)
+	})
+
+	t.Run("ahead", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+		defer cancel()
+		alice, chAlice, chBob := desyncedChannels(ctx, t)
+		// Alice needs to handle Bob's sync request.
+		go alice.Handle(
+			client.ProposalHandlerFunc(func(_ client.ChannelProposal, pr *client.ProposalResponder) {
+				assert.NoError(t, pr.Reject(ctx, "unexpected proposal"))
+			}),
+			client.UpdateHandlerFunc(func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+				assert.NoError(t, ur.Reject(ctx, "unexpected update"))
+			}))
+
+		require.NoError(t, chBob.Sync(ctx))
+		// Alice adopts Bob's state after replying.
+		assert.Eventually(t, func() bool {
+			return chAlice.State().Equal(chBob.State()) == nil
+		}, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, transfer(ctx, chAlice, 1))
+		assert.Equal(t, uint64(2), chBob.State().Version)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+		defer cancel()
+		rng := test.Prng(t)
+		setups := NewSetups(rng, []string{"Alice", "Bob"})
+		// Both sides sync at the same time, so each has to reply to the other
+		// while waiting for the other's reply.
+		gate := new(sync.WaitGroup)
+		gate.Add(2)
+		for i := range setups {
+			setups[i].Bus = syncGateBus{Bus: setups[i].Bus, gate: gate}
+		}
+		alice, chAlice, chBob := desyncedChannelsFromSetups(ctx, t, rng, setups)
+		go alice.Handle(
+			client.ProposalHandlerFunc(func(_ client.ChannelProposal, pr *client.ProposalResponder) {
+				assert.NoError(t, pr.Reject(ctx, "unexpected proposal"))
+			}),
+			client.UpdateHandlerFunc(func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+				assert.NoError(t, ur.Reject(ctx, "unexpected update"))
+			}))
+
+		syncCtx, syncCancel := context.WithTimeout(ctx, time.Second)
+		defer syncCancel()
+		errs := make(chan error, 2)
+		go func() { errs <- chAlice.Sync(syncCtx) }()
+		go func() { errs <- chBob.Sync(syncCtx) }()
+		require.NoError(t, <-errs)
+		require.NoError(t, <-errs)
+
+		assert.Equal(t, uint64(1), chAlice.State().Version)
+		assert.NoError(t, chAlice.State().Equal(chBob.State()))
+		require.NoError(t, transfer(ctx, chAlice, 1))
+		assert.Equal(t, uint64(2), chBob.State().Version)
+	})
+}
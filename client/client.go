@@ -16,6 +16,8 @@ package client
 
 import (
 	"context"
+	stdsync "sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -23,6 +25,7 @@ import (
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/channel/persistence"
 	"perun.network/go-perun/log"
+	"perun.network/go-perun/pkg/clock"
 	"perun.network/go-perun/pkg/sync"
 	"perun.network/go-perun/wallet"
 	"perun.network/go-perun/wire"
@@ -34,17 +37,27 @@ import (
 //
 // Currently, only the two-party protocol is fully implemented.
 type Client struct {
-	address           wire.Address
-	conn              clientConn
-	channels          chanRegistry
-	funder            channel.Funder
-	adjudicator       channel.Adjudicator
-	wallet            wallet.Wallet
-	pr                persistence.PersistRestorer
-	log               log.Logger // structured logger for this client
-	version1Cache     version1Cache
-	fundingWatcher    *stateWatcher
-	settlementWatcher *stateWatcher
+	address             wire.Address
+	conn                clientConn
+	channels            chanRegistry
+	funder              channel.Funder
+	adjudicator         channel.Adjudicator
+	wallet              wallet.Wallet
+	pr                  persistence.PersistRestorer
+	log                 log.Logger // structured logger for this client
+	version1Cache       version1Cache
+	fundingWatcher      *stateWatcher
+	settlementWatcher   *stateWatcher
+	proposalLimiter     *proposalLimiter
+	updateQueueSize     int
+	updateQueuePolicy   UpdateQueuePolicy
+	eventSlots          chan struct{} // handler pool, see SetEventHandlerPool
+	invalidUpdatePolicy InvalidUpdatePolicy
+	virtualFeeStrategy  VirtualFeeStrategy
+	clock               clock.Clock
+	watchers            watcherGroup
+	identities          IdentityMapper
+	cfg                 Config
 
 	sync.Closer
 }
@@ -65,12 +78,27 @@ type Client struct {
 // restoring channels.
 //
 // If any argument is nil, New panics.
+//
+// The client uses the DefaultConfig; use NewWithConfig to configure it.
 func New(
 	address wire.Address,
 	bus wire.Bus,
 	funder channel.Funder,
 	adjudicator channel.Adjudicator,
 	wallet wallet.Wallet,
+) (c *Client, err error) {
+	return NewWithConfig(address, bus, funder, adjudicator, wallet, DefaultConfig())
+}
+
+// NewWithConfig is like New, but configures the client's timeouts and
+// randomness source with cfg. Zero fields of cfg are set to their defaults.
+func NewWithConfig(
+	address wire.Address,
+	bus wire.Bus,
+	funder channel.Funder,
+	adjudicator channel.Adjudicator,
+	wallet wallet.Wallet,
+	cfg Config,
 ) (c *Client, err error) {
 	if address == nil {
 		log.Panic("address must not be nil")
@@ -87,6 +115,16 @@ func New(
 		log.Panic("wallet must not be nil")
 	}
 
+	cfg = cfg.withDefaults()
+	cfg.Rand = &lockedReader{r: cfg.Rand}
+	if cfg.ConfirmationDepth > 0 {
+		for _, b := range []interface{}{funder, adjudicator} {
+			if s, ok := b.(ConfirmationDepthSetter); ok {
+				s.SetConfirmationDepth(cfg.ConfirmationDepth)
+			}
+		}
+	}
+
 	conn, err := makeClientConn(address, bus)
 	if err != nil {
 		return nil, errors.WithMessage(err, "setting up client connection")
@@ -101,24 +139,43 @@ func New(
 		wallet:      wallet,
 		pr:          persistence.NonPersistRestorer,
 		log:         log,
+		cfg:         cfg,
 	}
 
 	c.fundingWatcher = newStateWatcher(c.matchFundingProposal)
 	c.settlementWatcher = newStateWatcher(c.matchSettlementProposal)
+	c.proposalLimiter = newProposalLimiter()
+	c.clock = clock.System()
 	return
 }
 
 // Close closes this state channel client.
-// It also closes the peer registry.
+// It also closes the peer registry and all channels, which stops their
+// watchers and adjudicator subscriptions. It waits at most
+// Config.CloseTimeout for the watchers to return, see CloseCtx.
 func (c *Client) Close() error {
+	// Not timeoutCtx because CloseCtx closes the client's context first.
+	ctx, cancel := clock.WithTimeout(context.Background(), c.clock, c.cfg.CloseTimeout)
+	defer cancel()
+	return c.CloseCtx(ctx)
+}
+
+// CloseCtx is like Close, but waits for the channel watchers to return until
+// the context is done. No new watchers can be started once it is called.
+// If a watcher did not return in time, an error is returned.
+func (c *Client) CloseCtx(ctx context.Context) error {
 	if err := c.Closer.Close(); err != nil {
 		return err
 	}
+	c.watchers.close()
 
 	err := errors.WithMessage(c.channels.CloseAll(), "closing channels")
 	if cerr := c.conn.Close(); err == nil {
 		err = errors.WithMessage(cerr, "closing channel connection")
 	}
+	if werr := c.watchers.wait(ctx); err == nil {
+		err = werr
+	}
 	return err
 }
 
@@ -139,6 +196,35 @@ func (c *Client) EnablePersistence(pr persistence.PersistRestorer) {
 	c.pr = pr
 }
 
+// SetClock sets the Clock that the client uses to measure its protocol
+// timeouts. It defaults to the system clock and can be replaced by a mock
+// clock in tests. This method is expected to be called once during the setup
+// of the client, before any protocol is run, and is hence not thread-safe.
+func (c *Client) SetClock(clk clock.Clock) {
+	if clk == nil {
+		c.log.Panic("clock must not be nil")
+	}
+	c.clock = clk
+}
+
+// timeoutCtx returns a child context of the client's context that times out
+// after d, as measured by the client's clock.
+func (c *Client) timeoutCtx(d time.Duration) (context.Context, context.CancelFunc) {
+	return clock.WithTimeout(c.Ctx(), c.clock, d)
+}
+
+// SetProposalLimits limits the number of incoming channel proposals that are
+// handled concurrently, per peer and in total. Proposals that exceed a limit
+// are rejected with reason "too many pending proposals" without calling the
+// ProposalHandler. A limit of zero disables the respective limit, which is the
+// default. This function may be safely called at any time.
+func (c *Client) SetProposalLimits(perPeer, total int) {
+	if perPeer < 0 || total < 0 {
+		c.log.Panic("proposal limits must not be negative")
+	}
+	c.proposalLimiter.setLimits(perPeer, total)
+}
+
 // Channel queries a channel by its ID.
 func (c *Client) Channel(id channel.ID) (*Channel, error) {
 	if ch, ok := c.channels.Get(id); ok {
@@ -172,11 +258,13 @@ func (c *Client) Handle(ph ProposalHandler, uh UpdateHandler) {
 		case *VirtualChannelProposal:
 			go c.handleChannelProposal(ph, env.Sender, msg)
 		case *msgChannelUpdate:
-			go c.handleChannelUpdate(uh, env.Sender, msg)
+			c.dispatchChannelUpdate(uh, env.Sender, msg)
+		case *msgChannelUpdateBatch:
+			c.dispatchChannelUpdate(uh, env.Sender, msg)
 		case *virtualChannelFundingProposal:
-			go c.handleChannelUpdate(uh, env.Sender, msg)
+			c.dispatchChannelUpdate(uh, env.Sender, msg)
 		case *virtualChannelSettlementProposal:
-			go c.handleChannelUpdate(uh, env.Sender, msg)
+			c.dispatchChannelUpdate(uh, env.Sender, msg)
 		case *msgChannelSync:
 			go c.handleSyncMsg(env.Sender, msg)
 		default:
@@ -204,22 +292,46 @@ func (c *Client) logChan(id channel.ID) log.Logger {
 }
 
 // Restore restores all channels from persistence. Channels are restored in
-// parallel. Newly restored channels should be acquired through the
-// OnNewChannel callback.
-func (c *Client) Restore(ctx context.Context) error {
+// parallel. Newly restored channels are passed to the OnNewChannel callback
+// and returned. Channels that are already known to the client are skipped.
+//
+// If the RestoreWithWatcher option is given, the watcher is started on all
+// restored channels, see Channel.Watch.
+func (c *Client) Restore(ctx context.Context, opts ...RestoreOption) ([]*Channel, error) {
+	var o restoreOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	ps, err := c.pr.ActivePeers(ctx)
 	if err != nil {
-		return errors.WithMessage(err, "restoring active peers")
+		return nil, errors.WithMessage(err, "restoring active peers")
 	}
 
-	var eg errgroup.Group
+	var (
+		eg       errgroup.Group
+		mtx      stdsync.Mutex
+		restored []*Channel
+	)
 	for _, p := range ps {
 		if p.Equals(c.address) {
 			continue // skip own peer
 		}
 		p := p
-		eg.Go(func() error { return c.restorePeerChannels(ctx, p) })
+		eg.Go(func() error {
+			chs, err := c.restorePeerChannels(ctx, p)
+			mtx.Lock()
+			defer mtx.Unlock()
+			restored = append(restored, chs...)
+			return err
+		})
 	}
+	err = eg.Wait()
 
-	return eg.Wait()
+	if o.watchHandler != nil {
+		for _, ch := range restored {
+			go c.watchRestored(ch, o.watchHandler, o.watchOpts)
+		}
+	}
+	return restored, err
 }
@@ -15,13 +15,23 @@
 package client
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
 	channeltest "perun.network/go-perun/channel/test"
+	clocktest "perun.network/go-perun/pkg/clock/test"
+	ctxtest "perun.network/go-perun/pkg/context/test"
 	"perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wallet"
 	wallettest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
 )
 
 func TestClient_Channel(t *testing.T) {
@@ -49,3 +59,180 @@ func TestClient_Channel(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestWithoutAutoRefute(t *testing.T) {
+	var o watchOpts
+	assert.False(t, o.noAutoRefute)
+	WithoutAutoRefute()(&o)
+	assert.True(t, o.noAutoRefute)
+}
+
+func TestWatchOpts_disputeStrategy(t *testing.T) {
+	var o watchOpts
+	assert.Equal(t, RefuteOutdatedStrategy{}, o.disputeStrategy())
+	WithoutAutoRefute()(&o)
+	assert.Equal(t, observeStrategy{}, o.disputeStrategy())
+
+	custom := DisputeStrategyFunc(func(context.Context, *Channel, channel.AdjudicatorEvent) error { return nil })
+	WithDisputeStrategy(custom)(&o)
+	_, ok := o.disputeStrategy().(DisputeStrategyFunc)
+	assert.True(t, ok, "custom strategy takes precedence")
+}
+
+func TestClient_SetClock(t *testing.T) {
+	clk := clocktest.NewMockClock(time.Now())
+	c := &Client{}
+	c.SetClock(clk)
+
+	timeout := DefaultConfig().ResponseTimeout
+	ctx, cancel := c.timeoutCtx(timeout)
+	defer cancel()
+	clk.Advance(timeout / 2)
+	assert.NoError(t, ctx.Err())
+	clk.Advance(timeout / 2)
+	ctxtest.AssertTerminatesQuickly(t, func() { <-ctx.Done() })
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestConfig_withDefaults(t *testing.T) {
+	def := DefaultConfig()
+	assert.Equal(t, def, Config{}.withDefaults())
+
+	cfg := Config{ResponseTimeout: time.Minute, CloseTimeout: time.Second}.withDefaults()
+	assert.Equal(t, time.Minute, cfg.ResponseTimeout)
+	assert.Equal(t, time.Second, cfg.CloseTimeout)
+	assert.Equal(t, def.VirtualFundingTimeout, cfg.VirtualFundingTimeout)
+	assert.Equal(t, def.VirtualSettlementTimeout, cfg.VirtualSettlementTimeout)
+	assert.Equal(t, def.SyncReplyTimeout, cfg.SyncReplyTimeout)
+	assert.Zero(t, cfg.UpdateResponseTimeout, "response timeout disabled by default")
+}
+
+func TestClient_RandomNonce(t *testing.T) {
+	newClient := func() *Client {
+		return &Client{cfg: Config{Rand: test.Prng(t)}.withDefaults()}
+	}
+	c0, c1 := newClient(), newClient()
+
+	n0 := c0.RandomNonce().nonce()
+	assert.Equal(t, n0, c1.RandomNonce().nonce(), "same seed should yield same nonce")
+	assert.NotEqual(t, n0, c0.RandomNonce().nonce(), "consecutive nonces should differ")
+}
+
+func TestChannel_Participants(t *testing.T) {
+	rng := test.Prng(t)
+	const n, me = 3, 1
+	accs := make([]wallet.Account, n)
+	addrs := make([]wallet.Address, n)
+	peers := make([]wire.Address, n)
+	w := wallettest.NewWallet()
+	for i := range accs {
+		accs[i] = w.NewRandomAccount(rng)
+		addrs[i] = accs[i].Address()
+		peers[i] = wiretest.NewRandomAddress(rng)
+	}
+	params := channeltest.NewRandomParams(rng, channeltest.WithParts(addrs...), channeltest.WithoutApp())
+	m, err := channel.NewStateMachine(accs[me], *params)
+	require.NoError(t, err)
+	ch := &Channel{
+		conn:    &channelConn{peers: peers},
+		machine: persistence.FromStateMachine(m, persistence.NonPersistRestorer),
+	}
+
+	parts := ch.Participants()
+	require.Len(t, parts, n)
+	for i, p := range parts {
+		assert.Equal(t, peers[i], p.Address)
+		assert.Equal(t, channel.Index(i), p.Idx)
+		assert.Equal(t, i == me, p.IsMe)
+	}
+}
+
+func TestChannel_ProgressBy_Deadline(t *testing.T) {
+	clk := clocktest.NewMockClock(time.Now())
+	ch := &Channel{client: &Client{clock: clk}}
+	require.True(t, ch.machMtx.TryLock())
+	defer ch.machMtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// The deadline is measured by the client's clock.
+	go func() {
+		for clk.NumTimers() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		clk.Advance(10 * time.Millisecond)
+	}()
+	updated := false
+	err := ch.ProgressBy(ctx, func(*channel.State) { updated = true },
+		WithProgressDeadline(clk.Now().Add(10*time.Millisecond)))
+	assert.Error(t, err, "progression should fail after the deadline")
+	assert.NoError(t, ctx.Err(), "deadline should expire before the context")
+	assert.False(t, updated)
+}
+
+func TestSameEvent(t *testing.T) {
+	rng := test.Prng(t)
+	state := channeltest.NewRandomState(rng)
+	id := state.ID
+	timeout := &channel.TimeTimeout{Time: time.Unix(100, 0)}
+	registered := func(s *channel.State, timeout channel.Timeout) channel.AdjudicatorEvent {
+		return channel.NewRegisteredEvent(id, timeout, s.Version, s, nil)
+	}
+	e := registered(state, timeout)
+
+	assert.True(t, sameEvent(e, registered(state.Clone(), &channel.TimeTimeout{Time: timeout.Time})))
+	assert.False(t, sameEvent(e, channel.NewConcludedEvent(id, timeout, state.Version)), "type")
+	assert.True(t, sameEvent(e, registered(state, new(channel.ElapsedTimeout))), "timeout is not compared")
+	other := state.Clone()
+	other.IsFinal = !other.IsFinal
+	assert.False(t, sameEvent(e, registered(other, timeout)), "state")
+	assert.False(t, sameEvent(e, channel.NewRegisteredEvent(channeltest.NewRandomChannelID(rng), timeout, state.Version, state, nil)), "channel ID")
+}
+
+// positionAdjudicator reports the positions of events in block 42 by their
+// index in events.
+type positionAdjudicator struct {
+	channel.Adjudicator
+	events []channel.AdjudicatorEvent
+}
+
+func (a *positionAdjudicator) SubscribeFrom(context.Context, *channel.Params, uint64) (channel.AdjudicatorSubscription, error) {
+	panic("not implemented")
+}
+
+func (a *positionAdjudicator) EventPosition(e channel.AdjudicatorEvent) (uint64, uint, bool) {
+	for i, ae := range a.events {
+		if ae == e {
+			return 42, uint(i), true
+		}
+	}
+	return 0, 0, false
+}
+
+func TestChannel_isCheckpointed(t *testing.T) {
+	rng := test.Prng(t)
+	state := channeltest.NewRandomState(rng)
+	timeout := &channel.TimeTimeout{Time: time.Unix(100, 0)}
+	// Two equal events in the same block, e.g., of two registrations of the
+	// same state, followed by a conclusion.
+	events := []channel.AdjudicatorEvent{
+		channel.NewRegisteredEvent(state.ID, timeout, state.Version, state, nil),
+		channel.NewRegisteredEvent(state.ID, timeout, state.Version, state, nil),
+		channel.NewConcludedEvent(state.ID, timeout, state.Version),
+	}
+	c := &Channel{adjudicator: &positionAdjudicator{events: events}}
+
+	assert.False(t, c.isCheckpointed(nil, events[0]), "no checkpoint")
+	cp := &WatchCheckpoint{Event: events[1], Block: 42, Index: 1}
+	assert.True(t, c.isCheckpointed(cp, events[0]), "earlier event in block")
+	assert.True(t, c.isCheckpointed(cp, events[1]))
+	assert.False(t, c.isCheckpointed(cp, events[2]), "later event in block")
+	assert.True(t, c.isCheckpointed(&WatchCheckpoint{Block: 43}, events[2]), "earlier block")
+	assert.False(t, c.isCheckpointed(&WatchCheckpoint{Event: events[2], Block: 41, Index: 2}, events[2]), "later block")
+
+	// Without positions, only the checkpointed event itself is skipped.
+	c = &Channel{}
+	assert.True(t, c.isCheckpointed(cp, events[0]), "same event")
+	assert.False(t, c.isCheckpointed(cp, events[2]), "other event")
+	assert.False(t, c.isCheckpointed(&WatchCheckpoint{Block: 42}, events[0]), "no checkpointed event")
+}
@@ -15,14 +15,21 @@
 package client_test
 
 import (
+	"context"
+	"math/big"
 	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	chtest "perun.network/go-perun/channel/test"
 	"perun.network/go-perun/client"
 	ctest "perun.network/go-perun/client/test"
 	wtest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/wire"
 	wiretest "perun.network/go-perun/wire/test"
 )
 
@@ -58,7 +65,12 @@ type Client struct {
 }
 
 func NewClients(rng *rand.Rand, names []string, t *testing.T) []*Client {
-	setups := NewSetups(rng, names)
+	return newClientsFromSetups(rng, NewSetups(rng, names), t)
+}
+
+// newClientsFromSetups creates a client for each setup, e.g., after replacing
+// some of their funders.
+func newClientsFromSetups(rng *rand.Rand, setups []ctest.RoleSetup, t *testing.T) []*Client {
 	clients := make([]*Client, len(setups))
 	for i, setup := range setups {
 		setup.Identity = setup.Wallet.NewRandomAccount(rng)
@@ -71,3 +83,58 @@ func NewClients(rng *rand.Rand, names []string, t *testing.T) []*Client {
 	}
 	return clients
 }
+
+// openAcceptingChannel opens a ledger channel between alice and bob with
+// balances of 10 each. bob accepts all proposals and updates.
+func openAcceptingChannel(ctx context.Context, t *testing.T, rng *rand.Rand, alice, bob *Client) (chAlice, chBob *client.Channel) {
+	t.Helper()
+	var updateHandlerBob client.UpdateHandlerFunc = func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+		assert.NoError(t, ur.Accept(ctx))
+	}
+	return openChannel(ctx, t, rng, alice, bob, updateHandlerBob)
+}
+
+// openChannel is like openAcceptingChannel, but Bob handles channel updates
+// with the given update handler.
+func openChannel(ctx context.Context, t *testing.T, rng *rand.Rand, alice, bob *Client, updateHandlerBob client.UpdateHandler) (chAlice, chBob *client.Channel) {
+	t.Helper()
+	channelsBob := make(chan *client.Channel, 1)
+	var proposalHandlerBob client.ProposalHandlerFunc = func(cp client.ChannelProposal, pr *client.ProposalResponder) {
+		ch, err := pr.Accept(ctx, cp.(*client.LedgerChannelProposal).Accept(bob.Identity.Address(), client.WithRandomNonce()))
+		assert.NoError(t, err)
+		channelsBob <- ch
+	}
+	go bob.Handle(proposalHandlerBob, updateHandlerBob)
+
+	res, err := alice.ProposeChannel(ctx, newLedgerChannelProposal(t, rng, alice, bob))
+	require.NoError(t, err)
+	return res.Channel, <-channelsBob
+}
+
+// newLedgerChannelProposal returns alice's proposal of a ledger channel with
+// bob with balances of 10 each.
+func newLedgerChannelProposal(t *testing.T, rng *rand.Rand, alice, bob *Client) *client.LedgerChannelProposal {
+	t.Helper()
+	prop, err := client.NewLedgerChannelProposal(
+		challengeDuration,
+		alice.Identity.Address(),
+		&channel.Allocation{
+			Assets:   []channel.Asset{chtest.NewRandomAsset(rng)},
+			Balances: [][]channel.Bal{{big.NewInt(10), big.NewInt(10)}},
+		},
+		[]wire.Address{alice.Identity.Address(), bob.Identity.Address()},
+	)
+	require.NoError(t, err)
+	return prop
+}
+
+// transfer lets the channel's user send amount of the first asset to the
+// other participant of the two-party channel.
+func transfer(ctx context.Context, ch *client.Channel, amount int64) error {
+	return ch.UpdateBy(ctx, func(s *channel.State) error {
+		me := ch.Idx()
+		s.Balances[0][me].Sub(s.Balances[0][me], big.NewInt(amount))
+		s.Balances[0][1-me].Add(s.Balances[0][1-me], big.NewInt(amount))
+		return nil
+	})
+}
@@ -74,3 +74,25 @@ func TestClient_New(t *testing.T) {
 	assert.NoError(t, err)
 	require.NotNil(t, c)
 }
+
+// depthBackend records the confirmation depth that is set on it.
+type depthBackend struct {
+	*ctest.MockBackend
+	depth uint64
+}
+
+func (b *depthBackend) SetConfirmationDepth(depth uint64) { b.depth = depth }
+
+func TestClient_NewWithConfig_ConfirmationDepth(t *testing.T) {
+	rng := test.Prng(t)
+	f, a := &depthBackend{MockBackend: &ctest.MockBackend{}}, &depthBackend{MockBackend: &ctest.MockBackend{}}
+	_, err := client.NewWithConfig(wtest.NewRandomAddress(rng), &DummyBus{t}, f, a, wtest.RandomWallet(), client.DefaultConfig())
+	require.NoError(t, err)
+	assert.Zero(t, f.depth, "zero depth should not be passed")
+
+	cfg := client.Config{ConfirmationDepth: 3}
+	_, err = client.NewWithConfig(wtest.NewRandomAddress(rng), &DummyBus{t}, f, a, wtest.RandomWallet(), cfg)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, f.depth, "funder depth")
+	assert.EqualValues(t, 3, a.depth, "adjudicator depth")
+}
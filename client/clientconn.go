@@ -64,25 +64,81 @@ func isReqMsg(m *wire.Envelope) bool {
 	return m.Msg.Type() == wire.LedgerChannelProposal ||
 		m.Msg.Type() == wire.SubChannelProposal ||
 		m.Msg.Type() == wire.VirtualChannelProposal ||
+		m.Msg.Type() == wire.VirtualChannelProposalWithFee ||
 		m.Msg.Type() == wire.VirtualChannelFundingProposal ||
+		m.Msg.Type() == wire.VirtualChannelFundingProposalWithFee ||
 		m.Msg.Type() == wire.VirtualChannelSettlementProposal ||
 		m.Msg.Type() == wire.ChannelUpdate ||
-		m.Msg.Type() == wire.ChannelSync
+		m.Msg.Type() == wire.ChannelUpdateWithPayload ||
+		m.Msg.Type() == wire.ChannelUpdateBatch ||
+		isSyncReq(m)
+}
+
+// isSyncReq returns whether the message is a channel sync request, i.e., a
+// channel sync message that is not a reply.
+func isSyncReq(m *wire.Envelope) bool {
+	return m.Msg.Type() == wire.ChannelSync
 }
 
 func (c clientConn) nextReq(ctx context.Context) (*wire.Envelope, error) {
 	return c.reqRecv.Next(ctx)
 }
 
+// Disconnected forwards to the bus if it is a wire.DisconnectNotifier.
+// Otherwise, it returns nil.
+func (c *clientConn) Disconnected(peer wire.Address) <-chan struct{} {
+	if dn, ok := c.bus.(wire.DisconnectNotifier); ok {
+		return dn.Disconnected(peer)
+	}
+	return nil
+}
+
+// nextFrom returns the next envelope of recv. If dn reports that the
+// connection to one of peers is lost before an envelope is received, it
+// returns an ErrPeerDisconnected. dn may be nil.
+func nextFrom(ctx context.Context, recv *wire.Receiver, dn wire.DisconnectNotifier, peers ...wire.Address) (*wire.Envelope, error) {
+	if dn == nil {
+		return recv.Next(ctx)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lost := make(chan wire.Address, len(peers))
+	for _, peer := range peers {
+		disconnected := dn.Disconnected(peer)
+		if disconnected == nil {
+			continue
+		}
+		go func(peer wire.Address) {
+			select {
+			case <-disconnected:
+				lost <- peer
+				cancel()
+			case <-ctx.Done():
+			}
+		}(peer)
+	}
+
+	env, err := recv.Next(ctx)
+	if err != nil {
+		select {
+		case peer := <-lost:
+			return nil, errors.WithStack(ErrPeerDisconnected{Peer: peer})
+		default:
+		}
+	}
+	return env, err
+}
+
 // pubMsg publishes the given message on the wire bus, setting the own client as
-// the sender.
+// the sender. Returns an ErrPeerDisconnected if the connection to the
+// recipient was lost.
 func (c *clientConn) pubMsg(ctx context.Context, msg wire.Msg, rec wire.Address) error {
 	c.Log().WithField("peer", rec).Debugf("Publishing message: %v: %+v", msg.Type(), msg)
-	return c.bus.Publish(ctx, &wire.Envelope{
+	return checkPeerDisconnected(c.bus.Publish(ctx, &wire.Envelope{
 		Sender:    c.sender,
 		Recipient: rec,
 		Msg:       msg,
-	})
+	}), rec)
 }
 
 // Publish publishes the message on the bus. Makes clientConn implement the
@@ -0,0 +1,58 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+type noopAdjEventHandler struct{}
+
+func (noopAdjEventHandler) HandleAdjudicatorEvent(channel.AdjudicatorEvent) {}
+
+func TestClient_Close_StopsWatchers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	alice, bob := clients[0], clients[1]
+	chAlice, chBob := openAcceptingChannel(ctx, t, rng, alice, bob)
+	chs := []*client.Channel{chAlice, chBob}
+
+	watching := make(chan struct{}, len(chs))
+	for _, ch := range chs {
+		ch := ch
+		go func() {
+			watching <- struct{}{}
+			ch.Watch(noopAdjEventHandler{}) // nolint:errcheck
+		}()
+	}
+	<-watching
+	<-watching
+
+	// Closing returns only after all watchers returned.
+	require.NoError(t, alice.Close())
+	require.NoError(t, bob.Close())
+	assert.Error(t, chAlice.Watch(noopAdjEventHandler{}), "watching after close should fail")
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+// pendingAdjudicator reports the challenge timeouts of all events as pending
+// while pending is set.
+type pendingAdjudicator struct {
+	channel.Adjudicator
+	pending bool
+}
+
+type pendingSub struct {
+	channel.AdjudicatorSubscription
+	adj *pendingAdjudicator
+}
+
+type pendingTimeout struct{}
+
+func (pendingTimeout) IsElapsed(context.Context) bool { return false }
+
+func (pendingTimeout) Wait(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (a *pendingAdjudicator) Subscribe(ctx context.Context, params *channel.Params) (channel.AdjudicatorSubscription, error) {
+	sub, err := a.Adjudicator.Subscribe(ctx, params)
+	return &pendingSub{AdjudicatorSubscription: sub, adj: a}, err
+}
+
+func (a *pendingAdjudicator) Conclude(ctx context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error {
+	return a.Adjudicator.(client.Concluder).Conclude(ctx, req, subStates)
+}
+
+func (s *pendingSub) Next() channel.AdjudicatorEvent {
+	e := s.AdjudicatorSubscription.Next()
+	if e == nil || !s.adj.pending {
+		return e
+	}
+	return channel.NewRegisteredEvent(e.ID(), pendingTimeout{}, e.Version(), nil, nil)
+}
+
+func TestChannel_ConcludeForced(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	setups := NewSetups(rng, []string{"Alice", "Bob"})
+	adj := &pendingAdjudicator{Adjudicator: setups[0].Adjudicator, pending: true}
+	setups[0].Adjudicator = adj
+	clients := newClientsFromSetups(rng, setups, t)
+	chAlice, _ := openAcceptingChannel(ctx, t, rng, clients[0], clients[1])
+	req0 := client.NewTestChannel(chAlice).AdjudicatorReq()
+	require.NoError(t, transfer(ctx, chAlice, 3))
+
+	assert.Error(t, chAlice.ConcludeForced(ctx), "channel is not registered")
+
+	require.NoError(t, chAlice.Register(ctx))
+	err := chAlice.ConcludeForced(ctx)
+	var notElapsed client.ChallengeNotElapsedError
+	require.True(t, errors.As(err, &notElapsed), "expected ChallengeNotElapsedError, got %v", err)
+	assert.Equal(t, uint64(1), notElapsed.Version)
+	assert.Equal(t, channel.Registered, chAlice.Phase())
+
+	// Bob registers an outdated state, which Alice does not refute because
+	// she does not watch the channel.
+	require.NoError(t, clients[1].Adjudicator.Register(ctx, req0, nil))
+
+	backend := setups[0].Backend
+	part, asset := chAlice.Params().Parts[chAlice.Idx()], req0.Tx.Allocation.Assets[0]
+	bal := backend.GetBalance(part, asset)
+
+	adj.pending = false
+	require.NoError(t, chAlice.ConcludeForced(ctx))
+	assert.Equal(t, channel.Registered, chAlice.Phase())
+	assert.Equal(t, bal, backend.GetBalance(part, asset), "ConcludeForced should not withdraw")
+	sub, err := adj.Subscribe(ctx, chAlice.Params())
+	require.NoError(t, err)
+	e := sub.Next()
+	require.NoError(t, sub.Close())
+	require.IsType(t, new(channel.ConcludedEvent), e)
+	assert.Equal(t, req0.Tx.Version, e.Version(), "registered state should be concluded")
+
+	require.NoError(t, chAlice.Settle(ctx, false))
+	assert.Equal(t, channel.Withdrawn, chAlice.Phase())
+	outcome := req0.Tx.Allocation.Balances[0][chAlice.Idx()]
+	assert.Equal(t, new(big.Int).Add(bal, outcome), backend.GetBalance(part, asset))
+}
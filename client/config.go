@@ -0,0 +1,124 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"time"
+)
+
+// Config holds the timeouts and the randomness source of the client's
+// protocols. It is passed to NewWithConfig. Zero fields are set to their
+// defaults from DefaultConfig.
+type Config struct {
+	// ResponseTimeout is how long the client waits until its response to a
+	// proposal must be transmitted.
+	ResponseTimeout time.Duration
+	// VirtualFundingTimeout is how long the client waits for the matching
+	// funding proposal of a virtual channel.
+	VirtualFundingTimeout time.Duration
+	// VirtualSettlementTimeout is how long the client waits for the matching
+	// settlement proposal of a virtual channel.
+	VirtualSettlementTimeout time.Duration
+	// SyncReplyTimeout is how long the client waits until its reply to a
+	// channel synchronization request must be transmitted.
+	SyncReplyTimeout time.Duration
+	// CloseTimeout is how long Close waits for the channel watchers to return.
+	CloseTimeout time.Duration
+	// RefundTimeout is how long the client tries to withdraw its deposits
+	// after the funding of a ledger channel timed out. It must cover the
+	// channel's challenge duration.
+	RefundTimeout time.Duration
+	// UpdateDeferralTimeout is how long an incoming channel update may be
+	// deferred with UpdateResponder.Defer until it is rejected automatically.
+	UpdateDeferralTimeout time.Duration
+	// UpdateResponseTimeout is how long the client waits for the responses of
+	// the participants to a channel update. The update is sent to all
+	// participants at once, so each participant has this long to respond,
+	// counted from the same start. The update fails if a participant did not
+	// respond in time. It should exceed the UpdateDeferralTimeout of the
+	// peers. Zero, the default, disables it, so that only the context of the
+	// update limits the wait.
+	UpdateResponseTimeout time.Duration
+	// ConfirmationDepth is the number of blocks that must be mined on top of
+	// the block of an on-chain transaction until it is considered confirmed.
+	// It is passed to the funder and the adjudicator if they implement
+	// ConfirmationDepthSetter. Zero, the default, leaves their setting
+	// unchanged, which considers transactions confirmed once they are mined.
+	ConfirmationDepth uint64
+	// Rand is the entropy source of all nonce shares generated by the client.
+	// It is read from concurrently under a lock, so it need not be
+	// thread-safe itself.
+	Rand io.Reader
+}
+
+// ConfirmationDepthSetter can optionally be implemented by a channel.Funder
+// or channel.Adjudicator to wait for a number of blocks on top of their
+// transactions. It is used to apply Config.ConfirmationDepth.
+type ConfirmationDepthSetter interface {
+	// SetConfirmationDepth sets the number of blocks that must be mined on
+	// top of the block of a transaction until it is considered confirmed.
+	SetConfirmationDepth(depth uint64)
+}
+
+// DefaultConfig returns the default client configuration.
+func DefaultConfig() Config {
+	return Config{
+		ResponseTimeout:          10 * time.Second,
+		VirtualFundingTimeout:    10 * time.Second,
+		VirtualSettlementTimeout: 10 * time.Second,
+		SyncReplyTimeout:         10 * time.Second,
+		CloseTimeout:             10 * time.Second,
+		UpdateDeferralTimeout:    10 * time.Second,
+		RefundTimeout:            10 * time.Minute,
+		Rand:                     rand.Reader,
+	}
+}
+
+// withDefaults returns the configuration with all zero fields set to their
+// defaults.
+func (cfg Config) withDefaults() Config {
+	def := DefaultConfig()
+	setDefault := func(d *time.Duration, def time.Duration) {
+		if *d == 0 {
+			*d = def
+		}
+	}
+	setDefault(&cfg.ResponseTimeout, def.ResponseTimeout)
+	setDefault(&cfg.VirtualFundingTimeout, def.VirtualFundingTimeout)
+	setDefault(&cfg.VirtualSettlementTimeout, def.VirtualSettlementTimeout)
+	setDefault(&cfg.SyncReplyTimeout, def.SyncReplyTimeout)
+	setDefault(&cfg.CloseTimeout, def.CloseTimeout)
+	setDefault(&cfg.UpdateDeferralTimeout, def.UpdateDeferralTimeout)
+	setDefault(&cfg.RefundTimeout, def.RefundTimeout)
+	if cfg.Rand == nil {
+		cfg.Rand = def.Rand
+	}
+	return cfg
+}
+
+// lockedReader serializes reads from an io.Reader.
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Read(p)
+}
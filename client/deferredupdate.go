@@ -0,0 +1,120 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/pkg/sync/atomic"
+)
+
+// ErrUpdatePending is returned when an update is attempted on a channel while
+// an incoming update is deferred, see UpdateResponder.Defer.
+var ErrUpdatePending = errors.New("deferred channel update pending")
+
+// DeferredUpdate is an incoming channel update whose acceptance or rejection
+// was deferred with UpdateResponder.Defer. Exactly one of ResolveAccept,
+// ResolveReject and ResolveRejectWithCode should be called on it. Both may be called from any goroutine.
+//
+// While the update is deferred, the channel stays locked so that the update
+// can still be completed. Competing updates are rejected with
+// ErrUpdatePending, incoming ones with UpdateRejectBusy. Other methods that
+// lock the channel, like Channel.State, block until the update is resolved.
+// If the update is not resolved within the client's UpdateDeferralTimeout, it
+// is rejected automatically.
+type DeferredUpdate struct {
+	responder *UpdateResponder
+	resolved  atomic.Bool
+	cancel    context.CancelFunc // stops the deferral timeout
+}
+
+// Defer lets the user signal that they want to decide on the channel update
+// later, without blocking the UpdateHandler. The update stays pending until it
+// is resolved with the returned DeferredUpdate or the deferral times out.
+// Defer must be called before HandleUpdate returns, because the client unlocks
+// the channel when HandleUpdate returned without a deferral. Panics if the update was already accepted, rejected
+// or deferred.
+func (r *UpdateResponder) Defer() *DeferredUpdate {
+	if !r.called.TrySet() {
+		log.Panic("multiple calls on channel update responder")
+	}
+
+	c, client := r.channel, r.channel.client
+	ctx, cancel := client.timeoutCtx(client.cfg.UpdateDeferralTimeout)
+	d := &DeferredUpdate{responder: r, cancel: cancel}
+	r.deferred.Set()
+	c.updatePending.Set()
+	c.logUpdate(r.req.Base().State.Version).Debug("Update deferred.")
+
+	go func() {
+		<-ctx.Done()
+		// Reject the update if it was not resolved before the timeout.
+		// nolint:errcheck
+		d.resolve(func() error {
+			rctx, rcancel := client.timeoutCtx(client.cfg.ResponseTimeout)
+			defer rcancel()
+			return c.handleUpdateRej(rctx, r.pidx, r.req, UpdateRejectBusy, "update deferral timed out")
+		})
+	}()
+	return d
+}
+
+// ResolveAccept accepts the deferred channel update. Returns an error if the
+// update was already resolved or rejected because the deferral timed out.
+func (d *DeferredUpdate) ResolveAccept(ctx context.Context) error {
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	r := d.responder
+	return d.resolve(func() error {
+		return r.channel.handleUpdateAcc(ctx, r.pidx, r.req)
+	})
+}
+
+// ResolveReject rejects the deferred channel update, giving a reason for the
+// rejection. Returns an error if the update was already resolved or rejected
+// because the deferral timed out.
+func (d *DeferredUpdate) ResolveReject(ctx context.Context, reason string) error {
+	return d.ResolveRejectWithCode(ctx, UpdateRejectUnspecified, reason)
+}
+
+// ResolveRejectWithCode is like ResolveReject but additionally sends a reason
+// code to the proposer of the update, like UpdateResponder.RejectWithCode.
+func (d *DeferredUpdate) ResolveRejectWithCode(ctx context.Context, code UpdateRejectCode, reason string) error {
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	r := d.responder
+	return d.resolve(func() error {
+		return r.channel.handleUpdateRej(ctx, r.pidx, r.req, code, reason)
+	})
+}
+
+// resolve calls respond if the update was not resolved yet. Afterwards, it
+// unlocks the channel, which was kept locked since the update was deferred.
+func (d *DeferredUpdate) resolve(respond func() error) error {
+	if !d.resolved.TrySet() {
+		return errors.New("deferred update already resolved")
+	}
+	d.cancel()
+
+	c := d.responder.channel
+	defer c.machMtx.Unlock()
+	defer c.updatePending.Unset()
+	return respond()
+}
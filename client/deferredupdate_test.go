@@ -0,0 +1,108 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	clocktest "perun.network/go-perun/pkg/clock/test"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestUpdateResponder_Defer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	clk := clocktest.NewMockClock(time.Now())
+	clients[1].SetClock(clk)
+	deferred := make(chan *client.DeferredUpdate, 1)
+	var updateHandlerBob client.UpdateHandlerFunc = func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+		deferred <- ur.Defer()
+	}
+	chAlice, chBob := openChannel(ctx, t, rng, clients[0], clients[1], updateHandlerBob)
+
+	// deferUpdate starts a transfer from Alice to Bob, which Bob defers. It
+	// returns the deferred update and the result of the transfer.
+	deferUpdate := func() (*client.DeferredUpdate, <-chan error) {
+		res := make(chan error, 1)
+		go func() { res <- transfer(ctx, chAlice, 1) }()
+		select {
+		case d := <-deferred:
+			return d, res
+		case <-ctx.Done():
+			t.Fatal("update was not deferred")
+			return nil, nil
+		}
+	}
+
+	t.Run("ResolveAccept", func(t *testing.T) {
+		next := chBob.State().Clone()
+		version := next.Version
+		d, res := deferUpdate()
+		assert.True(t, errors.Is(chBob.Update(ctx, next), client.ErrUpdatePending),
+			"competing update should fail while the deferred update is pending")
+
+		require.NoError(t, d.ResolveAccept(ctx))
+		require.NoError(t, <-res)
+		assert.Equal(t, version+1, chBob.State().Version)
+		assert.Equal(t, version+1, chAlice.State().Version)
+		assert.Error(t, d.ResolveReject(ctx, "too late"), "already resolved")
+	})
+
+	t.Run("ResolveReject", func(t *testing.T) {
+		version := chBob.State().Version
+		d, res := deferUpdate()
+		const reason = "oracle disagrees"
+		require.NoError(t, d.ResolveReject(ctx, reason))
+
+		var rejErr client.PeerRejectedError
+		require.True(t, errors.As(<-res, &rejErr))
+		assert.Equal(t, reason, rejErr.Reason)
+		assert.Equal(t, version, chBob.State().Version)
+		assert.Equal(t, version, chAlice.State().Version)
+	})
+
+	t.Run("ResolveRejectWithCode", func(t *testing.T) {
+		d, res := deferUpdate()
+		require.NoError(t, d.ResolveRejectWithCode(ctx, client.UpdateRejectAppLogic, "invalid move"))
+
+		var rejErr client.PeerRejectedError
+		require.True(t, errors.As(<-res, &rejErr))
+		assert.Equal(t, client.UpdateRejectAppLogic, rejErr.UpdateCode)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		version := chBob.State().Version
+		d, res := deferUpdate()
+		clk.Advance(client.DefaultConfig().UpdateDeferralTimeout)
+
+		var rejErr client.PeerRejectedError
+		require.True(t, errors.As(<-res, &rejErr))
+		assert.Equal(t, client.UpdateRejectBusy, rejErr.UpdateCode)
+		assert.Error(t, d.ResolveAccept(ctx), "already rejected by timeout")
+		assert.Equal(t, version, chBob.State().Version)
+		assert.Equal(t, version, chAlice.State().Version)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+)
+
+type (
+	// DisputeStrategy decides how Channel.Watch reacts to the adjudicator
+	// events of a channel. It is consulted on every event, after a newer
+	// registered state was adopted and before the AdjudicatorEventHandler is
+	// notified. An error returned by the strategy stops the watcher.
+	//
+	// The default strategy is RefuteOutdatedStrategy.
+	DisputeStrategy interface {
+		OnAdjudicatorEvent(ctx context.Context, ch *Channel, e channel.AdjudicatorEvent) error
+	}
+
+	// DisputeStrategyFunc is a function that implements DisputeStrategy.
+	DisputeStrategyFunc func(ctx context.Context, ch *Channel, e channel.AdjudicatorEvent) error
+
+	// RefuteOutdatedStrategy refutes the registration of an outdated state by
+	// registering the channel tree with the most recent local states. It
+	// ignores all other events.
+	RefuteOutdatedStrategy struct{}
+
+	// observeStrategy only logs the registration of outdated states.
+	observeStrategy struct{}
+)
+
+// WithDisputeStrategy makes Channel.Watch react to adjudicator events with the
+// given strategy instead of the default RefuteOutdatedStrategy. It takes
+// precedence over WithoutAutoRefute.
+func WithDisputeStrategy(s DisputeStrategy) WatchOption {
+	return func(o *watchOpts) { o.strategy = s }
+}
+
+// OnAdjudicatorEvent calls f(ctx, ch, e).
+func (f DisputeStrategyFunc) OnAdjudicatorEvent(ctx context.Context, ch *Channel, e channel.AdjudicatorEvent) error {
+	return f(ctx, ch, e)
+}
+
+// OnAdjudicatorEvent registers the channel tree if e registers a state that is
+// older than the current state of ch.
+func (RefuteOutdatedStrategy) OnAdjudicatorEvent(ctx context.Context, ch *Channel, e channel.AdjudicatorEvent) error {
+	if !isOutdatedRegistration(ch, e) {
+		return nil
+	}
+	return errors.WithMessage(ch.Register(ctx), "registering")
+}
+
+func (observeStrategy) OnAdjudicatorEvent(_ context.Context, ch *Channel, e channel.AdjudicatorEvent) error {
+	if isOutdatedRegistration(ch, e) {
+		ch.Log().Warnf("Outdated state with version %d registered, not refuting.", e.Version())
+	}
+	return nil
+}
+
+// isOutdatedRegistration returns whether e registers a state that is older
+// than the current state of ch.
+func isOutdatedRegistration(ch *Channel, e channel.AdjudicatorEvent) bool {
+	_, ok := e.(*channel.RegisteredEvent)
+	return ok && e.Version() < ch.State().Version
+}
+
+// disputeStrategy returns the strategy configured by the watch options.
+func (o *watchOpts) disputeStrategy() DisputeStrategy {
+	switch {
+	case o.strategy != nil:
+		return o.strategy
+	case o.noAutoRefute:
+		return observeStrategy{}
+	default:
+		return RefuteOutdatedStrategy{}
+	}
+}
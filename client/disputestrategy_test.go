@@ -0,0 +1,66 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestChannel_Watch_DisputeStrategy(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	alice, bob := clients[0], clients[1]
+	chAlice, _ := openAcceptingChannel(ctx, t, rng, alice, bob)
+	defer alice.Close() // nolint:errcheck
+
+	errStop := errors.New("stop watching")
+	consulted := make(chan channel.AdjudicatorEvent, 1)
+	strategy := client.DisputeStrategyFunc(func(_ context.Context, ch *client.Channel, e channel.AdjudicatorEvent) error {
+		assert.Same(t, chAlice, ch)
+		consulted <- e
+		return errStop
+	})
+
+	h := make(chanAdjEventHandler, 1)
+	watchErr := make(chan error, 1)
+	go func() { watchErr <- chAlice.Watch(h, client.WithDisputeStrategy(strategy)) }()
+	require.NoError(t, chAlice.Register(ctx))
+
+	select {
+	case e := <-consulted:
+		assert.IsType(t, new(channel.RegisteredEvent), e)
+	case <-ctx.Done():
+		t.Fatal("strategy not consulted")
+	}
+	select {
+	case err := <-watchErr:
+		assert.Same(t, errStop, errors.Cause(err), "strategy error should stop the watcher")
+	case <-ctx.Done():
+		t.Fatal("watcher did not return")
+	}
+	assert.Empty(t, h, "handler should not be notified after the strategy failed")
+}
@@ -18,6 +18,9 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wire"
 )
 
 type (
@@ -36,6 +39,62 @@ type (
 	// network when trying to do on-chain transactions or reading from the blockchain.
 	ChainNotReachableError struct {
 	}
+
+	// ErrPeerDisconnected indicates that the connection to a peer was lost
+	// while a message was sent to it or its response was awaited. It is only
+	// detected on the receiving side if the bus is a wire.DisconnectNotifier.
+	// In contrast to a PeerRejectedError or RequestTimedOutError, the peer did
+	// not get the chance to respond, so the user may want to re-establish the
+	// connection and retry.
+	ErrPeerDisconnected struct {
+		Peer wire.Address // Peer that was disconnected.
+	}
+
+	// FutureVersionRegisteredError indicates that a state with a newer
+	// version than the local one was registered on-chain and could not be
+	// adopted, e.g., because it is not signed by all participants.
+	FutureVersionRegisteredError struct {
+		LocalVersion      uint64 // Version of the local state.
+		RegisteredVersion uint64 // Version of the registered state.
+	}
+
+	// ReserveViolatedError indicates that an update would drop the own balance
+	// of an asset below the reserve set with Channel.SetBalanceReserve.
+	ReserveViolatedError struct {
+		Asset   int         // Index of the asset.
+		Balance channel.Bal // Own balance after the update.
+		Reserve channel.Bal // Configured reserve.
+	}
+
+	// FundingAbortedError indicates that a peer did not fund a ledger channel
+	// in time and that the own deposits were withdrawn from the adjudicator
+	// again. The channel was not opened. Cause is the funding error, usually
+	// a channel.FundingTimeoutError that identifies the peers that did not
+	// fund.
+	FundingAbortedError struct {
+		ChannelID channel.ID // ID of the aborted channel.
+		Cause     error      // Error that caused the abort.
+	}
+
+	// ChallengeNotElapsedError indicates that a registered channel cannot be
+	// concluded yet because the challenge timeout of its registered state has
+	// not elapsed.
+	ChallengeNotElapsedError struct {
+		Version uint64 // Version of the registered state.
+	}
+
+	// FundingShortfallError indicates that the on-chain deposits of a channel
+	// do not cover the funds of its current state.
+	FundingShortfallError struct {
+		Shortfalls []AssetShortfall // Assets whose deposits fall short.
+	}
+
+	// AssetShortfall describes the deposits of an asset that fall short.
+	AssetShortfall struct {
+		Asset    int           // Index of the asset.
+		Deposits []channel.Bal // Deposits of the participants.
+		Required channel.Bal   // Funds of the asset in the current state.
+	}
 )
 
 // Error implements the error interface.
@@ -48,6 +107,45 @@ func (e ChainNotReachableError) Error() string {
 	return "blockchain network not reachable"
 }
 
+// Error implements the error interface.
+func (e ErrPeerDisconnected) Error() string {
+	return fmt.Sprintf("peer %v disconnected", e.Peer)
+}
+
+// Error implements the error interface.
+func (e FutureVersionRegisteredError) Error() string {
+	return fmt.Sprintf("registered version %d newer than local version %d", e.RegisteredVersion, e.LocalVersion)
+}
+
+// Error implements the error interface.
+func (e ReserveViolatedError) Error() string {
+	return fmt.Sprintf("balance %v of asset %d below reserve %v", e.Balance, e.Asset, e.Reserve)
+}
+
+// Error implements the error interface.
+func (e FundingAbortedError) Error() string {
+	return fmt.Sprintf("funding of channel %x aborted and deposits refunded: %v", e.ChannelID, e.Cause)
+}
+
+// Unwrap returns the error that caused the abort.
+func (e FundingAbortedError) Unwrap() error {
+	return e.Cause
+}
+
+// Error implements the error interface.
+func (e ChallengeNotElapsedError) Error() string {
+	return fmt.Sprintf("challenge timeout of registered version %d not elapsed", e.Version)
+}
+
+// Error implements the error interface.
+func (e FundingShortfallError) Error() string {
+	msg := "deposits short:"
+	for _, s := range e.Shortfalls {
+		msg += fmt.Sprintf(" asset %d has deposits %v, requires %v;", s.Asset, s.Deposits, s.Required)
+	}
+	return msg
+}
+
 // NewTxTimedoutError constructs a TxTimedoutError and wraps it with the actual
 // error message.
 //
@@ -66,3 +164,12 @@ func NewTxTimedoutError(txType, txID, actualErrMsg string) error {
 func NewChainNotReachableError(actualErr error) error {
 	return errors.Wrap(ChainNotReachableError{}, actualErr.Error())
 }
+
+// checkPeerDisconnected wraps err in an ErrPeerDisconnected if it was caused by
+// the loss of the connection to peer. Other errors are returned unchanged.
+func checkPeerDisconnected(err error, peer wire.Address) error {
+	if errors.Is(err, wire.ErrPeerUnreachable) {
+		return errors.Wrap(ErrPeerDisconnected{Peer: peer}, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+
+	"perun.network/go-perun/channel"
+)
+
+// eventQueue delivers the adjudicator events of a single channel watcher to
+// its handler, one after another and in the order they were pushed. Each
+// handler call occupies one slot of the client's handler pool.
+type eventQueue struct {
+	h     AdjudicatorEventHandler
+	slots chan struct{}
+
+	mu      sync.Mutex
+	events  []channel.AdjudicatorEvent
+	running bool
+}
+
+// SetEventHandlerPool bounds the number of concurrent HandleAdjudicatorEvent
+// calls of all channel watchers of the client to size. The events of a channel
+// are then delivered to its handler one after another, in the order they were
+// observed. A watcher does not wait for its handler, events are queued until a
+// slot of the pool is free.
+//
+// A size of zero, the default, disables the pool and notifies the handler
+// about every event in its own goroutine. This method is expected to be called
+// once during the setup of the client, before any channel is watched, and is
+// hence not thread-safe.
+func (c *Client) SetEventHandlerPool(size int) {
+	if size < 0 {
+		c.log.Panic("event handler pool size must not be negative")
+	}
+	if size == 0 {
+		c.eventSlots = nil
+		return
+	}
+	c.eventSlots = make(chan struct{}, size)
+}
+
+// eventDispatcher returns the function that a channel watcher uses to notify
+// h about an adjudicator event.
+func (c *Client) eventDispatcher(h AdjudicatorEventHandler) func(channel.AdjudicatorEvent) {
+	if c.eventSlots == nil {
+		return func(e channel.AdjudicatorEvent) { go h.HandleAdjudicatorEvent(e) }
+	}
+	q := &eventQueue{h: h, slots: c.eventSlots}
+	return q.push
+}
+
+// push queues e and starts delivering the queued events if no delivery is
+// running yet. It never blocks.
+func (q *eventQueue) push(e channel.AdjudicatorEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.events = append(q.events, e)
+	if !q.running {
+		q.running = true
+		go q.deliver()
+	}
+}
+
+// deliver passes the queued events to the handler until the queue is empty.
+func (q *eventQueue) deliver() {
+	for {
+		q.mu.Lock()
+		if len(q.events) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		e := q.events[0]
+		q.events[0] = nil
+		q.events = q.events[1:]
+		q.mu.Unlock()
+
+		q.slots <- struct{}{}
+		q.h.HandleAdjudicatorEvent(e)
+		<-q.slots
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+)
+
+// poolHandler records the events it handles and the maximal number of
+// concurrent calls of all handlers sharing the same counters.
+type poolHandler struct {
+	active, maxActive *int32
+	wg                *sync.WaitGroup
+
+	mu       sync.Mutex
+	versions []uint64
+}
+
+func (h *poolHandler) HandleAdjudicatorEvent(e channel.AdjudicatorEvent) {
+	defer h.wg.Done()
+	n := atomic.AddInt32(h.active, 1)
+	defer atomic.AddInt32(h.active, -1)
+	for max := atomic.LoadInt32(h.maxActive); n > max; max = atomic.LoadInt32(h.maxActive) {
+		if atomic.CompareAndSwapInt32(h.maxActive, max, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.versions = append(h.versions, e.Version())
+}
+
+func TestClient_SetEventHandlerPool(t *testing.T) {
+	const (
+		poolSize    = 2
+		numChannels = 4
+		numEvents   = 20
+	)
+	rng := pkgtest.Prng(t)
+	c := new(Client)
+	c.SetEventHandlerPool(poolSize)
+
+	var active, maxActive int32
+	var wg sync.WaitGroup
+	wg.Add(numChannels * numEvents)
+	handlers := make([]*poolHandler, numChannels)
+	for i := range handlers {
+		handlers[i] = &poolHandler{active: &active, maxActive: &maxActive, wg: &wg}
+		notify := c.eventDispatcher(handlers[i])
+		id := channeltest.NewRandomChannelID(rng)
+		for v := uint64(0); v < numEvents; v++ {
+			notify(&channel.ConcludedEvent{AdjudicatorEventBase: *channel.NewAdjudicatorEventBase(id, nil, v)})
+		}
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxActive), int32(poolSize), "pool size exceeded")
+	for i, h := range handlers {
+		h.mu.Lock()
+		for v := range h.versions {
+			assert.Equalf(t, uint64(v), h.versions[v], "channel %d: events out of order", i)
+		}
+		assert.Lenf(t, h.versions, numEvents, "channel %d", i)
+		h.mu.Unlock()
+	}
+}
+
+func TestClient_SetEventHandlerPool_NonBlocking(t *testing.T) {
+	c := new(Client)
+	c.SetEventHandlerPool(1)
+
+	block := make(chan struct{})
+	h := &blockingHandler{block: block}
+	notify := c.eventDispatcher(h)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for v := uint64(0); v < 10; v++ {
+			notify(&channel.ConcludedEvent{AdjudicatorEventBase: *channel.NewAdjudicatorEventBase(channel.ID{}, nil, v)})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("notifying should not wait for the handler")
+	}
+	close(block)
+}
+
+type blockingHandler struct{ block chan struct{} }
+
+func (h *blockingHandler) HandleAdjudicatorEvent(channel.AdjudicatorEvent) { <-h.block }
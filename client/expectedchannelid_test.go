@@ -0,0 +1,66 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestClient_ExpectedChannelID(t *testing.T) {
+	rng := test.Prng(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	alice, bob := clients[0], clients[1]
+
+	type result struct {
+		expected channel.ID
+		ch       *client.Channel
+	}
+	results := make(chan result, 1)
+	var proposalHandlerBob client.ProposalHandlerFunc = func(cp client.ChannelProposal, pr *client.ProposalResponder) {
+		acc := cp.(*client.LedgerChannelProposal).Accept(bob.Identity.Address(), client.WithRandomNonce())
+		expected, err := bob.ExpectedChannelID(cp, acc)
+		assert.NoError(t, err)
+
+		_, err = bob.ExpectedChannelID(cp, new(client.SubChannelProposalAcc))
+		assert.Error(t, err, "mismatching accept message")
+
+		ch, err := pr.Accept(ctx, acc)
+		assert.NoError(t, err)
+		results <- result{expected, ch}
+	}
+	go bob.Handle(proposalHandlerBob, client.UpdateHandlerFunc(
+		func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+			assert.NoError(t, ur.Reject(ctx, "unexpected update"))
+		}))
+
+	res, err := alice.ProposeChannel(ctx, newLedgerChannelProposal(t, rng, alice, bob))
+	require.NoError(t, err)
+
+	r := <-results
+	require.NotNil(t, r.ch)
+	assert.Equal(t, r.expected, r.ch.ID())
+	assert.Equal(t, r.expected, res.Channel.ID())
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	perunio "perun.network/go-perun/pkg/io"
+	"perun.network/go-perun/wire"
+)
+
+// Export serializes all data that is needed to restore the channel controller
+// into a self-contained blob: the own index, the parameters, the current and
+// staging transactions, the phase, the peers and the parent channel's ID.
+// The blob can be imported with Client.ImportChannel.
+//
+// Sub-channels are not included and have to be exported separately.
+// Can not be called from an update handler.
+func (c *Channel) Export() ([]byte, error) {
+	c.machMtx.Lock()
+	defer c.machMtx.Unlock()
+
+	var parent *channel.ID
+	if c.parent != nil {
+		id := c.parent.ID()
+		parent = &id
+	}
+	pch := persistence.FromSource(c.machine, c.Peers(), parent)
+
+	var buf bytes.Buffer
+	if err := encodeChannel(&buf, pch); err != nil {
+		return nil, errors.WithMessage(err, "encoding channel")
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportChannel restores a channel controller from a blob that was created
+// with Channel.Export. The channel is persisted and put into the client's
+// channel registry, so that the OnNewChannel callback is called.
+//
+// If the channel is a sub-channel, its parent channel must already be known to
+// the client. Importing a channel that is already known fails.
+func (c *Client) ImportChannel(ctx context.Context, blob []byte) (*Channel, error) {
+	pch, err := decodeChannel(bytes.NewReader(blob))
+	if err != nil {
+		return nil, errors.WithMessage(err, "decoding channel")
+	}
+	if _, ok := c.channels.Get(pch.ID()); ok {
+		return nil, errors.Errorf("channel already exists: %v", pch.ID())
+	}
+
+	var parent *Channel
+	if pch.Parent != nil {
+		var ok bool
+		if parent, ok = c.channels.Get(*pch.Parent); !ok {
+			return nil, errors.Errorf("unknown parent channel: %v", *pch.Parent)
+		}
+	}
+
+	ch, err := c.channelFromSource(pch, parent, pch.PeersV...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "restoring channel")
+	}
+	if err := c.pr.ChannelCreated(ctx, ch.machine, pch.PeersV, pch.Parent); err != nil {
+		// nolint:errcheck,gosec
+		ch.Close()
+		return nil, errors.WithMessage(err, "persisting imported channel")
+	}
+	if !c.channels.Put(ch.ID(), ch) {
+		// nolint:errcheck,gosec
+		ch.Close()
+		return nil, errors.Errorf("failed to put channel into registry: %v", ch.ID())
+	}
+	return ch, nil
+}
+
+func encodeChannel(w io.Writer, ch *persistence.Channel) error {
+	if err := perunio.Encode(w,
+		uint16(ch.IdxV),
+		ch.ParamsV,
+		ch.CurrentTXV,
+		ch.StagingTXV,
+		ch.PhaseV,
+		wire.AddressesWithLen(ch.PeersV),
+		ch.Parent != nil,
+	); err != nil {
+		return err
+	}
+	if ch.Parent != nil {
+		return perunio.Encode(w, *ch.Parent)
+	}
+	return nil
+}
+
+func decodeChannel(r *bytes.Reader) (*persistence.Channel, error) {
+	var (
+		ch        = persistence.NewChannel()
+		idx       uint16
+		peers     wire.AddressesWithLen
+		hasParent bool
+	)
+	if err := perunio.Decode(r,
+		&idx,
+		ch.ParamsV,
+		&ch.CurrentTXV,
+		&ch.StagingTXV,
+		&ch.PhaseV,
+		&peers,
+		&hasParent,
+	); err != nil {
+		return nil, err
+	}
+	ch.IdxV = channel.Index(idx)
+	ch.PeersV = peers
+	if hasParent {
+		ch.Parent = new(channel.ID)
+		if err := perunio.Decode(r, ch.Parent); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("trailing data")
+	}
+	return ch, nil
+}
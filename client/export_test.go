@@ -0,0 +1,53 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	pkgtest "perun.network/go-perun/pkg/test"
+	wiretest "perun.network/go-perun/wire/test"
+)
+
+func TestEncodeDecodeChannel(t *testing.T) {
+	rng := pkgtest.Prng(t)
+
+	for _, withParent := range []bool{false, true} {
+		ch := mkRndChan(rng)
+		ch.PeersV = wiretest.NewRandomAddresses(rng, len(ch.ParamsV.Parts))
+		if withParent {
+			ch.Parent = new(channel.ID)
+			rng.Read(ch.Parent[:])
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, encodeChannel(&buf, ch))
+		blob := buf.Bytes()
+
+		decoded, err := decodeChannel(bytes.NewReader(blob))
+		require.NoError(t, err)
+		assert.Equal(t, ch, decoded)
+
+		_, err = decodeChannel(bytes.NewReader(append(blob, 0)))
+		assert.Error(t, err, "trailing data should be rejected")
+		_, err = decodeChannel(bytes.NewReader(blob[:len(blob)-1]))
+		assert.Error(t, err, "truncated data should be rejected")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	ctest "perun.network/go-perun/client/test"
+	"perun.network/go-perun/pkg/test"
+)
+
+// timeoutFunder is a funder that deposits the own funds, but whose peers never
+// fund.
+type timeoutFunder struct {
+	b *ctest.MockBackend
+}
+
+func (f timeoutFunder) Fund(_ context.Context, req channel.FundingReq) error {
+	for a, asset := range req.State.Assets {
+		f.b.Deposit(req.Params.Parts[req.Idx], asset, req.Agreement[a][req.Idx])
+	}
+	return channel.NewFundingTimeoutError([]*channel.AssetFundingError{{Asset: 0, TimedOutPeers: []channel.Index{1}}})
+}
+
+func TestClient_FundingAborted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	setups := NewSetups(rng, []string{"Alice", "Bob"})
+	setups[0].Funder = timeoutFunder{b: setups[0].Funder.(*ctest.MockBackend)}
+	clients := newClientsFromSetups(rng, setups, t)
+	alice, bob := clients[0], clients[1]
+
+	accepted := make(chan error, 1)
+	go bob.Handle(client.ProposalHandlerFunc(func(cp client.ChannelProposal, pr *client.ProposalResponder) {
+		_, err := pr.Accept(ctx, cp.(*client.LedgerChannelProposal).Accept(bob.Identity.Address(), client.WithRandomNonce()))
+		accepted <- err
+	}), client.UpdateHandlerFunc(nil))
+
+	prop := newLedgerChannelProposal(t, rng, alice, bob)
+	asset := prop.InitBals.Assets[0]
+
+	balance := alice.Backend.GetBalance(alice.Identity.Address(), asset)
+	res, err := alice.ProposeChannel(ctx, prop)
+	require.Error(t, err)
+	require.NoError(t, <-accepted)
+	var abortErr client.FundingAbortedError
+	require.True(t, errors.As(err, &abortErr), "expected FundingAbortedError, got %v", err)
+	assert.Equal(t, res.ID, abortErr.ChannelID)
+	require.True(t, channel.IsFundingTimeoutError(err), "cause should be kept")
+	var timeoutErr channel.FundingTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, []channel.Index{1}, timeoutErr.Errors[0].TimedOutPeers)
+	assert.Equal(t, channel.Withdrawn, res.Channel.Phase())
+	assert.Zero(t, alice.Backend.GetBalance(alice.Identity.Address(), asset).Cmp(balance), "deposit should be refunded")
+	_, err = alice.Channel(res.ID)
+	assert.Error(t, err, "aborted channel should not be registered")
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+type (
+	// IdentityMapper resolves the on-chain participant address that belongs
+	// to a wire identity and vice versa.
+	//
+	// A Perun node has two kinds of identities: its wire.Address identifies
+	// it in the peer-to-peer network, while its wallet.Address identifies it
+	// as a channel participant on-chain. They can be unrelated, so the
+	// mapping between them has to be provided by the user.
+	IdentityMapper interface {
+		// WalletAddress returns the on-chain address of the given peer.
+		WalletAddress(wire.Address) (wallet.Address, error)
+		// WireAddress returns the wire identity of the given participant.
+		WireAddress(wallet.Address) (wire.Address, error)
+	}
+
+	// SameKeyIdentityMapper is the IdentityMapper for setups in which the wire
+	// identity and the on-chain address of every node are the same address,
+	// i.e., derived from the same key.
+	SameKeyIdentityMapper struct{}
+)
+
+// WalletAddress returns the peer's wire address as its on-chain address.
+func (SameKeyIdentityMapper) WalletAddress(a wire.Address) (wallet.Address, error) {
+	return a, nil
+}
+
+// WireAddress returns the participant's on-chain address as its wire address.
+func (SameKeyIdentityMapper) WireAddress(a wallet.Address) (wire.Address, error) {
+	return a, nil
+}
+
+// SetIdentityMapper sets the IdentityMapper that the client uses to resolve
+// its own on-chain participant address, see ParticipantAddress. This method
+// is expected to be called once during the setup of the client and is hence
+// not thread-safe.
+func (c *Client) SetIdentityMapper(m IdentityMapper) {
+	c.identities = m
+}
+
+// ParticipantAddress returns the on-chain participant address of the client,
+// as resolved by its IdentityMapper from its wire address.
+func (c *Client) ParticipantAddress() (wallet.Address, error) {
+	if c.identities == nil {
+		return nil, errors.New("no identity mapper set")
+	}
+	addr, err := c.identities.WalletAddress(c.address)
+	return addr, errors.WithMessage(err, "resolving own participant address")
+}
+
+// NewLedgerChannelProposal is like the package-level NewLedgerChannelProposal,
+// but uses ParticipantAddress as the proposer's participant address. If no
+// nonce share is configured in opts, it is created with RandomNonce.
+func (c *Client) NewLedgerChannelProposal(
+	challengeDuration uint64,
+	initBals *channel.Allocation,
+	peers []wire.Address,
+	opts ...ProposalOpts,
+) (*LedgerChannelProposal, error) {
+	participant, err := c.ParticipantAddress()
+	if err != nil {
+		return nil, err
+	}
+	if !union(opts...).isNonce() {
+		opts = append(opts[:len(opts):len(opts)], c.RandomNonce())
+	}
+	return NewLedgerChannelProposal(challengeDuration, participant, initBals, peers, opts...)
+}
+
+// AcceptLedgerChannelProposal returns the acceptance message of the given
+// proposal, using ParticipantAddress as the own participant address. If
+// nonceShare does not configure a nonce share, it is created with RandomNonce.
+func (c *Client) AcceptLedgerChannelProposal(prop *LedgerChannelProposal, nonceShare ProposalOpts) (*LedgerChannelProposalAcc, error) {
+	participant, err := c.ParticipantAddress()
+	if err != nil {
+		return nil, err
+	}
+	if !nonceShare.isNonce() {
+		nonceShare = c.RandomNonce()
+	}
+	return prop.Accept(participant, nonceShare), nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestClient_IdentityMapper(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	alice, bob := clients[0], clients[1]
+	// Only the balances and peers are taken, the participant is derived by the
+	// identity mapper.
+	base := newLedgerChannelProposal(t, rng, alice, bob)
+	initBals, peers := base.InitBals, base.Peers
+
+	_, err := alice.NewLedgerChannelProposal(challengeDuration, initBals, peers)
+	assert.Error(t, err, "proposing without identity mapper should fail")
+
+	for _, c := range clients {
+		c.SetIdentityMapper(client.SameKeyIdentityMapper{})
+	}
+	accepted := make(chan error, 1)
+	go bob.Handle(client.ProposalHandlerFunc(func(cp client.ChannelProposal, pr *client.ProposalResponder) {
+		acc, err := bob.AcceptLedgerChannelProposal(cp.(*client.LedgerChannelProposal), client.WithRandomNonce())
+		if err == nil {
+			_, err = pr.Accept(ctx, acc)
+		}
+		accepted <- err
+	}), client.UpdateHandlerFunc(nil))
+
+	prop, err := alice.NewLedgerChannelProposal(challengeDuration, initBals, peers)
+	require.NoError(t, err)
+	assert.True(t, prop.Participant.Equals(alice.Identity.Address()))
+	res, err := alice.ProposeChannel(ctx, prop)
+	require.NoError(t, err)
+	require.NoError(t, <-accepted)
+	assert.True(t, res.Params.Parts[1].Equals(bob.Identity.Address()))
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wire"
+)
+
+// InvalidUpdatePolicy defines how the client responds to invalid incoming
+// channel updates, e.g., updates with an invalid signature or an invalid
+// transition. Updates that are valid but violate the own reserve or update
+// invariant are always rejected and not affected by the policy.
+type InvalidUpdatePolicy int
+
+const (
+	// LogInvalidUpdate logs and drops invalid updates. The peer will time out
+	// waiting for a response.
+	LogInvalidUpdate InvalidUpdatePolicy = iota
+	// RejectInvalidUpdate logs invalid updates and rejects them with code
+	// UpdateRejectInvalid, stating why the update is invalid.
+	RejectInvalidUpdate
+	// DisconnectInvalidUpdate logs invalid updates and closes the connection
+	// to the peer that sent them. If the bus does not implement
+	// wire.Disconnecter, the update is rejected instead.
+	DisconnectInvalidUpdate
+)
+
+// SetInvalidUpdatePolicy sets how the client responds to invalid incoming
+// channel updates. The default is LogInvalidUpdate. This method is expected to
+// be called once during the setup of the client, before Handle is started,
+// and is hence not thread-safe.
+func (c *Client) SetInvalidUpdatePolicy(p InvalidUpdatePolicy) {
+	c.invalidUpdatePolicy = p
+}
+
+// handleInvalidUpdate responds to the invalid update request of peer pidx
+// according to the client's InvalidUpdatePolicy. invalid states why the update
+// is invalid.
+func (c *Channel) handleInvalidUpdate(pidx channel.Index, responder *UpdateResponder, invalid error) {
+	c.logPeer(pidx).Warnf("invalid update received: %v", invalid)
+
+	switch c.client.invalidUpdatePolicy {
+	case LogInvalidUpdate:
+		return
+	case DisconnectInvalidUpdate:
+		d, ok := c.client.conn.bus.(wire.Disconnecter)
+		if !ok {
+			c.logPeer(pidx).Warn("Bus cannot disconnect peers, rejecting invalid update instead.")
+			break
+		}
+		if err := d.Disconnect(c.Peers()[pidx]); err != nil {
+			c.logPeer(pidx).Errorf("disconnecting peer: %v", err)
+		}
+		return
+	}
+	c.client.rejectProposal(responder, UpdateRejectInvalid, invalid.Error())
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wire"
+)
+
+// tamperingBus corrupts the signature of all channel updates published over
+// it.
+type tamperingBus struct{ wire.Bus }
+
+func (b tamperingBus) Publish(ctx context.Context, e *wire.Envelope) error {
+	if e.Msg.Type() != wire.ChannelUpdate {
+		return b.Bus.Publish(ctx, e)
+	}
+	var buf bytes.Buffer
+	if err := wire.Encode(e.Msg, &buf); err != nil {
+		return err
+	}
+	// Updates without a payload end with the signature.
+	enc := buf.Bytes()
+	enc[len(enc)-3] ^= 0xff
+	msg, err := wire.Decode(bytes.NewReader(enc))
+	if err != nil {
+		return err
+	}
+	return b.Bus.Publish(ctx, &wire.Envelope{Sender: e.Sender, Recipient: e.Recipient, Msg: msg})
+}
+
+// disconnectingBus records the peers that are disconnected.
+type disconnectingBus struct {
+	wire.Bus
+	disconnected chan wire.Address
+}
+
+func (b *disconnectingBus) Disconnect(peer wire.Address) error {
+	b.disconnected <- peer
+	return nil
+}
+
+func TestClient_SetInvalidUpdatePolicy(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	// setup returns Alice and Bob, where Bob uses the given policy and
+	// receives invalid updates from Alice.
+	setup := func(rng *rand.Rand, policy client.InvalidUpdatePolicy) (chAlice *client.Channel, busBob *disconnectingBus) {
+		setups := NewSetups(rng, []string{"Alice", "Bob"})
+		setups[0].Bus = tamperingBus{setups[0].Bus}
+		busBob = &disconnectingBus{Bus: setups[1].Bus, disconnected: make(chan wire.Address, 1)}
+		setups[1].Bus = busBob
+		clients := newClientsFromSetups(rng, setups, t)
+		clients[1].SetInvalidUpdatePolicy(policy)
+		var updateHandlerBob client.UpdateHandlerFunc = func(*channel.State, client.ChannelUpdate, *client.UpdateResponder) {
+			t.Error("invalid update should not reach the update handler")
+		}
+		chAlice, _ = openChannel(ctx, t, rng, clients[0], clients[1], updateHandlerBob)
+		return chAlice, busBob
+	}
+	// transferTimeout transfers from Alice to Bob and gives up after a short
+	// time because Bob might not respond.
+	transferTimeout := func(ch *client.Channel) error {
+		ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		return transfer(ctx, ch, 1)
+	}
+
+	t.Run("log", func(t *testing.T) {
+		chAlice, _ := setup(rng, client.LogInvalidUpdate)
+		err := transferTimeout(chAlice)
+		assert.True(t, errors.As(err, new(client.RequestTimedOutError)), "update should time out, got %v", err)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		chAlice, _ := setup(rng, client.RejectInvalidUpdate)
+		err := transferTimeout(chAlice)
+		var rejErr client.PeerRejectedError
+		require.True(t, errors.As(err, &rejErr), "expected PeerRejectedError, got %v", err)
+		assert.Equal(t, client.UpdateRejectInvalid, rejErr.UpdateCode)
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		chAlice, busBob := setup(rng, client.DisconnectInvalidUpdate)
+		err := transferTimeout(chAlice)
+		assert.True(t, errors.As(err, new(client.RequestTimedOutError)), "update should time out, got %v", err)
+		select {
+		case peer := <-busBob.disconnected:
+			assert.Equal(t, wire.Key(chAlice.Peers()[0]), wire.Key(peer), "Alice should be disconnected")
+		case <-ctx.Done():
+			t.Fatal("Alice was not disconnected")
+		}
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	chtest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wire"
+)
+
+// droppingBus reports the connections to all peers as lost once dropped is
+// closed.
+type droppingBus struct {
+	wire.Bus
+	dropped chan struct{}
+}
+
+func (b *droppingBus) Disconnected(wire.Address) <-chan struct{} {
+	return b.dropped
+}
+
+func TestClient_PeerDisconnected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	// setup returns Alice and Bob, where Alice's connection to Bob can be
+	// dropped.
+	setup := func() (alice, bob *Client, busAlice *droppingBus) {
+		setups := NewSetups(rng, []string{"Alice", "Bob"})
+		busAlice = &droppingBus{Bus: setups[0].Bus, dropped: make(chan struct{})}
+		setups[0].Bus = busAlice
+		clients := newClientsFromSetups(rng, setups, t)
+		return clients[0], clients[1], busAlice
+	}
+	requireDisconnected := func(t *testing.T, err error, peer wire.Address) {
+		t.Helper()
+		var disconnected client.ErrPeerDisconnected
+		require.True(t, errors.As(err, &disconnected), "expected ErrPeerDisconnected, got %v", err)
+		assert.Equal(t, wire.Key(peer), wire.Key(disconnected.Peer))
+	}
+
+	t.Run("update", func(t *testing.T) {
+		alice, bob, busAlice := setup()
+		// The connection drops while Bob handles the update, so he never
+		// responds.
+		var updateHandlerBob client.UpdateHandlerFunc = func(*channel.State, client.ChannelUpdate, *client.UpdateResponder) {
+			close(busAlice.dropped)
+		}
+		chAlice, _ := openChannel(ctx, t, rng, alice, bob, updateHandlerBob)
+
+		requireDisconnected(t, transfer(ctx, chAlice, 1), bob.Identity.Address())
+	})
+
+	t.Run("proposal", func(t *testing.T) {
+		alice, bob, busAlice := setup()
+		// The connection drops while Bob handles the proposal, so he never
+		// responds.
+		var proposalHandlerBob client.ProposalHandlerFunc = func(client.ChannelProposal, *client.ProposalResponder) {
+			close(busAlice.dropped)
+		}
+		go bob.Handle(proposalHandlerBob, client.UpdateHandlerFunc(func(*channel.State, client.ChannelUpdate, *client.UpdateResponder) {}))
+
+		prop, err := client.NewLedgerChannelProposal(
+			challengeDuration,
+			alice.Identity.Address(),
+			&channel.Allocation{
+				Assets:   []channel.Asset{chtest.NewRandomAsset(rng)},
+				Balances: [][]channel.Bal{{big.NewInt(10), big.NewInt(10)}},
+			},
+			[]wire.Address{alice.Identity.Address(), bob.Identity.Address()},
+		)
+		require.NoError(t, err)
+		_, err = alice.ProposeChannel(ctx, prop)
+		requireDisconnected(t, err, bob.Identity.Address())
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -60,10 +61,39 @@ type (
 	}
 
 	// PeerRejectedError indicates the channel proposal or channel update was
-	// rejected by the peer.
+	// rejected by the peer. For rejected channel updates, it identifies the
+	// rejected update by its channel ID and version.
 	PeerRejectedError struct {
-		ItemType string // ItemType indicates the type of item rejected (channel proposal or channel update).
-		Reason   string // Reason sent by the peer for the rejection.
+		ItemType   string             // ItemType indicates the type of item rejected (channel proposal or channel update).
+		ChannelID  channel.ID         // ChannelID of the rejected update. Zero for channel proposals.
+		Version    uint64             // Version of the rejected update. Zero for channel proposals.
+		Reason     string             // Reason sent by the peer for the rejection.
+		Code       ProposalRejectCode // Code sent by the peer for the rejection of a channel proposal.
+		UpdateCode UpdateRejectCode   // Code sent by the peer for the rejection of a channel update.
+	}
+
+	// ProposalResult is returned by ProposeChannel. Besides the new channel
+	// controller, it contains the identifiers of the channel that were
+	// negotiated in the proposal protocol, so that they can be displayed or
+	// persisted right away.
+	ProposalResult struct {
+		Channel *Channel        // The new channel controller.
+		Params  *channel.Params // Final parameters, including the negotiated nonce.
+		ID      channel.ID      // ID of the channel.
+		// FundingIDs contains the funding IDs of the participants, indexed by
+		// asset and participant. Funding IDs are backend-specific, so they are
+		// only set if the Funder implements FundingIDProvider.
+		FundingIDs [][][32]byte
+	}
+
+	// FundingIDProvider can optionally be implemented by a channel.Funder to
+	// expose the backend-specific funding IDs under which the participants
+	// deposit their funds.
+	FundingIDProvider interface {
+		// FundingIDs returns the funding IDs of the participants of the
+		// channel with the given parameters, indexed by asset and
+		// participant. The funding IDs may differ between assets.
+		FundingIDs(params *channel.Params, assets []channel.Asset) [][][32]byte
 	}
 )
 
@@ -90,13 +120,35 @@ func (f ProposalHandlerFunc) HandleProposal(p ChannelProposal, r *ProposalRespon
 // After the channel got successfully created, the user is required to start the
 // channel watcher with Channel.Watch() on the returned channel controller.
 //
+// Returns FundingAbortedError if any of the participants do not fund the
+// channel in time. The own deposits are refunded in this case. The error
+// wraps the channel.FundingTimeoutError, so channel.IsFundingTimeoutError
+// matches it.
 // Returns TxTimedoutError when the program times out waiting for a transaction
 // to be mined.
 // Returns ChainNotReachableError if the connection to the blockchain network
 // fails when sending a transaction to / reading from the blockchain.
 func (r *ProposalResponder) Accept(ctx context.Context, acc ChannelProposalAccept) (*Channel, error) {
+	ch, funded, err := r.AcceptAsync(ctx, acc)
+	if err != nil {
+		return ch, err
+	}
+	return ch, <-funded
+}
+
+// AcceptAsync is like Accept but returns the channel controller as soon as
+// the initial state is signed by all participants, before the channel is
+// funded. The funding continues in the background using ctx. Its result is
+// sent on the returned channel, which receives nil if the channel was funded
+// successfully. Only then, the channel is passed to the callback registered
+// with Client.OnNewChannel and can be updated.
+//
+// It can be used to show that a channel was agreed upon while its funding is
+// still in progress. The same context requirements and errors as for Accept
+// apply, with funding errors being sent on the returned channel.
+func (r *ProposalResponder) AcceptAsync(ctx context.Context, acc ChannelProposalAccept) (*Channel, <-chan error, error) {
 	if ctx == nil {
-		return nil, errors.New("context must not be nil")
+		return nil, nil, errors.New("context must not be nil")
 	}
 
 	if !r.called.TrySet() {
@@ -110,17 +162,25 @@ func (r *ProposalResponder) Accept(ctx context.Context, acc ChannelProposalAccep
 // Returns whether the rejection message was successfully sent. Panics if the
 // proposal was already accepted or rejected.
 func (r *ProposalResponder) Reject(ctx context.Context, reason string) error {
+	return r.RejectWithCode(ctx, ProposalRejectUnspecified, reason)
+}
+
+// RejectWithCode is like Reject but additionally sends a reason code to the
+// proposer, which is then contained in the PeerRejectedError returned by
+// ProposeChannel.
+func (r *ProposalResponder) RejectWithCode(ctx context.Context, code ProposalRejectCode, reason string) error {
 	if !r.called.TrySet() {
 		log.Panic("multiple calls on proposal responder")
 	}
-	return r.client.handleChannelProposalRej(ctx, r.peer, r.req, reason)
+	return r.client.handleChannelProposalRej(ctx, r.peer, r.req, code, reason)
 }
 
 // ProposeChannel attempts to open a channel with the parameters and peers from
 // ChannelProposal prop:
-// - the proposal is sent to the peers and if all peers accept,
-// - the channel is funded. If successful,
-// - the channel controller is returned.
+//   - the proposal is sent to the peers and if all peers accept,
+//   - the channel is funded. If successful,
+//   - the channel controller is returned together with the negotiated
+//     identifiers of the channel in a ProposalResult.
 //
 // After the channel controller got successfully set up, it is passed to the
 // callback registered with Client.OnNewChannel. Accept returns after this
@@ -134,16 +194,39 @@ func (r *ProposalResponder) Reject(ctx context.Context, reason string) error {
 // channel watcher with Channel.Watch() on the returned channel
 // controller.
 //
-// Returns PeerRejectedProposalError if the channel is rejected by the peer.
+// Returns PeerRejectedError if the channel is rejected by the peer. Its Code
+// states why the peer rejected the proposal, if the peer gave a reason code.
 // Returns RequestTimedOutError if the peer did not respond before the context
 // expires or is cancelled.
-// Returns FundingTimeoutError if any of the participants do not fund the
-// channel in time.
+// Returns FundingAbortedError if any of the participants do not fund the
+// channel in time. The own deposits are refunded in this case. The error
+// wraps the channel.FundingTimeoutError, so channel.IsFundingTimeoutError
+// matches it.
 // Returns TxTimedoutError when the program times out waiting for a transaction
 // to be mined.
 // Returns ChainNotReachableError if the connection to the blockchain network
 // fails when sending a transaction to / reading from the blockchain.
-func (c *Client) ProposeChannel(ctx context.Context, prop ChannelProposal) (*Channel, error) {
+func (c *Client) ProposeChannel(ctx context.Context, prop ChannelProposal) (*ProposalResult, error) {
+	res, funded, err := c.ProposeChannelAsync(ctx, prop)
+	if err != nil {
+		return nil, err
+	}
+	return res, <-funded
+}
+
+// ProposeChannelAsync is like ProposeChannel but returns the ProposalResult as
+// soon as the peer accepted the proposal and the initial state is signed by
+// all participants, before the channel is funded. The funding continues in
+// the background using ctx. Its result is sent on the returned channel, which
+// receives nil if the channel was funded successfully. Only then, the channel
+// is passed to the callback registered with Client.OnNewChannel and can be
+// updated.
+//
+// It can be used to show that a channel was agreed upon while its funding is
+// still in progress. The same context requirements and errors as for
+// ProposeChannel apply, with funding errors being sent on the returned
+// channel.
+func (c *Client) ProposeChannelAsync(ctx context.Context, prop ChannelProposal) (*ProposalResult, <-chan error, error) {
 	if ctx == nil {
 		c.log.Panic("invalid nil argument")
 	}
@@ -151,27 +234,56 @@ func (c *Client) ProposeChannel(ctx context.Context, prop ChannelProposal) (*Cha
 	// Prepare and cleanup, e.g., for locking and unlocking parent channel.
 	err := c.prepareChannelOpening(ctx, prop, proposerIdx)
 	if err != nil {
-		return nil, errors.WithMessage(err, "preparing channel opening")
+		return nil, nil, errors.WithMessage(err, "preparing channel opening")
 	}
-	defer c.cleanupChannelOpening(prop, proposerIdx)
+	cleanup := func() { c.cleanupChannelOpening(prop, proposerIdx) }
 
 	// 1. validate input
 	peer := c.proposalPeers(prop)[proposeeIdx]
 	if err := c.validTwoPartyProposal(prop, proposerIdx, peer); err != nil {
-		return nil, errors.WithMessage(err, "invalid channel proposal")
+		cleanup()
+		return nil, nil, errors.WithMessage(err, "invalid channel proposal")
 	}
 
 	// 2. send proposal, wait for response, create channel object
-	c.enableVer1Cache()        // cache version 1 updates until channel is opened
-	defer c.releaseVer1Cache() // replay cached version 1 updates
+	c.enableVer1Cache() // cache version 1 updates until channel is opened
+	cleanup = func() {
+		c.releaseVer1Cache() // replay cached version 1 updates
+		c.cleanupChannelOpening(prop, proposerIdx)
+	}
 	ch, err := c.proposeTwoPartyChannel(ctx, prop)
 	if err != nil {
-		return nil, errors.WithMessage(err, "channel proposal")
+		cleanup()
+		return nil, nil, errors.WithMessage(err, "channel proposal")
 	}
 
 	// 3. fund
-	fundingErr := c.fundChannel(ctx, ch, prop)
-	return ch, fundingErr
+	return c.newProposalResult(ch), c.fundChannelAsync(ctx, ch, prop, cleanup), nil
+}
+
+// fundChannelAsync funds the channel in the background. Afterwards, cleanup
+// is called and the funding result is sent on the returned channel.
+func (c *Client) fundChannelAsync(ctx context.Context, ch *Channel, prop ChannelProposal, cleanup func()) <-chan error {
+	funded := make(chan error, 1)
+	go func() {
+		err := c.fundChannel(ctx, ch, prop)
+		cleanup()
+		funded <- err
+	}()
+	return funded
+}
+
+// newProposalResult collects the negotiated identifiers of the channel.
+func (c *Client) newProposalResult(ch *Channel) *ProposalResult {
+	res := &ProposalResult{
+		Channel: ch,
+		Params:  ch.Params(),
+		ID:      ch.ID(),
+	}
+	if p, ok := c.funder.(FundingIDProvider); ok {
+		res.FundingIDs = p.FundingIDs(res.Params, ch.State().Assets)
+	}
+	return res
 }
 
 func (c *Client) prepareChannelOpening(ctx context.Context, prop ChannelProposal, ourIdx channel.Index) (err error) {
@@ -206,6 +318,17 @@ func (c *Client) cleanupChannelOpening(prop ChannelProposal, ourIdx channel.Inde
 func (c *Client) handleChannelProposal(handler ProposalHandler, p wire.Address, req ChannelProposal) {
 	ourIdx := channel.Index(proposeeIdx)
 
+	if !c.proposalLimiter.acquire(p) {
+		c.logPeer(p).Warn("rejecting channel proposal: ", tooManyProposalsReason)
+		ctx, cancel := c.timeoutCtx(c.cfg.ResponseTimeout)
+		defer cancel()
+		if err := c.handleChannelProposalRej(ctx, p, req, ProposalRejectPolicyDenied, tooManyProposalsReason); err != nil {
+			c.logPeer(p).Warn("rejecting channel proposal: ", err)
+		}
+		return
+	}
+	defer c.proposalLimiter.release(p)
+
 	// Prepare and cleanup, e.g., for locking and unlocking parent channel.
 	err := c.prepareChannelOpening(c.Ctx(), req, ourIdx)
 	if err != nil {
@@ -228,20 +351,20 @@ func (c *Client) handleChannelProposal(handler ProposalHandler, p wire.Address,
 func (c *Client) handleChannelProposalAcc(
 	ctx context.Context, p wire.Address,
 	prop ChannelProposal, acc ChannelProposalAccept,
-) (ch *Channel, err error) {
+) (*Channel, <-chan error, error) {
 	if err := c.validChannelProposalAcc(prop, acc); err != nil {
-		return ch, errors.WithMessage(err, "validating channel proposal acceptance")
+		return nil, nil, errors.WithMessage(err, "validating channel proposal acceptance")
 	}
 
-	c.enableVer1Cache()        // cache version 1 updates
-	defer c.releaseVer1Cache() // replay cached version 1 updates
-
-	if ch, err = c.acceptChannelProposal(ctx, prop, p, acc); err != nil {
-		return ch, errors.WithMessage(err, "accept channel proposal")
+	c.enableVer1Cache() // cache version 1 updates
+	ch, err := c.acceptChannelProposal(ctx, prop, p, acc)
+	if err != nil {
+		c.releaseVer1Cache()
+		return ch, nil, errors.WithMessage(err, "accept channel proposal")
 	}
 
-	fundingErr := c.fundChannel(ctx, ch, prop)
-	return ch, fundingErr
+	// replay cached version 1 updates after funding
+	return ch, c.fundChannelAsync(ctx, ch, prop, c.releaseVer1Cache), nil
 }
 
 func (c *Client) acceptChannelProposal(
@@ -270,10 +393,11 @@ func (c *Client) acceptChannelProposal(
 
 func (c *Client) handleChannelProposalRej(
 	ctx context.Context, p wire.Address,
-	req ChannelProposal, reason string,
+	req ChannelProposal, code ProposalRejectCode, reason string,
 ) error {
 	msgReject := &ChannelProposalRej{
 		ProposalID: req.ProposalID(),
+		Code:       code,
 		Reason:     reason,
 	}
 	if err := c.conn.pubMsg(ctx, msgReject, p); err != nil {
@@ -300,9 +424,9 @@ func (c *Client) proposeTwoPartyChannel(
 	proposalID := proposal.ProposalID()
 	isResponse := func(e *wire.Envelope) bool {
 		acc, isAcc := e.Msg.(ChannelProposalAccept)
+		rej, isRej := e.Msg.(*ChannelProposalRej)
 		return (isAcc && acc.Base().ProposalID == proposalID) ||
-			(e.Msg.Type() == wire.ChannelProposalRej &&
-				e.Msg.(*ChannelProposalRej).ProposalID == proposalID)
+			(isRej && rej.ProposalID == proposalID)
 	}
 	receiver := wire.NewReceiver()
 	// nolint:errcheck
@@ -316,7 +440,7 @@ func (c *Client) proposeTwoPartyChannel(
 		return nil, errors.WithMessage(err, "publishing channel proposal")
 	}
 
-	env, err := receiver.Next(ctx)
+	env, err := nextFrom(ctx, receiver, &c.conn, peer)
 	if err != nil {
 		if pcontext.IsContextError(err) {
 			return nil, newRequestTimedOutError("channel proposal", err.Error())
@@ -324,7 +448,11 @@ func (c *Client) proposeTwoPartyChannel(
 		return nil, errors.WithMessage(err, "receiving proposal response")
 	}
 	if rej, ok := env.Msg.(*ChannelProposalRej); ok {
-		return nil, newPeerRejectedError("channel proposal", rej.Reason)
+		return nil, errors.WithStack(PeerRejectedError{
+			ItemType: "channel proposal",
+			Reason:   rej.Reason,
+			Code:     rej.Code,
+		})
 	}
 
 	acc := env.Msg.(ChannelProposalAccept) // this is safe because of predicate isResponse
@@ -442,7 +570,16 @@ func (c *Client) validVirtualChannelProposal(prop *VirtualChannelProposal, ourId
 		}
 	}
 
+	// Check that we can fund the virtual channel and pay the fee of our parent
+	// channel, which may differ from the proposer's fee.
+	fee, err := c.virtualChannelFee(parent, *prop.InitBals)
+	if err != nil {
+		return errors.WithMessage(err, "determining fee")
+	}
 	virtualBals := transformBalances(prop.InitBals.Balances, parentState.NumParts(), indexMap)
+	for a, f := range fee {
+		virtualBals[a][parent.Idx()] = new(big.Int).Add(virtualBals[a][parent.Idx()], f)
+	}
 	if err := parentState.Balances.AssertGreaterOrEqual(virtualBals); err != nil {
 		return errors.WithMessage(err, "insufficient funds")
 	}
@@ -492,6 +629,40 @@ func calcNonce(nonceShares []NonceShare) channel.Nonce {
 	return channel.NonceFromBytes(hasher.Sum(nil))
 }
 
+// ExpectedChannelID returns the ID of the channel that results from the given
+// proposal and accept message. The channel ID depends on the nonce shares of
+// both the proposer and the responder, so it is only known once the accept
+// message is. Both participants can use it to log and cross-check the channel
+// ID before funding.
+func (c *Client) ExpectedChannelID(prop ChannelProposal, acc ChannelProposalAccept) (channel.ID, error) {
+	if !prop.Matches(acc) {
+		return channel.ID{}, errors.Errorf("accept message of type %T does not match proposal", acc)
+	}
+	if sub, ok := prop.(*SubChannelProposal); ok && !c.channels.Has(sub.Parent) {
+		return channel.ID{}, errors.Errorf("unknown parent channel %x", sub.Parent)
+	}
+	return c.proposalParams(prop, acc).ID(), nil
+}
+
+// proposalParams returns the parameters of the channel that results from the
+// given proposal and accept message.
+func (c *Client) proposalParams(prop ChannelProposal, acc ChannelProposalAccept) *channel.Params {
+	propBase := prop.Base()
+	return channel.NewParamsUnsafe(
+		propBase.ChallengeDuration,
+		c.mpcppParts(prop, acc),
+		propBase.App,
+		calcNonce(nonceShares(propBase.NonceShare, acc.Base().NonceShare)),
+		prop.Type() == wire.LedgerChannelProposal,
+		isVirtualChannelProposal(prop),
+	)
+}
+
+func isVirtualChannelProposal(prop ChannelProposal) bool {
+	_, ok := prop.(*VirtualChannelProposal)
+	return ok
+}
+
 // completeCPP completes the channel proposal protocol and sets up a new channel
 // controller. The initial state with signatures is exchanged using the wallet
 // to unlock the account for our participant.
@@ -509,14 +680,7 @@ func (c *Client) completeCPP(
 	partIdx channel.Index,
 ) (*Channel, error) {
 	propBase := prop.Base()
-	params := channel.NewParamsUnsafe(
-		propBase.ChallengeDuration,
-		c.mpcppParts(prop, acc),
-		propBase.App,
-		calcNonce(nonceShares(propBase.NonceShare, acc.Base().NonceShare)),
-		prop.Type() == wire.LedgerChannelProposal,
-		prop.Type() == wire.VirtualChannelProposal,
-	)
+	params := c.proposalParams(prop, acc)
 
 	if c.channels.Has(params.ID()) {
 		return nil, errors.New("channel already exists")
@@ -638,7 +802,15 @@ func (c *Client) fundLedgerChannel(ctx context.Context, ch *Channel, agreement c
 			ch.machine.Idx(),
 			agreement,
 		)); channel.IsFundingTimeoutError(err) {
-		return errors.WithMessage(err, "waiting for peer funding")
+		ch.Log().Warnf("peers did not fund channel, refunding own deposits: %v", err)
+		// The funding context may already be expired, so the refund gets its
+		// own deadline.
+		refundCtx, cancel := c.timeoutCtx(c.cfg.RefundTimeout)
+		defer cancel()
+		if rerr := ch.refundUnfunded(refundCtx); rerr != nil {
+			return errors.WithMessagef(rerr, "refunding own deposits after funding timeout (%v)", err)
+		}
+		return errors.WithStack(FundingAbortedError{ChannelID: ch.ID(), Cause: err})
 	} else if err != nil { // other runtime error
 		ch.Log().Warnf("error while funding channel: %v", err)
 		return errors.WithMessage(err, "error while funding channel")
@@ -714,9 +886,19 @@ type cachedUpdate struct {
 
 // Error implements the error interface.
 func (e PeerRejectedError) Error() string {
-	return fmt.Sprintf("%s rejected by peer: %s", e.ItemType, e.Reason)
+	item := e.ItemType
+	if e.ChannelID != (channel.ID{}) {
+		item = fmt.Sprintf("%s (channel %x, version %d)", e.ItemType, e.ChannelID, e.Version)
+	}
+	if e.Code != ProposalRejectUnspecified {
+		return fmt.Sprintf("%s rejected by peer (%v): %s", item, e.Code, e.Reason)
+	}
+	if e.UpdateCode != UpdateRejectUnspecified {
+		return fmt.Sprintf("%s rejected by peer (%v): %s", item, e.UpdateCode, e.Reason)
+	}
+	return fmt.Sprintf("%s rejected by peer: %s", item, e.Reason)
 }
 
 func newPeerRejectedError(rejectedItemType, reason string) error {
-	return errors.WithStack(PeerRejectedError{rejectedItemType, reason})
+	return errors.WithStack(PeerRejectedError{ItemType: rejectedItemType, Reason: reason})
 }
@@ -15,11 +15,14 @@
 package client
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
 )
 
 func TestProposalResponder_Accept_Nil(t *testing.T) {
@@ -48,3 +51,20 @@ func TestPeerRejectedProposalError(t *testing.T) {
 		assert.Contains(t, err.Error(), reason)
 	})
 }
+
+func TestPeerRejectedError_Error(t *testing.T) {
+	id := channel.ID{0xab, 0xcd}
+	err := PeerRejectedError{
+		ItemType:   "channel update",
+		ChannelID:  id,
+		Version:    42,
+		Reason:     "some-random-reason",
+		UpdateCode: UpdateRejectBusy,
+	}
+	assert.Equal(t,
+		fmt.Sprintf("channel update (channel %x, version 42) rejected by peer (%v): some-random-reason", id, UpdateRejectBusy),
+		err.Error())
+
+	err = PeerRejectedError{ItemType: "channel proposal", Reason: "some-random-reason"}
+	assert.Equal(t, "channel proposal rejected by peer: some-random-reason", err.Error())
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+
+	"perun.network/go-perun/wire"
+)
+
+// tooManyProposalsReason is the rejection reason that is sent to a peer if an
+// incoming proposal exceeds the proposal limits.
+const tooManyProposalsReason = "too many pending proposals"
+
+// proposalLimiter tracks the number of incoming channel proposals that are
+// currently being handled, per peer and in total. A limit of zero means that
+// the respective number is not limited.
+type proposalLimiter struct {
+	mtx      sync.Mutex
+	perPeer  int
+	total    int
+	pending  map[wire.AddrKey]int
+	nPending int
+}
+
+func newProposalLimiter() *proposalLimiter {
+	return &proposalLimiter{pending: make(map[wire.AddrKey]int)}
+}
+
+// setLimits sets the maximum number of pending proposals per peer and in total.
+func (l *proposalLimiter) setLimits(perPeer, total int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.perPeer, l.total = perPeer, total
+}
+
+// acquire reserves a slot for a proposal from peer p. It returns false if any
+// of the limits would be exceeded. Every successful acquire must be followed
+// by a release.
+func (l *proposalLimiter) acquire(p wire.Address) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	key := wire.Key(p)
+	if (l.perPeer > 0 && l.pending[key] >= l.perPeer) ||
+		(l.total > 0 && l.nPending >= l.total) {
+		return false
+	}
+	l.pending[key]++
+	l.nPending++
+	return true
+}
+
+// release frees a slot that was reserved by acquire for peer p.
+func (l *proposalLimiter) release(p wire.Address) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	key := wire.Key(p)
+	if l.pending[key]--; l.pending[key] <= 0 {
+		delete(l.pending, key)
+	}
+	l.nPending--
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pkgtest "perun.network/go-perun/pkg/test"
+	wiretest "perun.network/go-perun/wire/test"
+)
+
+func TestProposalLimiter(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	alice, bob := wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)
+
+	t.Run("unlimited", func(t *testing.T) {
+		l := newProposalLimiter()
+		for i := 0; i < 100; i++ {
+			assert.True(t, l.acquire(alice))
+		}
+	})
+
+	t.Run("per_peer", func(t *testing.T) {
+		l := newProposalLimiter()
+		l.setLimits(2, 0)
+		assert.True(t, l.acquire(alice))
+		assert.True(t, l.acquire(alice))
+		assert.False(t, l.acquire(alice))
+		assert.True(t, l.acquire(bob), "limit should be per peer")
+		l.release(alice)
+		assert.True(t, l.acquire(alice), "released slot should be reusable")
+	})
+
+	t.Run("total", func(t *testing.T) {
+		l := newProposalLimiter()
+		l.setLimits(0, 2)
+		assert.True(t, l.acquire(alice))
+		assert.True(t, l.acquire(bob))
+		assert.False(t, l.acquire(bob))
+		assert.False(t, l.acquire(alice))
+		l.release(bob)
+		assert.True(t, l.acquire(alice))
+		assert.Len(t, l.pending, 1)
+	})
+}
@@ -17,6 +17,7 @@ package client
 import (
 	"hash"
 	"io"
+	"math/big"
 
 	"golang.org/x/crypto/sha3"
 
@@ -56,11 +57,21 @@ func init() {
 			var m ChannelProposalRej
 			return &m, m.Decode(r)
 		})
+	wire.RegisterDecoder(wire.ChannelProposalRejWithCode,
+		func(r io.Reader) (wire.Msg, error) {
+			var m ChannelProposalRej
+			return &m, m.decodeWithCode(r)
+		})
 	wire.RegisterDecoder(wire.VirtualChannelProposal,
 		func(r io.Reader) (wire.Msg, error) {
 			var m = VirtualChannelProposal{}
 			return &m, m.Decode(r)
 		})
+	wire.RegisterDecoder(wire.VirtualChannelProposalWithFee,
+		func(r io.Reader) (wire.Msg, error) {
+			var m = VirtualChannelProposal{}
+			return &m, m.decodeWithFee(r)
+		})
 	wire.RegisterDecoder(wire.VirtualChannelProposalAcc,
 		func(r io.Reader) (wire.Msg, error) {
 			var m = VirtualChannelProposalAcc{}
@@ -164,6 +175,16 @@ func makeBaseChannelProposal(
 			return BaseChannelProposal{}, errors.New("FundingAgreement and initial balances differ")
 		}
 	}
+	if opt.isFundingSponsor() {
+		if opt.isFundingAgreement() {
+			return BaseChannelProposal{}, errors.New("FundingSponsor and FundingAgreement are mutually exclusive")
+		}
+		sponsor := opt.fundingSponsor()
+		if int(sponsor) >= initBals.NumParts() {
+			return BaseChannelProposal{}, errors.Errorf("FundingSponsor index %d out of range", sponsor)
+		}
+		fundingAgreement = sponsoredAgreement(initBals.Balances, sponsor)
+	}
 
 	return BaseChannelProposal{
 		ChallengeDuration: challengeDuration,
@@ -175,6 +196,24 @@ func makeBaseChannelProposal(
 	}, nil
 }
 
+// sponsoredAgreement returns a funding agreement in which the sponsor deposits
+// the sum of the given balances for every asset.
+func sponsoredAgreement(bals channel.Balances, sponsor channel.Index) channel.Balances {
+	sums := bals.Sum()
+	agreement := make(channel.Balances, len(bals))
+	for a, assetBals := range bals {
+		agreement[a] = make([]channel.Bal, len(assetBals))
+		for i := range assetBals {
+			if channel.Index(i) == sponsor {
+				agreement[a][i] = sums[a]
+			} else {
+				agreement[a][i] = new(big.Int)
+			}
+		}
+	}
+	return agreement
+}
+
 // Base returns the channel proposal's common values.
 func (p *BaseChannelProposal) Base() *BaseChannelProposal {
 	return p
@@ -377,7 +416,7 @@ func NewSubChannelProposal(
 	initBals *channel.Allocation,
 	opts ...ProposalOpts,
 ) (prop *SubChannelProposal, err error) {
-	if union(opts...).isFundingAgreement() {
+	if opt := union(opts...); opt.isFundingAgreement() || opt.isFundingSponsor() {
 		return nil, errors.New("Sub-Channels currently do not support funding agreements")
 	}
 	prop = &SubChannelProposal{Parent: parent}
@@ -543,42 +582,71 @@ func (acc *SubChannelProposalAcc) Decode(r io.Reader) error {
 }
 
 // ChannelProposalRej is used to reject a ChannelProposalReq.
-// An optional reason for the rejection can be set.
+// An optional reason code and reason for the rejection can be set.
+//
+// Rejections without a reason code are sent as ChannelProposalRej, which peers
+// that do not know reason codes can decode. Rejections with a reason code are
+// sent as ChannelProposalRejWithCode.
 //
 // The message is one of two possible responses in the
 // Multi-Party Channel Proposal Protocol (MPCPP).
 type ChannelProposalRej struct {
-	ProposalID ProposalID // The channel proposal to reject.
-	Reason     string     // The rejection reason.
+	ProposalID ProposalID         // The channel proposal to reject.
+	Code       ProposalRejectCode // The rejection reason code.
+	Reason     string             // The rejection reason.
 }
 
-// Type returns wire.ChannelProposalRej.
-func (ChannelProposalRej) Type() wire.Type {
-	return wire.ChannelProposalRej
+// Type returns wire.ChannelProposalRej if the rejection has no reason code and
+// wire.ChannelProposalRejWithCode otherwise.
+func (rej ChannelProposalRej) Type() wire.Type {
+	if rej.Code == ProposalRejectUnspecified {
+		return wire.ChannelProposalRej
+	}
+	return wire.ChannelProposalRejWithCode
 }
 
 // Encode encodes a ChannelProposalRej into an io.Writer.
 func (rej ChannelProposalRej) Encode(w io.Writer) error {
-	return perunio.Encode(w, rej.ProposalID, rej.Reason)
+	if rej.Code == ProposalRejectUnspecified {
+		return perunio.Encode(w, rej.ProposalID, rej.Reason)
+	}
+	return perunio.Encode(w, rej.ProposalID, rej.Reason, uint8(rej.Code))
 }
 
 // Decode decodes a ChannelProposalRej from an io.Reader.
-func (rej *ChannelProposalRej) Decode(r io.Reader) error {
+func (rej *ChannelProposalRej) Decode(r io.Reader) (err error) {
+	rej.Code = ProposalRejectUnspecified
 	return perunio.Decode(r, &rej.ProposalID, &rej.Reason)
 }
 
+// decodeWithCode decodes a rejection that was sent as
+// ChannelProposalRejWithCode.
+func (rej *ChannelProposalRej) decodeWithCode(r io.Reader) (err error) {
+	var code uint8
+	err = perunio.Decode(r, &rej.ProposalID, &rej.Reason, &code)
+	rej.Code = ProposalRejectCode(code)
+	return err
+}
+
 /*
 Virtual channels
 */
 
 type (
 	// VirtualChannelProposal is a channel proposal for virtual channels.
+	//
+	// Proposals without a fee are sent as VirtualChannelProposal, which peers
+	// that do not know fees can decode. Proposals with a fee are sent as
+	// VirtualChannelProposalWithFee.
 	VirtualChannelProposal struct {
 		BaseChannelProposal
 		Proposer  wallet.Address    // Proposer's address in the channel.
 		Peers     []wire.Address    // Participants' wire addresses.
 		Parents   []channel.ID      // Parent channels for each participant.
 		IndexMaps [][]channel.Index // Index mapping for each participant in relation to the root channel.
+		// Fee is the fee per asset that the proposer pays to the intermediary
+		// of its parent channel, see VirtualFeeStrategy. Empty means no fee.
+		Fee []channel.Bal
 	}
 
 	// VirtualChannelProposalAcc is the accept message type corresponding to
@@ -613,20 +681,27 @@ func NewVirtualChannelProposal(
 		Peers:               peers,
 		Parents:             parents,
 		IndexMaps:           indexMaps,
+		Fee:                 union(opts...).virtualChannelFee(),
 	}
 	return
 }
 
 // Encode encodes the proposal into an io.Writer.
 func (p VirtualChannelProposal) Encode(w io.Writer) error {
-	return perunio.Encode(
+	if err := perunio.Encode(
 		w,
 		p.BaseChannelProposal,
 		p.Proposer,
 		wire.AddressesWithLen(p.Peers),
 		channelIDsWithLen(p.Parents),
 		indexMapsWithLen(p.IndexMaps),
-	)
+	); err != nil {
+		return err
+	}
+	if isZeroFee(p.Fee) {
+		return nil
+	}
+	return perunio.Encode(w, balsWithLen(p.Fee))
 }
 
 // Decode decodes a proposal from an io.Reader.
@@ -641,9 +716,22 @@ func (p *VirtualChannelProposal) Decode(r io.Reader) error {
 	)
 }
 
-// Type returns the message type.
-func (VirtualChannelProposal) Type() wire.Type {
-	return wire.VirtualChannelProposal
+// decodeWithFee decodes a proposal that was sent as
+// VirtualChannelProposalWithFee.
+func (p *VirtualChannelProposal) decodeWithFee(r io.Reader) error {
+	if err := p.Decode(r); err != nil {
+		return err
+	}
+	return perunio.Decode(r, (*balsWithLen)(&p.Fee))
+}
+
+// Type returns the message type: VirtualChannelProposal if the proposal has
+// no fee and VirtualChannelProposalWithFee otherwise.
+func (p VirtualChannelProposal) Type() wire.Type {
+	if isZeroFee(p.Fee) {
+		return wire.VirtualChannelProposal
+	}
+	return wire.VirtualChannelProposalWithFee
 }
 
 // Accept constructs an accept message that belongs to a proposal message.
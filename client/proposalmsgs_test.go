@@ -15,15 +15,18 @@
 package client_test
 
 import (
+	"bytes"
 	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"perun.network/go-perun/channel"
 	"perun.network/go-perun/channel/test"
 	"perun.network/go-perun/client"
 	clienttest "perun.network/go-perun/client/test"
+	perunio "perun.network/go-perun/pkg/io"
 	pkgtest "perun.network/go-perun/pkg/test"
 	wallettest "perun.network/go-perun/wallet/test"
 	"perun.network/go-perun/wire"
@@ -47,6 +50,32 @@ func TestNewLedgerChannelProposal(t *testing.T) {
 	agreement = test.NewRandomBalances(rng, test.WithNumAssets(len(base.InitBals.Assets)))
 	_, err = client.NewLedgerChannelProposal(base.ChallengeDuration, base.Participant, base.InitBals, base.Peers, client.WithFundingAgreement(agreement))
 	assert.EqualError(t, err, "FundingAgreement and initial balances differ")
+
+	// The FundingSponsor deposits everything.
+	sponsor := channel.Index(rng.Intn(base.InitBals.NumParts()))
+	prop, err = client.NewLedgerChannelProposal(base.ChallengeDuration, base.Participant, base.InitBals, base.Peers, client.WithFundingSponsor(sponsor))
+	require.NoError(t, err)
+	sums := base.InitBals.Balances.Sum()
+	for a, bals := range prop.FundingAgreement {
+		for i, bal := range bals {
+			if channel.Index(i) == sponsor {
+				assert.Zero(t, bal.Cmp(sums[a]))
+			} else {
+				assert.Zero(t, bal.Sign())
+			}
+		}
+	}
+	assert.Equal(t, sums, base.InitBals.Balances.Sum(), "initial balances must not be modified")
+
+	// FundingSponsor out of range.
+	_, err = client.NewLedgerChannelProposal(base.ChallengeDuration, base.Participant, base.InitBals, base.Peers,
+		client.WithFundingSponsor(channel.Index(base.InitBals.NumParts())))
+	assert.Error(t, err)
+
+	// FundingSponsor and FundingAgreement cannot be combined.
+	_, err = client.NewLedgerChannelProposal(base.ChallengeDuration, base.Participant, base.InitBals, base.Peers,
+		client.WithFundingSponsor(sponsor), client.WithFundingAgreement(base.InitBals.Balances))
+	assert.EqualError(t, err, "FundingSponsor and FundingAgreement are mutually exclusive")
 }
 
 func TestChannelProposalReqSerialization(t *testing.T) {
@@ -67,7 +96,11 @@ func TestChannelProposalReqSerialization(t *testing.T) {
 			m, err = clienttest.NewRandomSubChannelProposal(rng, client.WithNonceFrom(rng), app)
 			require.NoError(t, err)
 		case 2:
-			m, err = clienttest.NewRandomVirtualChannelProposal(rng, client.WithNonceFrom(rng), app)
+			var fee client.ProposalOpts
+			if i&2 == 0 {
+				fee = client.WithVirtualChannelFee(test.NewRandomBals(rng, 1+rng.Intn(3)))
+			}
+			m, err = clienttest.NewRandomVirtualChannelProposal(rng, client.WithNonceFrom(rng), app, fee)
 			require.NoError(t, err)
 		}
 		wire.TestMsg(t, m)
@@ -194,12 +227,31 @@ func TestChannelProposalRejSerialization(t *testing.T) {
 	for i := 0; i < 16; i++ {
 		m := &client.ChannelProposalRej{
 			ProposalID: newRandomProposalID(rng),
+			Code:       client.ProposalRejectCode(rng.Intn(256)),
 			Reason:     newRandomString(rng, 16, 16),
 		}
 		wire.TestMsg(t, m)
 	}
 }
 
+func TestChannelProposalRejCompatibility(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	m := &client.ChannelProposalRej{
+		ProposalID: newRandomProposalID(rng),
+		Reason:     newRandomString(rng, 16, 16),
+	}
+
+	// Rejections without a code are encoded as before reason codes existed.
+	var buf bytes.Buffer
+	require.NoError(t, wire.Encode(m, &buf))
+	var legacy bytes.Buffer
+	require.NoError(t, perunio.Encode(&legacy, byte(wire.ChannelProposalRej), m.ProposalID, m.Reason))
+	assert.Equal(t, legacy.Bytes(), buf.Bytes())
+
+	m.Code = client.ProposalRejectPolicyDenied
+	assert.Equal(t, wire.ChannelProposalRejWithCode, m.Type())
+}
+
 func TestSubChannelProposalSerialization(t *testing.T) {
 	rng := pkgtest.Prng(t)
 	const repeatRandomizedTest = 16
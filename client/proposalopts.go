@@ -27,7 +27,7 @@ import (
 // NoData is set, and a random nonce share is generated.
 type ProposalOpts map[string]interface{}
 
-var optNames = struct{ nonce, app, appData, fundingAgreement string }{nonce: "nonce", app: "app", appData: "appData", fundingAgreement: "fundingAgreement"}
+var optNames = struct{ nonce, app, appData, fundingAgreement, fundingSponsor, virtualChannelFee string }{nonce: "nonce", app: "app", appData: "appData", fundingAgreement: "fundingAgreement", fundingSponsor: "fundingSponsor", virtualChannelFee: "virtualChannelFee"}
 
 // App returns the option's configured app.
 func (o ProposalOpts) App() channel.App {
@@ -66,6 +66,30 @@ func (o ProposalOpts) fundingAgreement() channel.Balances {
 	return a.(channel.Balances)
 }
 
+func (o ProposalOpts) isFundingSponsor() bool {
+	_, ok := o[optNames.fundingSponsor]
+	return ok
+}
+
+// fundingSponsor returns the sponsor that was set by `WithFundingSponsor` and
+// panics otherwise.
+func (o ProposalOpts) fundingSponsor() channel.Index {
+	s, ok := o[optNames.fundingSponsor]
+	if !ok {
+		panic("Option FundingSponsor not set")
+	}
+	return s.(channel.Index)
+}
+
+// virtualChannelFee returns the fee that was set by `WithVirtualChannelFee`
+// or nil.
+func (o ProposalOpts) virtualChannelFee() []channel.Bal {
+	if v := o[optNames.virtualChannelFee]; v != nil {
+		return v.([]channel.Bal)
+	}
+	return nil
+}
+
 // nonce returns the option's configured nonce share, or a random nonce share.
 func (o ProposalOpts) nonce() NonceShare {
 	n, ok := o[optNames.nonce]
@@ -101,6 +125,23 @@ func WithFundingAgreement(alloc channel.Balances) ProposalOpts {
 	return ProposalOpts{optNames.fundingAgreement: alloc}
 }
 
+// WithFundingSponsor configures a funding agreement in which the participant
+// with the given index deposits the complete initial balances of all assets,
+// e.g., to onboard a user who does not hold any funds yet. The other
+// participants do not deposit anything. It cannot be combined with
+// WithFundingAgreement.
+func WithFundingSponsor(sponsor channel.Index) ProposalOpts {
+	return ProposalOpts{optNames.fundingSponsor: sponsor}
+}
+
+// WithVirtualChannelFee configures the fee per asset that the proposer of a
+// virtual channel pays to the intermediary of its parent channel. The
+// intermediary checks that it equals the fee of its VirtualFeeStrategy. It only
+// applies to virtual channel proposals.
+func WithVirtualChannelFee(fee []channel.Bal) ProposalOpts {
+	return ProposalOpts{optNames.virtualChannelFee: fee}
+}
+
 // WithNonce configures a fixed nonce share.
 func WithNonce(share NonceShare) ProposalOpts {
 	return ProposalOpts{optNames.nonce: share}
@@ -120,6 +161,12 @@ func WithRandomNonce() ProposalOpts {
 	return WithNonceFrom(rand.Reader)
 }
 
+// RandomNonce creates a nonce share from the client's randomness source, as
+// configured by Config.Rand.
+func (c *Client) RandomNonce() ProposalOpts {
+	return WithNonceFrom(c.cfg.Rand)
+}
+
 // WithApp configures an app and initial data.
 func WithApp(app channel.App, initData channel.Data) ProposalOpts {
 	return ProposalOpts{optNames.app: app, optNames.appData: initData}
@@ -0,0 +1,60 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "fmt"
+
+// ProposalRejectCode states why a channel proposal was rejected. It is sent
+// to the proposer along with the free-text rejection reason, so that the
+// proposer can react to a rejection programmatically, e.g., by adjusting the
+// proposal and retrying.
+type ProposalRejectCode uint8
+
+// The ProposalRejectCodes that are known to the client. Further codes might be
+// received from peers that use a newer protocol version.
+const (
+	// ProposalRejectUnspecified is used if no reason code was given.
+	ProposalRejectUnspecified ProposalRejectCode = iota
+	// ProposalRejectUnsupportedAsset indicates that the peer does not support
+	// an asset of the proposed allocation.
+	ProposalRejectUnsupportedAsset
+	// ProposalRejectInsufficientLiquidity indicates that the peer cannot or
+	// does not want to fund its part of the proposed allocation.
+	ProposalRejectInsufficientLiquidity
+	// ProposalRejectChallengeTooShort indicates that the proposed challenge
+	// duration is shorter than what the peer accepts.
+	ProposalRejectChallengeTooShort
+	// ProposalRejectPolicyDenied indicates that the proposal violates a policy
+	// of the peer, e.g., a limit on the number of pending proposals.
+	ProposalRejectPolicyDenied
+)
+
+// String returns a human-readable representation of the code.
+func (c ProposalRejectCode) String() string {
+	switch c {
+	case ProposalRejectUnspecified:
+		return "unspecified"
+	case ProposalRejectUnsupportedAsset:
+		return "unsupported asset"
+	case ProposalRejectInsufficientLiquidity:
+		return "insufficient liquidity"
+	case ProposalRejectChallengeTooShort:
+		return "challenge duration too short"
+	case ProposalRejectPolicyDenied:
+		return "policy denied"
+	default:
+		return fmt.Sprintf("unknown code %d", uint8(c))
+	}
+}
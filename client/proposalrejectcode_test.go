@@ -0,0 +1,54 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestProposalResponder_RejectWithCode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	alice, bob := clients[0], clients[1]
+
+	const reason = "only accepting ETH"
+	errs := make(chan error, 1)
+	var proposalHandlerBob client.ProposalHandlerFunc = func(_ client.ChannelProposal, pr *client.ProposalResponder) {
+		errs <- pr.RejectWithCode(ctx, client.ProposalRejectUnsupportedAsset, reason)
+	}
+	var updateHandlerBob client.UpdateHandlerFunc = func(*channel.State, client.ChannelUpdate, *client.UpdateResponder) {}
+	go bob.Handle(proposalHandlerBob, updateHandlerBob)
+
+	_, err := alice.ProposeChannel(ctx, newLedgerChannelProposal(t, rng, alice, bob))
+	require.NoError(t, <-errs)
+
+	var rejErr client.PeerRejectedError
+	require.True(t, errors.As(err, &rejErr))
+	assert.Equal(t, client.ProposalRejectUnsupportedAsset, rejErr.Code)
+	assert.Equal(t, reason, rejErr.Reason)
+	assert.Contains(t, err.Error(), client.ProposalRejectUnsupportedAsset.String())
+}
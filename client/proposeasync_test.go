@@ -0,0 +1,99 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+// gatedFunder is a funder that only starts funding once released.
+type gatedFunder struct {
+	channel.Funder
+	release <-chan struct{}
+}
+
+func (f *gatedFunder) Fund(ctx context.Context, req channel.FundingReq) error {
+	select {
+	case <-f.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return f.Funder.Fund(ctx, req)
+}
+
+func TestClient_ProposeChannelAsync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	release := make(chan struct{})
+	setups := NewSetups(rng, []string{"Alice", "Bob"})
+	for i := range setups {
+		setups[i].Funder = &gatedFunder{Funder: setups[i].Funder, release: release}
+	}
+	clients := newClientsFromSetups(rng, setups, t)
+	alice, bob := clients[0], clients[1]
+
+	newChannels := make(chan *client.Channel, 1)
+	alice.OnNewChannel(func(ch *client.Channel) { newChannels <- ch })
+
+	type accepted struct {
+		ch     *client.Channel
+		funded <-chan error
+	}
+	acceptedBob := make(chan accepted, 1)
+	var proposalHandlerBob client.ProposalHandlerFunc = func(cp client.ChannelProposal, pr *client.ProposalResponder) {
+		ch, funded, err := pr.AcceptAsync(ctx, cp.(*client.LedgerChannelProposal).Accept(bob.Identity.Address(), client.WithRandomNonce()))
+		assert.NoError(t, err)
+		acceptedBob <- accepted{ch, funded}
+	}
+	var updateHandlerBob client.UpdateHandlerFunc = func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+		assert.NoError(t, ur.Accept(ctx))
+	}
+	go bob.Handle(proposalHandlerBob, updateHandlerBob)
+
+	res, fundedAlice, err := alice.ProposeChannelAsync(ctx, newLedgerChannelProposal(t, rng, alice, bob))
+	require.NoError(t, err)
+	bobRes := <-acceptedBob
+	require.NotNil(t, res.Channel)
+	require.NotNil(t, bobRes.ch)
+	assert.Equal(t, res.ID, bobRes.ch.ID())
+
+	// The channel is agreed upon but not funded yet.
+	assert.Equal(t, channel.Funding, res.Channel.Phase())
+	select {
+	case err := <-fundedAlice:
+		t.Fatalf("funding completed before funder was released: %v", err)
+	case <-newChannels:
+		t.Fatal("OnNewChannel called before funding completed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-fundedAlice)
+	require.NoError(t, <-bobRes.funded)
+	assert.Same(t, res.Channel, <-newChannels)
+	assert.Equal(t, channel.Acting, res.Channel.Phase())
+	assert.NoError(t, transfer(ctx, res.Channel, 1))
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestChannel_SetBalanceReserve(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	chAlice, chBob := openAcceptingChannel(ctx, t, rng, clients[0], clients[1])
+
+	assert.Error(t, chBob.SetBalanceReserve([]channel.Bal{big.NewInt(1), big.NewInt(1)}),
+		"reserve must have an entry per asset")
+	require.NoError(t, chBob.SetBalanceReserve([]channel.Bal{big.NewInt(8)}))
+
+	// Incoming updates that drop Bob's balance below the reserve are rejected.
+	require.NoError(t, transfer(ctx, chAlice, -2))
+	err := transfer(ctx, chAlice, -1)
+	var rejErr client.PeerRejectedError
+	require.True(t, errors.As(err, &rejErr), "expected PeerRejectedError, got %v", err)
+	assert.Contains(t, rejErr.Reason, "below reserve")
+
+	// Outgoing updates are checked locally.
+	err = transfer(ctx, chBob, 1)
+	assert.True(t, errors.As(err, new(client.ReserveViolatedError)), "expected ReserveViolatedError, got %v", err)
+
+	// Increasing the balance is always fine.
+	require.NoError(t, transfer(ctx, chAlice, 1))
+	assert.Equal(t, uint64(2), chBob.State().Version)
+}
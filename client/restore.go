@@ -26,6 +26,26 @@ import (
 
 type channelFromSourceSig = func(*Client, *persistence.Channel, *Channel, ...wire.Address) (*Channel, error)
 
+type (
+	// RestoreOption configures the behavior of Client.Restore.
+	RestoreOption func(*restoreOpts)
+
+	restoreOpts struct {
+		watchHandler AdjudicatorEventHandler
+		watchOpts    []WatchOption
+	}
+)
+
+// RestoreWithWatcher makes Client.Restore start the watcher with handler h
+// and the given options on every restored channel. Errors returned by the
+// watchers are logged.
+func RestoreWithWatcher(h AdjudicatorEventHandler, opts ...WatchOption) RestoreOption {
+	return func(o *restoreOpts) {
+		o.watchHandler = h
+		o.watchOpts = opts
+	}
+}
+
 // clientChannelFromSource is the production behaviour of reconstructChannel.
 // During testing, it is replaced by a simpler function that needs much less
 // setup code.
@@ -66,10 +86,10 @@ func (c *Client) reconstructChannel(
 	return ch
 }
 
-func (c *Client) restorePeerChannels(ctx context.Context, p wire.Address) (err error) {
+func (c *Client) restorePeerChannels(ctx context.Context, p wire.Address) (_ []*Channel, err error) {
 	it, err := c.pr.RestorePeer(p)
 	if err != nil {
-		return errors.WithMessagef(err, "restoring channels for peer: %v", err)
+		return nil, errors.WithMessagef(err, "restoring channels for peer: %v", err)
 	}
 	defer func() {
 		if cerr := it.Close(); cerr != nil {
@@ -87,16 +107,17 @@ func (c *Client) restorePeerChannels(ctx context.Context, p wire.Address) (err e
 	}
 
 	if err := it.Close(); err != nil {
-		return err
+		return nil, err
 	}
 
-	c.restoreChannelCollection(db, clientChannelFromSource)
-	return nil
+	return c.restoreChannelCollection(db, clientChannelFromSource), nil
 }
 
+// restoreChannelCollection restores the channels in db and returns the ones
+// that were newly added to the client.
 func (c *Client) restoreChannelCollection(
 	db map[channel.ID]*persistence.Channel,
-	channelFromSource channelFromSourceSig) {
+	channelFromSource channelFromSourceSig) (restored []*Channel) {
 	chs := make(map[channel.ID]*Channel)
 	for _, pch := range db {
 		ch := c.reconstructChannel(channelFromSource, pch, db, chs)
@@ -116,7 +137,17 @@ func (c *Client) restoreChannelCollection(
 			// If the channel already existed, close this one.
 			// nolint:errcheck,gosec
 			ch.Close()
+			continue
 		}
+		restored = append(restored, ch)
 		log.Info("Channel restored.")
 	}
+	return restored
+}
+
+// watchRestored runs the watcher on a restored channel and logs its error.
+func (c *Client) watchRestored(ch *Channel, h AdjudicatorEventHandler, opts []WatchOption) {
+	if err := ch.Watch(h, opts...); err != nil {
+		ch.Log().Errorf("Watcher of restored channel returned: %v", err)
+	}
 }
@@ -97,14 +97,15 @@ func TestRestoreChannelCollection(t *testing.T) {
 	})
 
 	// Restore all channels into the client and check the published channels.
-	c.restoreChannelCollection(db, patchChFromSource)
+	restored := c.restoreChannelCollection(db, patchChFromSource)
 	require.Equal(t, len(witnessedChans), len(db), "channel count mismatch")
+	require.Len(t, restored, len(db))
 
 	// Duplicates should be ignored and there should be no missing channels.
 	c.OnNewChannel(func(*Channel) {
 		t.Fatal("must not add duplicate or new channels")
 	})
-	c.restoreChannelCollection(db, patchChFromSource)
+	require.Empty(t, c.restoreChannelCollection(db, patchChFromSource))
 }
 
 // mkRndChan creates a single random channel.
@@ -0,0 +1,71 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"math/big"
+	"testing"
+
+	chtest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/client"
+	ctest "perun.network/go-perun/client/test"
+	"perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wire"
+)
+
+func TestScenarios(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []ctest.Action
+	}{
+		{"transfers", []ctest.Action{
+			ctest.Transfer(0, 5),
+			ctest.Transfer(1, 3),
+			ctest.Transfer(0, 7),
+		}},
+		{"dropped transfer", []ctest.Action{
+			ctest.Transfer(0, 5),
+			ctest.DropTransfer(1, 20),
+			ctest.Transfer(1, 3),
+		}},
+		{"peer goes offline", []ctest.Action{
+			ctest.Transfer(1, 10),
+			ctest.GoOffline(0),
+		}},
+		{"stale registration", []ctest.Action{
+			ctest.Transfer(0, 5),
+			ctest.Transfer(1, 30),
+			ctest.RegisterStale(1),
+		}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rng := test.Prng(t)
+			setups := NewSetups(rng, []string{"Alice", "Bob"})
+			s := &ctest.Scenario{
+				BaseExecConfig: ctest.MakeBaseExecConfig(
+					[2]wire.Address{setups[0].Identity.Address(), setups[1].Identity.Address()},
+					chtest.NewRandomAsset(rng),
+					[2]*big.Int{big.NewInt(100), big.NewInt(100)},
+					client.WithoutApp(),
+				),
+				Actions: tt.actions,
+			}
+			ctest.ExecuteScenario(t, [2]ctest.RoleSetup{setups[0], setups[1]}, s)
+		})
+	}
+}
@@ -16,6 +16,7 @@ package client
 
 import (
 	"io"
+	"math/big"
 
 	"github.com/pkg/errors"
 	"perun.network/go-perun/channel"
@@ -27,6 +28,7 @@ type (
 	channelIDsWithLen []channel.ID
 	indexMapWithLen   []channel.Index
 	indexMapsWithLen  [][]channel.Index
+	balsWithLen       []channel.Bal
 )
 
 // Encode encodes the object to the writer.
@@ -130,3 +132,41 @@ func (a *indexMapWithLen) Decode(r io.Reader) (err error) {
 	}
 	return
 }
+
+// Encode encodes the object to the writer.
+func (a balsWithLen) Encode(w io.Writer) (err error) {
+	err = perunio.Encode(w, sliceLen(len(a)))
+	if err != nil {
+		return
+	}
+
+	for _, b := range a {
+		err = perunio.Encode(w, b)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Decode decodes the object from the reader.
+func (a *balsWithLen) Decode(r io.Reader) (err error) {
+	var l sliceLen
+	if err = perunio.Decode(r, &l); err != nil {
+		return errors.WithMessage(err, "decoding length")
+	}
+	if l > channel.MaxNumAssets {
+		return errors.Errorf("expected maximum number of assets %d, got %d", channel.MaxNumAssets, l)
+	}
+
+	*a = make(balsWithLen, l)
+	for i := range *a {
+		b := new(big.Int)
+		err = perunio.Decode(r, &b)
+		if err != nil {
+			return errors.WithMessagef(err, "decoding item %d", i)
+		}
+		(*a)[i] = b
+	}
+	return
+}
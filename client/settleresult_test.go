@@ -0,0 +1,87 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+	wallettest "perun.network/go-perun/wallet/test"
+)
+
+func TestChannel_SettleWithResult(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	chAlice, chBob := openAcceptingChannel(ctx, t, rng, clients[0], clients[1])
+	require.NoError(t, transfer(ctx, chAlice, 3))
+	require.NoError(t, chAlice.UpdateBy(ctx, func(s *channel.State) error {
+		s.IsFinal = true
+		return nil
+	}))
+
+	asset, alice := chAlice.State().Assets[0], chAlice.Params().Parts[0]
+	before := clients[0].Backend.GetBalance(alice, asset)
+	res, err := chAlice.SettleWithResult(ctx, false)
+	require.NoError(t, err)
+	expectedOutcome := channel.Balances{{big.NewInt(7), big.NewInt(13)}}
+	assert.NoError(t, expectedOutcome.AssertEqual(res.Outcome))
+	assert.Equal(t, []channel.Bal{big.NewInt(7)}, res.Withdrawn)
+	withdrawn := new(big.Int).Sub(clients[0].Backend.GetBalance(alice, asset), before)
+	assert.Zero(t, withdrawn.Cmp(res.Withdrawn[0]), "result should match the on-chain withdrawal")
+
+	res, err = chBob.SettleWithResult(ctx, true)
+	require.NoError(t, err)
+	assert.NoError(t, expectedOutcome.AssertEqual(res.Outcome))
+	assert.Equal(t, []channel.Bal{big.NewInt(13)}, res.Withdrawn)
+}
+
+func TestChannel_SettleWithReceiver(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	chAlice, chBob := openAcceptingChannel(ctx, t, rng, clients[0], clients[1])
+	require.NoError(t, transfer(ctx, chAlice, 3))
+	require.NoError(t, chAlice.UpdateBy(ctx, func(s *channel.State) error {
+		s.IsFinal = true
+		return nil
+	}))
+
+	backend := clients[0].Backend
+	asset, parts := chAlice.State().Assets[0], chAlice.Params().Parts
+	aliceBefore := backend.GetBalance(parts[0], asset)
+	bobBefore := backend.GetBalance(parts[1], asset)
+	receiver := wallettest.NewRandomAddress(rng)
+
+	res, err := chAlice.SettleWithResult(ctx, false, client.WithReceiver(receiver))
+	require.NoError(t, err)
+	require.NoError(t, chBob.Settle(ctx, true))
+
+	assert.Zero(t, backend.GetBalance(receiver, asset).Cmp(res.Withdrawn[0]), "receiver should get Alice's funds")
+	assert.Zero(t, backend.GetBalance(parts[0], asset).Cmp(aliceBefore), "Alice should get nothing")
+	bobWithdrawn := new(big.Int).Sub(backend.GetBalance(parts[1], asset), bobBefore)
+	assert.Zero(t, bobWithdrawn.Cmp(big.NewInt(13)), "Bob should get his funds")
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestChannel_Snapshot(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	chAlice, _ := openAcceptingChannel(ctx, t, rng, clients[0], clients[1])
+	require.NoError(t, transfer(ctx, chAlice, 1))
+
+	current, staging, phase := chAlice.Snapshot()
+	assert.Equal(t, channel.Acting, phase)
+	assert.Nil(t, staging, "no staged update after the update completed")
+	require.NoError(t, chAlice.State().Equal(current))
+	assert.NotSame(t, chAlice.State(), current, "should return a clone")
+
+	current.Version++
+	assert.NotEqual(t, current.Version, chAlice.State().Version, "modifying the snapshot should not modify the channel")
+}
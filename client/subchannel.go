@@ -20,6 +20,7 @@ import (
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wire"
 )
 
 // IsLedgerChannel returns whether the channel is a ledger channel.
@@ -33,18 +34,36 @@ func (c *Channel) IsSubChannel() bool {
 }
 
 func (c *Channel) equalParticipants(_c *Channel) bool {
-	a, b := c.Peers(), _c.Peers()
-	if len(a) != len(b) {
-		return false
-	}
+	return wire.Addresses(c.Peers()).Equal(_c.Peers())
+}
 
-	for i, _a := range a {
-		if !_a.Equals(b[i]) {
-			return false
-		}
+// CollapseSubChannel withdraws the final outcome of the sub-channel with the
+// given ID into the balances of c, without settling c itself. No on-chain
+// interaction is required. Both participants must call it: the sub-channel
+// proposer updates c, while the proposee awaits and accepts that update.
+//
+// It is an alias for calling Settle(ctx, false) on the sub-channel that
+// looks the sub-channel up by its ID and checks beforehand that it is a
+// final sub-channel of c without locked funds. Afterwards, the sub-channel
+// is in phase Withdrawn.
+func (c *Channel) CollapseSubChannel(ctx context.Context, subID channel.ID) error {
+	sub, err := c.client.Channel(subID)
+	if err != nil {
+		return errors.WithMessagef(err, "looking up sub-channel %x", subID)
 	}
-
-	return true
+	if sub.Parent() != c || !sub.IsSubChannel() {
+		return errors.Errorf("channel %x is not a sub-channel of %x", subID, c.ID())
+	}
+	if sub.Phase() == channel.Withdrawn {
+		return errors.New("sub-channel already withdrawn")
+	}
+	if !sub.State().IsFinal {
+		return errors.New("sub-channel not final")
+	}
+	if sub.hasLockedFunds() {
+		return errors.New("sub-channel has locked funds")
+	}
+	return sub.Settle(ctx, false)
 }
 
 func (c *Channel) fundSubChannel(ctx context.Context, id channel.ID, alloc *channel.Allocation) error {
@@ -27,6 +27,14 @@ import (
 )
 
 func TestHappySusieTim(t *testing.T) {
+	testHappySusieTim(t, false)
+}
+
+func TestHappySusieTim_Collapse(t *testing.T) {
+	testHappySusieTim(t, true)
+}
+
+func testHappySusieTim(t *testing.T, collapse bool) {
 	rng := test.Prng(t)
 
 	setups := NewSetups(rng, []string{"Susie", "Tim"})
@@ -58,6 +66,7 @@ func TestHappySusieTim(t *testing.T) {
 		),
 		big.NewInt(1),
 	)
+	cfg.CollapseSubSubChannels = collapse
 
 	ctest.ExecuteTwoPartyTest(t, roles, cfg)
 }
@@ -0,0 +1,45 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	chtest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestChannel_CollapseSubChannel_Invalid(t *testing.T) {
+	rng := test.Prng(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	alice, bob := clients[0], clients[1]
+	chAlice, _ := openAcceptingChannel(ctx, t, rng, alice, bob)
+
+	t.Run("unknown", func(t *testing.T) {
+		err := chAlice.CollapseSubChannel(ctx, chtest.NewRandomChannelID(rng))
+		assert.Error(t, err)
+	})
+
+	t.Run("not a sub-channel", func(t *testing.T) {
+		err := chAlice.CollapseSubChannel(ctx, chAlice.ID())
+		assert.Error(t, err)
+	})
+}
@@ -16,21 +16,21 @@ package client
 
 import (
 	"context"
-	"time"
+	"fmt"
 
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/channel/persistence"
+	pcontext "perun.network/go-perun/pkg/context"
 	"perun.network/go-perun/wire"
 )
 
-var syncReplyTimeout = 10 * time.Second
-
 // handleSyncMsg is the passive incoming sync message handler. If the channel
 // exists, it just sends the current channel data to the requester. If the
 // own channel is in the Signing phase, the ongoing update is discarded so that
-// the channel is reverted to the Acting phase.
+// the channel is reverted to the Acting phase. If the requester sent a newer
+// state that is signed by all participants, it becomes the current state.
 func (c *Client) handleSyncMsg(peer wire.Address, msg *msgChannelSync) {
 	log := c.logChan(msg.ID()).WithField("peer", peer)
 	ch, ok := c.channels.Get(msg.ID())
@@ -41,15 +41,17 @@ func (c *Client) handleSyncMsg(peer wire.Address, msg *msgChannelSync) {
 
 	// TODO: cancel ongoing protocol, like Update
 
-	ctx, cancel := context.WithTimeout(c.Ctx(), syncReplyTimeout)
+	ctx, cancel := c.timeoutCtx(c.cfg.SyncReplyTimeout)
 	defer cancel()
 	// Lock machine while replying to sync request.
 	if !ch.machMtx.TryLockCtx(ctx) {
 		log.Errorf("Could not lock machine mutex in time: %v", ctx.Err())
+		return
 	}
 	defer ch.machMtx.Unlock()
 
 	syncMsg := newChannelSyncMsg(persistence.CloneSource(ch.machine))
+	syncMsg.Reply = true
 	if err := c.conn.pubMsg(ctx, syncMsg, peer); err != nil {
 		log.Error("Error sending sync reply: ", err)
 		return
@@ -64,6 +66,125 @@ func (c *Client) handleSyncMsg(peer wire.Address, msg *msgChannelSync) {
 			log.Error("Error discarding update: ", err)
 		}
 	}
+
+	if err := ch.adoptSyncedTX(c.Ctx(), msg); err != nil {
+		log.Error("Error syncing channel: ", err)
+	}
+}
+
+// Sync detects and recovers from a divergence of the channel state between
+// the participants, e.g., after an update message got lost. It sends the own
+// current state to all peers and receives theirs. If a peer holds a newer
+// state that is signed by all participants, it becomes the current state of
+// the channel. Peers that are behind adopt the own state the same way. An
+// ongoing update is discarded by all participants.
+//
+// Returns a RequestTimedOutError if any peer did not respond before the
+// context expires, and an error if a peer holds a different state of the same
+// version, which cannot be resolved.
+//
+// The machine is not locked while waiting for the replies, so that the peers
+// can sync with this channel at the same time.
+func (c *Channel) Sync(ctx context.Context) error {
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+
+	recv := wire.NewReceiver()
+	// nolint:errcheck
+	defer recv.Close() // ignore error
+	id := c.ID()
+	if err := c.client.conn.Subscribe(recv, func(m *wire.Envelope) bool {
+		return m.Msg.Type() == wire.ChannelSyncReply && m.Msg.(ChannelMsg).ID() == id
+	}); err != nil {
+		return errors.WithMessage(err, "subscribing on relay")
+	}
+
+	syncMsg, err := c.prepareSync(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.conn.Send(ctx, syncMsg); err != nil {
+		return errors.WithMessage(err, "sending sync message")
+	}
+
+	missing := make(map[channel.Index]bool, c.machine.N())
+	for i := channel.Index(0); i < c.machine.N(); i++ {
+		if i != c.machine.Idx() {
+			missing[i] = true
+		}
+	}
+	for len(missing) > 0 {
+		env, err := recv.Next(ctx)
+		if err != nil {
+			if pcontext.IsContextError(err) {
+				return newRequestTimedOutError("channel sync",
+					fmt.Sprintf("%v: missing responses of participants %v", err, sortedIdxs(missing)))
+			}
+			return errors.WithMessage(err, "receiving sync message")
+		}
+		pidx := channel.Index(wire.IndexOfAddr(c.conn.Peers(), env.Sender))
+		if !missing[pidx] {
+			c.Log().WithField("peer", env.Sender).Warn("Ignoring unexpected sync message.")
+			continue
+		}
+		delete(missing, pidx)
+
+		if err := c.adoptSyncedReply(ctx, env.Msg.(*msgChannelSync)); err != nil {
+			return errors.WithMessagef(err, "syncing with peer[%d]", pidx)
+		}
+	}
+	return nil
+}
+
+// prepareSync discards an ongoing update, as the peers do when receiving the
+// sync request, and returns the sync request with the current state.
+func (c *Channel) prepareSync(ctx context.Context) (*msgChannelSync, error) {
+	if !c.machMtx.TryLockCtx(ctx) {
+		return nil, errors.Errorf("locking machine mutex in time: %v", ctx.Err())
+	}
+	defer c.machMtx.Unlock()
+
+	if c.machine.Phase() == channel.Signing {
+		if err := c.machine.DiscardUpdate(ctx); err != nil {
+			return nil, errors.WithMessage(err, "discarding update")
+		}
+	}
+	return newChannelSyncMsg(persistence.CloneSource(c.machine)), nil
+}
+
+// adoptSyncedReply locks the machine and adopts the transaction of a peer's
+// sync reply, see adoptSyncedTX.
+func (c *Channel) adoptSyncedReply(ctx context.Context, msg *msgChannelSync) error {
+	if !c.machMtx.TryLockCtx(ctx) {
+		return errors.Errorf("locking machine mutex in time: %v", ctx.Err())
+	}
+	defer c.machMtx.Unlock()
+	return c.adoptSyncedTX(ctx, msg)
+}
+
+// adoptSyncedTX makes the current transaction of the peer's sync message the
+// own current transaction if it is newer. It returns an error if the peer's
+// state has the same version as the own state but differs from it.
+// The machine mutex must be held when calling this method.
+func (c *Channel) adoptSyncedTX(ctx context.Context, msg *msgChannelSync) error {
+	tx := msg.CurrentTX
+	current := c.machine.State()
+	if tx.State == nil || tx.Version < current.Version {
+		return nil
+	} else if tx.Version == current.Version {
+		return errors.WithMessage(tx.State.Equal(current), "different states for same version")
+	}
+
+	if err := c.machine.SyncTX(ctx, tx); err != nil {
+		return errors.WithMessage(err, "adopting synced state")
+	}
+	c.Log().WithField("version", tx.Version).Info("Synced newer channel state.")
+
+	if c.onUpdate != nil {
+		c.onUpdate(current, c.machine.State())
+	}
+	return nil
 }
 
 // syncChannel synchronizes the channel state with the given peer and modifies
@@ -28,11 +28,21 @@ func init() {
 			var m msgChannelSync
 			return &m, m.Decode(r)
 		})
+	wire.RegisterDecoder(wire.ChannelSyncReply,
+		func(r io.Reader) (wire.Msg, error) {
+			m := msgChannelSync{Reply: true}
+			return &m, m.Decode(r)
+		})
 }
 
+// msgChannelSync is the wire message to synchronize the channel state with a
+// peer. Replies to a sync request are sent as ChannelSyncReply and have the
+// same encoding, so that peers that do not know replies can still decode
+// ChannelSync messages.
 type msgChannelSync struct {
 	Phase     channel.Phase       // Phase is the phase of the sender.
 	CurrentTX channel.Transaction // CurrentTX is the sender's current transaction.
+	Reply     bool                // Reply is set if the message answers a sync request.
 }
 
 var _ ChannelMsg = (*msgChannelSync)(nil)
@@ -63,7 +73,11 @@ func (m *msgChannelSync) ID() channel.ID {
 	return m.CurrentTX.ID
 }
 
-// Type implements wire.Type.
+// Type implements wire.Type. It returns ChannelSyncReply for replies and
+// ChannelSync otherwise.
 func (m *msgChannelSync) Type() wire.Type {
+	if m.Reply {
+		return wire.ChannelSyncReply
+	}
 	return wire.ChannelSync
 }
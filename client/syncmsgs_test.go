@@ -0,0 +1,66 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/test"
+	perunio "perun.network/go-perun/pkg/io"
+	pkgtest "perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+func TestChannelSyncSerialization(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 4; i++ {
+		state := test.NewRandomState(rng)
+		sigs := make([]wallet.Sig, state.NumParts())
+		for j := range sigs {
+			sigs[j] = newRandomSig(rng)
+		}
+		m := &msgChannelSync{
+			Phase:     channel.Phase(rng.Intn(int(channel.Withdrawn) + 1)),
+			CurrentTX: channel.Transaction{State: state, Sigs: sigs},
+			Reply:     rng.Intn(2) == 0,
+		}
+		wire.TestMsg(t, m)
+	}
+}
+
+func TestChannelSyncCompatibility(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	state := test.NewRandomState(rng)
+	m := &msgChannelSync{
+		Phase:     channel.Acting,
+		CurrentTX: channel.Transaction{State: state, Sigs: make([]wallet.Sig, state.NumParts())},
+	}
+
+	// Sync requests are encoded as before sync replies existed.
+	var buf bytes.Buffer
+	require.NoError(t, wire.Encode(m, &buf))
+	var legacy bytes.Buffer
+	require.NoError(t, perunio.Encode(&legacy, byte(wire.ChannelSync), m.Phase, m.CurrentTX))
+	assert.Equal(t, legacy.Bytes(), buf.Bytes())
+
+	m.Reply = true
+	assert.Equal(t, wire.ChannelSyncReply, m.Type())
+}
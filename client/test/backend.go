@@ -37,6 +37,9 @@ type (
 		latestEvents map[channel.ID]channel.AdjudicatorEvent
 		eventSubs    map[channel.ID][]chan channel.AdjudicatorEvent
 		balances     map[addressMapKey]map[assetMapKey]*big.Int
+		// unpaid holds the outcomes of channels that were concluded without
+		// being withdrawn.
+		unpaid map[channel.ID]channel.Balances
 	}
 
 	rng interface {
@@ -57,6 +60,7 @@ func NewMockBackend(rng *rand.Rand) *MockBackend {
 		latestEvents: make(map[channel.ID]channel.AdjudicatorEvent),
 		eventSubs:    make(map[channel.ID][]chan channel.AdjudicatorEvent),
 		balances:     make(map[string]map[string]*big.Int),
+		unpaid:       make(map[channel.ID]channel.Balances),
 	}
 }
 
@@ -173,27 +177,61 @@ func outcomeRecursive(state *channel.State, subStates channel.StateMap) (outcome
 
 // Withdraw withdraws the channel funds.
 func (b *MockBackend) Withdraw(_ context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error {
+	return b.withdraw(req, subStates, nil)
+}
+
+// WithdrawTo is like Withdraw, but the outcome of the participant of req is
+// sent to receiver. Because the mock pays out all participants when the
+// channel is concluded, only the first withdrawal can set a receiver.
+func (b *MockBackend) WithdrawTo(_ context.Context, req channel.AdjudicatorReq, subStates channel.StateMap, receiver wallet.Address) error {
+	return b.withdraw(req, subStates, receiver)
+}
+
+// Conclude concludes the channel with the state of req without paying out
+// its outcome. The outcome is paid out on the next withdrawal.
+func (b *MockBackend) Conclude(_ context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.conclude(req, subStates)
+	return nil
+}
 
+func (b *MockBackend) conclude(req channel.AdjudicatorReq, subStates channel.StateMap) {
 	// Check concluded.
 	ch := req.Params.ID()
 	if b.isConcluded(ch) {
-		log.Debug("withdraw: already concluded:", ch)
+		log.Debug("conclude: already concluded:", ch)
+		return
+	}
+
+	b.unpaid[ch] = outcomeRecursive(req.Tx.State, subStates)
+	b.setLatestEvent(ch, channel.NewConcludedEvent(ch, &channel.ElapsedTimeout{}, req.Tx.Version))
+}
+
+func (b *MockBackend) withdraw(req channel.AdjudicatorReq, subStates channel.StateMap, receiver wallet.Address) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.conclude(req, subStates)
+	ch := req.Params.ID()
+	outcome, ok := b.unpaid[ch]
+	if !ok {
+		log.Debug("withdraw: already withdrawn:", ch)
 		return nil
 	}
+	delete(b.unpaid, ch)
 
-	outcome := outcomeRecursive(req.Tx.State, subStates)
 	b.log.Infof("Withdraw: %+v, %+v, %+v", req, subStates, outcome)
 	for a, assetOutcome := range outcome {
 		asset := req.Tx.Allocation.Assets[a]
 		for p, amount := range assetOutcome {
 			participant := req.Params.Parts[p]
+			if receiver != nil && channel.Index(p) == req.Idx {
+				participant = receiver
+			}
 			b.addBalance(participant, asset, amount)
 		}
 	}
-
-	b.setLatestEvent(ch, channel.NewConcludedEvent(ch, &channel.ElapsedTimeout{}, req.Tx.Version))
 	return nil
 }
 
@@ -247,6 +285,14 @@ func encodableAsString(e io.Encoder) string {
 	return buf.String()
 }
 
+// Deposit deducts v of asset a from the balance of participant p, as if p
+// deposited it into a channel. It can be used by funders in tests.
+func (b *MockBackend) Deposit(p wallet.Address, a channel.Asset, v *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.addBalance(p, a, new(big.Int).Neg(v))
+}
+
 // GetBalance returns the balance for the participant and asset.
 func (b *MockBackend) GetBalance(p wallet.Address, a channel.Asset) *big.Int {
 	b.mu.Lock()
@@ -275,6 +321,8 @@ func (b *MockBackend) Subscribe(ctx context.Context, params *channel.Params) (ch
 
 	sub := &mockSubscription{
 		ctx:    ctx,
+		b:      b,
+		id:     params.ID(),
 		events: make(chan channel.AdjudicatorEvent, 1),
 		err:    make(chan error, 1),
 	}
@@ -290,13 +338,19 @@ func (b *MockBackend) Subscribe(ctx context.Context, params *channel.Params) (ch
 
 type mockSubscription struct {
 	ctx    context.Context
+	b      *MockBackend
+	id     channel.ID
 	events chan channel.AdjudicatorEvent
 	err    chan error
+	once   sync.Once
 }
 
 func (s *mockSubscription) Next() channel.AdjudicatorEvent {
 	select {
-	case e := <-s.events:
+	case e, ok := <-s.events:
+		if !ok {
+			return nil
+		}
 		return e
 	case <-s.ctx.Done():
 		s.err <- s.ctx.Err()
@@ -304,11 +358,28 @@ func (s *mockSubscription) Next() channel.AdjudicatorEvent {
 	}
 }
 
+// Close unsubscribes from the backend. It may be called multiple times.
 func (s *mockSubscription) Close() error {
-	close(s.events)
+	s.once.Do(func() {
+		s.b.mu.Lock()
+		defer s.b.mu.Unlock()
+		subs := s.b.eventSubs[s.id]
+		for i, events := range subs {
+			if events == s.events {
+				s.b.eventSubs[s.id] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(s.events)
+	})
 	return nil
 }
 
 func (s *mockSubscription) Err() error {
-	return <-s.err
+	select {
+	case err := <-s.err:
+		return err
+	default:
+		return nil
+	}
 }
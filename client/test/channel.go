@@ -121,6 +121,22 @@ func (ch *paymentChannel) sendTransfer(amount channel.Bal, desc string) {
 	ch.assertBals(ch.State())
 }
 
+// sendDroppedTransfer proposes a transfer that is expected to fail, e.g.,
+// because the update message is dropped. The tracked balances are unchanged.
+func (ch *paymentChannel) sendDroppedTransfer(amount channel.Bal, desc string) {
+	ch.log.Debugf("Sending dropped update: %s", desc)
+	ctx, cancel := context.WithTimeout(context.Background(), ch.r.timeout)
+	defer cancel()
+
+	err := ch.UpdateBy(ctx, func(state *channel.State) error {
+		transferBal(stateBals(state), ch.Idx(), amount)
+		return nil
+	})
+	ch.log.Infof("Sent dropped update: %s, err: %v", desc, err)
+	assert.Error(ch.r.t, err)
+	ch.assertBals(ch.State())
+}
+
 func (ch *paymentChannel) recvUpdate(accept bool, desc string) *channel.State {
 	ch.log.Debugf("Receiving update: %s, accept: %t", desc, accept)
 	ch.handler <- accept
@@ -192,14 +208,33 @@ func (ch *paymentChannel) settleImpl(secondary bool) {
 	ch.assertBals(ch.State())
 
 	if ch.IsSubChannel() {
-		// Track parent channel's balances.
-		parentChannel, ok := ch.r.chans.get(ch.Parent().ID())
-		assert.True(ok, "parent channel not found")
-
-		for i, bal := range ch.bals {
-			parentBal := parentChannel.bals[i]
-			parentBal.Add(parentBal, bal)
-		}
+		ch.trackParentBals()
+	}
+}
+
+// collapse withdraws the final sub-channel into its parent via the parent's
+// CollapseSubChannel.
+func (ch *paymentChannel) collapse() {
+	assert := assert.New(ch.r.t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), ch.r.timeout)
+	defer cancel()
+
+	assert.NoError(ch.Parent().CollapseSubChannel(ctx, ch.ID()))
+	assert.Equal(channel.Withdrawn, ch.Phase())
+	ch.assertBals(ch.State())
+	ch.trackParentBals()
+}
+
+// trackParentBals adds the sub-channel's balances to the tracked balances of
+// its parent channel.
+func (ch *paymentChannel) trackParentBals() {
+	parentChannel, ok := ch.r.chans.get(ch.Parent().ID())
+	assert.True(ch.r.t, ok, "parent channel not found")
+
+	for i, bal := range ch.bals {
+		parentBal := parentChannel.bals[i]
+		parentBal.Add(parentBal, bal)
 	}
 }
 
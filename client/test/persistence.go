@@ -109,7 +109,9 @@ func (r *Petra) Execute(cfg ExecConfig) {
 	// Restore channels locally
 	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
 	defer cancel()
-	assrt.NoError(r.Restore(ctx)) // should restore channels
+	chs, err := r.Restore(ctx) // should restore channels
+	assrt.NoError(err)
+	assrt.Len(chs, 1)
 	select {
 	case ch = <-newCh: // expected
 		assrt.NotNil(ch)
@@ -169,7 +171,9 @@ func (r *Robert) Execute(cfg ExecConfig) {
 	// Restore channels locally
 	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
 	defer cancel()
-	assrt.NoError(r.Restore(ctx)) // should restore channels
+	chs, err := r.Restore(ctx) // should restore channels
+	assrt.NoError(err)
+	assrt.Len(chs, 1)
 	select {
 	case ch = <-newCh: // expected
 		assrt.NotNil(ch)
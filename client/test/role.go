@@ -267,12 +267,12 @@ func (r *role) Idxs(peers [2]wire.Address) (our, their channel.Index) {
 func (r *role) ProposeChannel(req client.ChannelProposal) (*paymentChannel, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
 	defer cancel()
-	_ch, err := r.Client.ProposeChannel(ctx, req)
+	res, err := r.Client.ProposeChannel(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	// Client.OnNewChannel callback adds paymentChannel wrapper to the chans map
-	ch, ok := r.chans.get(_ch.ID())
+	ch, ok := r.chans.get(res.ID)
 	if !ok {
 		return ch, errors.New("channel not found")
 	}
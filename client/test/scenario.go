@@ -0,0 +1,368 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/wire"
+)
+
+type (
+	// ActionKind is the kind of an Action in a Scenario.
+	ActionKind int
+
+	// An Action is a step of a Scenario that is performed by one of the two
+	// roles. The other role performs the complementary step, e.g., it receives
+	// a transfer.
+	Action struct {
+		Kind   ActionKind
+		Role   int      // Index of the acting role, 0 (proposer) or 1 (responder).
+		Amount *big.Int // Transferred amount of Transfer and DropTransfer.
+	}
+
+	// A Scenario describes a sequence of possibly adversarial actions on a
+	// two-party ledger channel. It is executed with ExecuteScenario.
+	//
+	// The channel is proposed by role 0 and accepted by role 1. Then, the
+	// actions are executed in lock-step. Afterwards, the channel is settled
+	// cooperatively if no dispute happened, or unilaterally by all online roles
+	// otherwise. Finally, the withdrawn balances are asserted.
+	Scenario struct {
+		BaseExecConfig
+		Actions []Action
+	}
+
+	// scenarioParty contains the state that a role needs to execute its part
+	// of a Scenario.
+	scenarioParty struct {
+		idx        int
+		sc         *Scenario
+		bus        *droppingBus
+		registered chan *channel.RegisteredEvent
+		channel    chan *paymentChannel // hands the opened channel to ExecuteScenario
+	}
+
+	scenarioProposer struct {
+		Proposer
+		scenarioParty
+	}
+
+	scenarioResponder struct {
+		Responder
+		scenarioParty
+	}
+
+	// droppingBus is a wire.Bus that silently drops a configurable number of
+	// outgoing channel update messages.
+	droppingBus struct {
+		wire.Bus
+		drop int32 // number of updates to drop, accessed atomically
+	}
+)
+
+// Action kinds.
+const (
+	// ActTransfer lets the role send Amount to the other role.
+	ActTransfer ActionKind = iota
+	// ActDropTransfer lets the role propose a transfer of Amount whose update
+	// message is lost on the way. The update times out and is discarded.
+	ActDropTransfer
+	// ActGoOffline lets the role stop participating in the channel. It does
+	// not react to disputes and does not settle the channel.
+	ActGoOffline
+	// ActRegisterStale lets the role register the initial channel state. The
+	// other role watches the channel and must refute the registration.
+	ActRegisterStale
+)
+
+// Transfer returns an Action in which role sends amount to the other role.
+func Transfer(role int, amount int64) Action {
+	return Action{Kind: ActTransfer, Role: role, Amount: big.NewInt(amount)}
+}
+
+// DropTransfer returns an Action in which role proposes a transfer of amount
+// whose update message is dropped.
+func DropTransfer(role int, amount int64) Action {
+	return Action{Kind: ActDropTransfer, Role: role, Amount: big.NewInt(amount)}
+}
+
+// GoOffline returns an Action in which role goes offline.
+func GoOffline(role int) Action {
+	return Action{Kind: ActGoOffline, Role: role}
+}
+
+// RegisterStale returns an Action in which role registers the initial state
+// of the channel.
+func RegisterStale(role int) Action {
+	return Action{Kind: ActRegisterStale, Role: role}
+}
+
+// String returns the name of the action kind.
+func (k ActionKind) String() string {
+	switch k {
+	case ActTransfer:
+		return "Transfer"
+	case ActDropTransfer:
+		return "DropTransfer"
+	case ActGoOffline:
+		return "GoOffline"
+	case ActRegisterStale:
+		return "RegisterStale"
+	}
+	return fmt.Sprintf("ActionKind(%d)", int(k))
+}
+
+// validate checks that the scenario can be executed. No off-chain updates can
+// happen once a role is offline or the channel is disputed, and a stale
+// registration must be refuted by an online role.
+func (s *Scenario) validate() error {
+	var offline [2]bool
+	disputed := false
+	for i, a := range s.Actions {
+		if a.Role != 0 && a.Role != 1 {
+			return fmt.Errorf("action %d: invalid role %d", i, a.Role)
+		}
+		switch a.Kind {
+		case ActTransfer, ActDropTransfer:
+			if offline[0] || offline[1] || disputed {
+				return fmt.Errorf("action %d: %v after dispute or going offline", i, a.Kind)
+			}
+			if a.Amount == nil || a.Amount.Sign() < 0 {
+				return fmt.Errorf("action %d: invalid amount", i)
+			}
+		case ActGoOffline:
+			offline[a.Role] = true
+		case ActRegisterStale:
+			if offline[0] || offline[1] {
+				return fmt.Errorf("action %d: %v while a role is offline", i, a.Kind)
+			}
+			disputed = true
+		default:
+			return fmt.Errorf("action %d: unknown kind %v", i, a.Kind)
+		}
+	}
+	return nil
+}
+
+// disputed returns whether the channel has to be settled unilaterally.
+func (s *Scenario) disputed() bool {
+	for _, a := range s.Actions {
+		if a.Kind == ActGoOffline || a.Kind == ActRegisterStale {
+			return true
+		}
+	}
+	return false
+}
+
+// offline returns whether role goes offline during the scenario.
+func (s *Scenario) offline(role int) bool {
+	for _, a := range s.Actions {
+		if a.Kind == ActGoOffline && a.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// registersStale returns whether role registers a stale state.
+func (s *Scenario) registersStale(role int) bool {
+	for _, a := range s.Actions {
+		if a.Kind == ActRegisterStale && a.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedBals returns the final balances of both roles.
+func (s *Scenario) expectedBals() [2]*big.Int {
+	bals := [2]*big.Int{new(big.Int).Set(s.initBals[0]), new(big.Int).Set(s.initBals[1])}
+	for _, a := range s.Actions {
+		if a.Kind == ActTransfer {
+			bals[a.Role].Sub(bals[a.Role], a.Amount)
+			bals[a.Role^1].Add(bals[a.Role^1], a.Amount)
+		}
+	}
+	return bals
+}
+
+// ExecuteScenario executes the scenario with two roles created from the given
+// setups, using the Bus, Funder and Adjudicator of the setups. It asserts the
+// final balances of the channel participants using the setups' Backend.
+func ExecuteScenario(t *testing.T, setups [2]RoleSetup, s *Scenario) {
+	t.Helper()
+	if err := s.validate(); err != nil {
+		t.Fatal("Invalid scenario: ", err)
+	}
+
+	numStages := len(s.Actions) + 1
+	parties := [2]scenarioParty{}
+	for i := range parties {
+		parties[i] = scenarioParty{
+			idx:        i,
+			sc:         s,
+			bus:        &droppingBus{Bus: setups[i].Bus},
+			registered: make(chan *channel.RegisteredEvent, 10),
+			channel:    make(chan *paymentChannel, 1),
+		}
+		setups[i].Bus = parties[i].bus
+	}
+	roles := [2]Executer{
+		&scenarioProposer{Proposer: *NewProposer(setups[0], t, numStages), scenarioParty: parties[0]},
+		&scenarioResponder{Responder: *NewResponder(setups[1], t, numStages), scenarioParty: parties[1]},
+	}
+	ExecuteTwoPartyTest(t, roles, s)
+
+	var ch *paymentChannel
+	select {
+	case ch = <-parties[0].channel:
+	default:
+		t.Fatal("channel was not opened")
+	}
+	if setups[0].Backend == nil {
+		return
+	}
+	expected := s.expectedBals()
+	for i, part := range ch.Params().Parts {
+		bal := setups[0].Backend.GetBalance(part, s.Asset())
+		assert.Zerof(t, bal.Cmp(expected[i]), "withdrawn balance of role %d: %v != %v", i, bal, expected[i])
+	}
+}
+
+// Execute executes the scenario as proposer.
+func (r *scenarioProposer) Execute(cfg ExecConfig) {
+	r.Proposer.Execute(cfg, func(_ ExecConfig, ch *paymentChannel) {
+		r.run(&r.role, ch)
+	})
+}
+
+// Execute executes the scenario as responder.
+func (r *scenarioResponder) Execute(cfg ExecConfig) {
+	r.Responder.Execute(cfg, func(_ ExecConfig, ch *paymentChannel, _ *acceptNextPropHandler) {
+		r.run(&r.role, ch)
+	})
+}
+
+// HandleAdjudicatorEvent forwards registered events to the party.
+func (p *scenarioParty) HandleAdjudicatorEvent(e channel.AdjudicatorEvent) {
+	if e, ok := e.(*channel.RegisteredEvent); ok {
+		p.registered <- e
+	}
+}
+
+func (p *scenarioParty) run(r *role, ch *paymentChannel) {
+	assert := assert.New(r.t)
+	we := p.idx
+	p.channel <- ch
+	// Request for the initial state, used for stale registrations.
+	req0 := client.NewTestChannel(ch.Channel).AdjudicatorReq()
+
+	// Honest roles watch the channel and refute stale registrations.
+	if !p.sc.registersStale(we) {
+		go func() {
+			r.log.Info("Starting channel watcher.")
+			err := ch.Watch(p)
+			r.log.Infof("Channel watcher returned: %v", err)
+		}()
+	}
+	// 1st stage - channel opened
+	r.waitStage()
+
+	offline := false
+	for i, a := range p.sc.Actions {
+		ours := a.Role == we
+		desc := fmt.Sprintf("%v#%d", a.Kind, i)
+		switch {
+		case offline:
+			// Offline roles do not participate anymore.
+		case a.Kind == ActTransfer && ours:
+			ch.sendTransfer(a.Amount, desc)
+		case a.Kind == ActTransfer:
+			ch.recvTransfer(a.Amount, desc)
+		case a.Kind == ActDropTransfer && ours:
+			p.bus.dropNext()
+			ch.sendDroppedTransfer(a.Amount, desc)
+		case a.Kind == ActGoOffline && ours:
+			r.log.Info("Going offline.")
+			offline = true
+		case a.Kind == ActRegisterStale && ours:
+			ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+			r.log.Debug("Registering stale state.")
+			assert.NoError(r.setup.Adjudicator.Register(ctx, req0, nil))
+			cancel()
+		case a.Kind == ActRegisterStale:
+			p.awaitRefutation(r, ch)
+		}
+		r.waitStage()
+	}
+
+	switch {
+	case offline:
+	case p.sc.disputed():
+		ch.settle()
+	case we == 0:
+		ch.sendFinal()
+		ch.settle()
+	default:
+		ch.recvFinal()
+		ch.settleSecondary()
+	}
+}
+
+// awaitRefutation waits until the current state of the channel is registered.
+func (p *scenarioParty) awaitRefutation(r *role, ch *paymentChannel) {
+	version := ch.State().Version
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	for {
+		select {
+		case e := <-p.registered:
+			r.log.Debugf("Registered version %d, local version %d", e.Version(), version)
+			if e.Version() == version {
+				return
+			}
+		case <-ctx.Done():
+			r.t.Error("timeout: expected refutation of stale state")
+			return
+		}
+	}
+}
+
+// Publish drops the envelope if it is a channel update and updates are to be
+// dropped. Otherwise, it is forwarded to the underlying bus.
+func (b *droppingBus) Publish(ctx context.Context, env *wire.Envelope) error {
+	if env.Msg.Type() == wire.ChannelUpdate {
+		for n := atomic.LoadInt32(&b.drop); n > 0; n = atomic.LoadInt32(&b.drop) {
+			if atomic.CompareAndSwapInt32(&b.drop, n, n-1) {
+				return nil
+			}
+		}
+	}
+	return b.Bus.Publish(ctx, env)
+}
+
+// dropNext lets the bus drop the next channel update.
+func (b *droppingBus) dropNext() {
+	atomic.AddInt32(&b.drop, 1)
+}
@@ -30,6 +30,9 @@ type SusieTimExecConfig struct {
 	SubSubChannelFunds [][2]*big.Int       // sub-sub-channel funding amounts, also determines number of sub-sub-channels
 	LeafChannelApp     client.ProposalOpts // app used in the leaf channels
 	TxAmount           *big.Int            // transaction amount
+	// CollapseSubSubChannels makes the sub-sub-channels be withdrawn with
+	// Channel.CollapseSubChannel instead of Channel.Settle.
+	CollapseSubSubChannels bool
 }
 
 // NewSusieTimExecConfig creates a new object from the given parameters.
@@ -128,7 +131,12 @@ func (r *Susie) exec(_cfg ExecConfig, ledgerChannel *paymentChannel) {
 	// stage 5 - finalize sub-channels 1.1, ..., 1.NumSubSubChannels
 
 	for _, ch := range subSubChannels {
-		finalizeAndSettle(ch)
+		if cfg.CollapseSubSubChannels {
+			ch.sendFinal()
+			ch.collapse()
+		} else {
+			finalizeAndSettle(ch)
+		}
 	}
 
 	r.waitStage()
@@ -221,7 +229,12 @@ func (r *Tim) exec(_cfg ExecConfig, ledgerChannel *paymentChannel, propHandler *
 	// stage 5 - finalize sub-channels 1.1, ..., 1.NumSubSubChannels
 
 	for _, ch := range subSubChannels {
-		finalizeAndSettle(ch)
+		if cfg.CollapseSubSubChannels {
+			ch.recvFinal()
+			ch.collapse()
+		} else {
+			finalizeAndSettle(ch)
+		}
 	}
 
 	r.waitStage()
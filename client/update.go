@@ -16,12 +16,16 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/log"
+	"perun.network/go-perun/pkg/clock"
 	pcontext "perun.network/go-perun/pkg/context"
+	perunio "perun.network/go-perun/pkg/io"
 	"perun.network/go-perun/pkg/sync/atomic"
 	"perun.network/go-perun/wallet"
 	"perun.network/go-perun/wire"
@@ -68,6 +72,12 @@ type (
 		// ActorIdx is the actor causing the new state. It does not need to
 		// coincide with the sender of the request.
 		ActorIdx channel.Index
+
+		// payload is the optional application payload of the update. It is not
+		// part of the state and not signed.
+		payload []byte
+		// steps are the intermediate states of a batch update, see Steps.
+		steps []*channel.State
 	}
 
 	// An UpdateHandler decides how to handle incoming channel update requests
@@ -87,14 +97,16 @@ type (
 
 	// The UpdateResponder allows the user to react to the incoming channel update
 	// request. If the user wants to accept the update, Accept() should be called,
-	// otherwise Reject(), possibly giving a reason for the rejection.
+	// otherwise Reject(), possibly giving a reason for the rejection. The
+	// decision can be postponed with Defer().
 	// Only a single function must be called and every further call causes a
 	// panic.
 	UpdateResponder struct {
-		channel *Channel
-		pidx    channel.Index
-		req     ChannelUpdateProposal
-		called  atomic.Bool
+		channel  *Channel
+		pidx     channel.Index
+		req      ChannelUpdateProposal
+		called   atomic.Bool
+		deferred atomic.Bool // set by Defer
 	}
 
 	// RequestTimedOutError indicates that a peer has not responded within the
@@ -121,6 +133,13 @@ func (r *UpdateResponder) Accept(ctx context.Context) error {
 
 // Reject lets the user signal that they reject the channel update.
 func (r *UpdateResponder) Reject(ctx context.Context, reason string) error {
+	return r.RejectWithCode(ctx, UpdateRejectUnspecified, reason)
+}
+
+// RejectWithCode is like Reject but additionally sends a reason code to the
+// proposer of the update, which is then contained in the PeerRejectedError
+// returned by Channel.Update.
+func (r *UpdateResponder) RejectWithCode(ctx context.Context, code UpdateRejectCode, reason string) error {
 	if ctx == nil {
 		return errors.New("context must not be nil")
 	}
@@ -128,7 +147,7 @@ func (r *UpdateResponder) Reject(ctx context.Context, reason string) error {
 		log.Panic("multiple calls on channel update responder")
 	}
 
-	return r.channel.handleUpdateRej(ctx, r.pidx, r.req, reason)
+	return r.channel.handleUpdateRej(ctx, r.pidx, r.req, code, reason)
 }
 
 // Update proposes the `next` state to all channel participants.
@@ -136,12 +155,28 @@ func (r *UpdateResponder) Reject(ctx context.Context, reason string) error {
 //
 // Returns nil if all peers accept the update. Returns RequestTimedOutError if
 // any peer did not respond before the context expires or is cancelled. Returns
-// an error if any runtime error occurs or any peer rejects the update.
+// PeerRejectedError if any peer rejects the update. It contains the channel ID
+// and version of the rejected update, and its UpdateCode states why the peer
+// rejected the update, if the peer gave a reason code. Returns
+// ErrUpdatePending if an incoming update is deferred, see
+// UpdateResponder.Defer. Returns an error if any runtime error occurs.
 func (c *Channel) Update(ctx context.Context, next *channel.State) (err error) {
+	return c.UpdateWithPayload(ctx, next, nil)
+}
+
+// UpdateWithPayload is like Update, but attaches an application payload to the
+// update request, e.g., a chat message or a move annotation. The peers'
+// UpdateHandler receives it via ChannelUpdate.Payload. The payload is not part
+// of the channel state and is not signed.
+func (c *Channel) UpdateWithPayload(ctx context.Context, next *channel.State, payload []byte) (err error) {
 	if ctx == nil {
 		return errors.New("context must not be nil")
 	}
 
+	if c.updatePending.IsSet() {
+		return errors.WithStack(ErrUpdatePending)
+	}
+
 	// Lock machine while update is in progress.
 	if !c.machMtx.TryLockCtx(ctx) {
 		return errors.Errorf("locking machine mutex in time: %v", ctx.Err())
@@ -152,7 +187,166 @@ func (c *Channel) Update(ctx context.Context, next *channel.State) (err error) {
 		return err
 	}
 
-	return c.update(ctx, next)
+	return c.updateGeneric(ctx, next, withPayload(payload))
+}
+
+// UpdateBatch applies the given update functions one after another to the
+// current state, each resulting in a state with the next version, and proposes
+// the resulting chain of states to all channel participants in a single
+// exchange. The update functions must not update the version counter.
+//
+// The peers validate every step of the batch and accept or reject the batch as
+// a whole. Only the last state of the batch is enabled, the intermediate
+// states are skipped. If any peer rejects any step, the whole batch is
+// discarded and the channel remains at its current state.
+//
+// Returns nil if all peers accept the batch. Returns RequestTimedOutError if
+// any peer did not respond before the context expires or is cancelled. Returns
+// an error if any runtime error occurs or any peer rejects the batch.
+func (c *Channel) UpdateBatch(ctx context.Context, updates []func(*channel.State) error) error {
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	if len(updates) == 0 {
+		return errors.New("empty batch")
+	}
+
+	if c.updatePending.IsSet() {
+		return errors.WithStack(ErrUpdatePending)
+	}
+
+	// Lock machine while update is in progress.
+	if !c.machMtx.TryLockCtx(ctx) {
+		return errors.Errorf("locking machine mutex in time: %v", ctx.Err())
+	}
+	defer c.machMtx.Unlock()
+
+	states := make([]*channel.State, len(updates))
+	prev := c.machine.State()
+	for i, update := range updates {
+		next := prev.Clone()
+		if err := update(next); err != nil {
+			return errors.WithMessagef(err, "applying update %d", i)
+		}
+		if err := c.validTwoPartyUpdateState(next); err != nil {
+			return errors.WithMessagef(err, "validating update %d", i)
+		}
+		next.Version = prev.Version + 1
+		states[i], prev = next, next
+	}
+
+	return c.updateBatch(ctx, states)
+}
+
+// updateBatch stages the last of the given states in the machine and proposes
+// the chain of states to the peers.
+func (c *Channel) updateBatch(ctx context.Context, states []*channel.State) (err error) {
+	if c.watchOnly {
+		return errors.WithStack(ErrWatchOnly)
+	}
+	up := makeChannelUpdate(states[len(states)-1], c.machine.Idx())
+	up.steps = states[:len(states)-1]
+	if err = c.machine.UpdateBatch(ctx, states, up.ActorIdx); err != nil {
+		return errors.WithMessage(err, "updating machine")
+	}
+
+	stepSigs := make([]wallet.Sig, len(up.steps))
+	for i, s := range up.steps {
+		if stepSigs[i], err = channel.Sign(c.machine.Account(), c.Params(), s); err != nil {
+			c.handleUpdateError(ctx, err)
+			return errors.WithMessagef(err, "signing step %d", i)
+		}
+	}
+
+	resRecv, err := c.sendStagedUpdate(ctx, up, func(mcu *msgChannelUpdate) wire.Msg {
+		return &msgChannelUpdateBatch{msgChannelUpdate: *mcu, StepSigs: stepSigs}
+	})
+	if err != nil {
+		return err
+	}
+	// nolint:errcheck
+	defer resRecv.Close()
+
+	return c.awaitUpdate(ctx, resRecv)
+}
+
+// PendingUpdate is a channel update that was sent with
+// Channel.UpdateOptimistic and awaits the responses of the peers.
+type PendingUpdate struct {
+	version uint64
+	done    chan struct{}
+	err     error
+}
+
+// UpdateOptimistic is like Update, but returns as soon as the update and our
+// signature are sent to the peers, without waiting for their responses. The
+// new state is only enabled once all peers accepted it. If any peer rejects
+// the update or does not respond before ctx expires, the update is discarded.
+// Use the returned PendingUpdate to await the outcome.
+//
+// The channel is locked while the update is pending, so that further updates,
+// optimistic or not, wait until it is enabled or discarded.
+func (c *Channel) UpdateOptimistic(ctx context.Context, next *channel.State) (*PendingUpdate, error) {
+	if ctx == nil {
+		return nil, errors.New("context must not be nil")
+	}
+
+	if c.updatePending.IsSet() {
+		return nil, errors.WithStack(ErrUpdatePending)
+	}
+
+	// Lock machine while update is in progress. It is unlocked once the
+	// responses of the peers are processed.
+	if !c.machMtx.TryLockCtx(ctx) {
+		return nil, errors.Errorf("locking machine mutex in time: %v", ctx.Err())
+	}
+
+	if err := c.validTwoPartyUpdateState(next); err != nil {
+		c.machMtx.Unlock()
+		return nil, err
+	}
+
+	resRecv, err := c.sendUpdate(ctx, next, func(mcu *msgChannelUpdate) wire.Msg { return mcu })
+	if err != nil {
+		c.machMtx.Unlock()
+		return nil, err
+	}
+
+	p := &PendingUpdate{version: next.Version, done: make(chan struct{})}
+	go func() {
+		defer c.machMtx.Unlock()
+		p.err = c.awaitUpdate(ctx, resRecv)
+		resRecv.Close() // nolint:errcheck,gosec
+		close(p.done)
+	}()
+	return p, nil
+}
+
+// Version returns the version of the pending update.
+func (p *PendingUpdate) Version() uint64 {
+	return p.version
+}
+
+// Done returns a channel that is closed once the update was either enabled or
+// discarded.
+func (p *PendingUpdate) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits until the update was either enabled or discarded.
+//
+// Returns nil if all peers accepted the update. Returns RequestTimedOutError if
+// any peer did not respond before the context of UpdateOptimistic expired.
+// Returns an error if any runtime error occurred or any peer rejected the
+// update. If ctx expires first, Wait returns its error and the update remains
+// pending.
+func (p *PendingUpdate) Wait(ctx context.Context) error {
+	select {
+	case <-p.done:
+		return p.err
+	case <-ctx.Done():
+		return errors.WithMessage(ctx.Err(), "waiting for pending update")
+	}
 }
 
 // Like Update, but assumes channel locked and update validated.
@@ -160,31 +354,72 @@ func (c *Channel) update(ctx context.Context, next *channel.State) (err error) {
 	return c.updateGeneric(ctx, next, func(mcu *msgChannelUpdate) wire.Msg { return mcu })
 }
 
+// withPayload returns a message preparation function for updateGeneric that
+// attaches the application payload to the update.
+func withPayload(payload []byte) func(*msgChannelUpdate) wire.Msg {
+	return func(mcu *msgChannelUpdate) wire.Msg {
+		mcu.payload = payload
+		return mcu
+	}
+}
+
 // Like update, but for generic update types.
 func (c *Channel) updateGeneric(
 	ctx context.Context,
 	next *channel.State,
 	prepareMsg func(*msgChannelUpdate) wire.Msg,
-) (err error) {
+) error {
+	resRecv, err := c.sendUpdate(ctx, next, prepareMsg)
+	if err != nil {
+		return err
+	}
+	// nolint:errcheck
+	defer resRecv.Close()
+
+	return c.awaitUpdate(ctx, resRecv)
+}
+
+// sendUpdate stages the update to next in the machine and sends it together
+// with our signature to the peers. It returns the receiver of the peers'
+// responses. If sending fails, the staged update is discarded.
+func (c *Channel) sendUpdate(
+	ctx context.Context,
+	next *channel.State,
+	prepareMsg func(*msgChannelUpdate) wire.Msg,
+) (_ *channelMsgRecv, err error) {
+	if c.watchOnly {
+		return nil, errors.WithStack(ErrWatchOnly)
+	}
 	up := makeChannelUpdate(next, c.machine.Idx())
 	if err = c.machine.Update(ctx, up.State, up.ActorIdx); err != nil {
-		return errors.WithMessage(err, "updating machine")
+		return nil, errors.WithMessage(err, "updating machine")
 	}
+	return c.sendStagedUpdate(ctx, up, prepareMsg)
+}
+
+// sendStagedUpdate sends the update up, which is already staged in the
+// machine, together with our signature to the peers. It returns the receiver
+// of the peers' responses. If sending fails, the staged update is discarded.
+func (c *Channel) sendStagedUpdate(
+	ctx context.Context,
+	up ChannelUpdate,
+	prepareMsg func(*msgChannelUpdate) wire.Msg,
+) (_ *channelMsgRecv, err error) {
+	ulog := c.logUpdate(up.State.Version)
+	ulog.WithField("actor", up.ActorIdx).Debug("Update proposed.")
 	// if anything goes wrong from now on, we discard the update.
 	// TODO: this is insecure after we sent our signature.
 	defer func() { c.handleUpdateError(ctx, err) }()
 
 	sig, err := c.machine.Sig(ctx)
 	if err != nil {
-		return errors.WithMessage(err, "signing update")
+		return nil, errors.WithMessage(err, "signing update")
 	}
 
 	resRecv, err := c.conn.NewUpdateResRecv(up.State.Version)
 	if err != nil {
-		return errors.WithMessage(err, "creating update response receiver")
+		return nil, errors.WithMessage(err, "creating update response receiver")
 	}
-	// nolint:errcheck
-	defer resRecv.Close()
 
 	msgUpdate := &msgChannelUpdate{
 		ChannelUpdate: up,
@@ -192,29 +427,115 @@ func (c *Channel) updateGeneric(
 	}
 	msg := prepareMsg(msgUpdate)
 	if err = c.conn.Send(ctx, msg); err != nil {
-		return errors.WithMessage(err, "sending update")
+		resRecv.Close() // nolint:errcheck,gosec
+		return nil, errors.WithMessage(err, "sending update")
 	}
+	ulog.Debug("Update signature sent.")
+	return resRecv, nil
+}
 
-	pidx, res, err := resRecv.Next(ctx)
-	if err != nil {
-		if pcontext.IsContextError(err) {
-			err = newRequestTimedOutError("channel update", err.Error())
-			return err
-		}
-		return errors.WithMessage(err, "receiving update response")
-	}
-	c.Log().Tracef("Received update response (%T): %v", res, res)
+// awaitUpdate collects the peers' responses to the staged update from resRecv
+// and enables the update once all peers accepted it. If any peer rejects the
+// update or an error occurs, the staged update is discarded.
+func (c *Channel) awaitUpdate(ctx context.Context, resRecv *channelMsgRecv) (err error) {
+	defer func() { c.handleUpdateError(ctx, err) }()
 
-	if rej, ok := res.(*msgChannelUpdateRej); ok {
-		return newPeerRejectedError("channel update", rej.Reason)
+	if err = c.collectUpdateSigs(ctx, resRecv, c.machine.Idx()); err != nil {
+		return err
 	}
 
-	acc := res.(*msgChannelUpdateAcc) // safe by predicate of the updateResRecv
-	if err := c.machine.AddSig(ctx, pidx, acc.Sig); err != nil {
-		return errors.WithMessage(err, "adding peer signature")
+	return c.enableNotifyUpdate(ctx)
+}
+
+// collectUpdateSigs receives update responses on resRecv until the signatures
+// of all participants except the given signers were added to the staging
+// state of the machine. Responses of participants that already responded are
+// ignored.
+//
+// If the client's UpdateResponseTimeout is set, each participant must respond
+// within it, counted from the call. Otherwise, only the context limits the
+// wait.
+//
+// Returns a PeerRejectedError if any participant rejects the update, and a
+// RequestTimedOutError naming the participants that did not respond if the
+// response timeout passes or the context expires before all signatures are
+// collected.
+func (c *Channel) collectUpdateSigs(ctx context.Context, resRecv *channelMsgRecv, signers ...channel.Index) error {
+	missing := make(map[channel.Index]bool, c.machine.N())
+	for i := channel.Index(0); i < c.machine.N(); i++ {
+		missing[i] = true
+	}
+	for _, i := range signers {
+		delete(missing, i)
+	}
+
+	ulog := c.logUpdate(c.machine.StagingState().Version)
+	start := c.client.clock.Now()
+	if timeout := c.client.cfg.UpdateResponseTimeout; timeout > 0 {
+		// All participants are awaited from the same start, so their response
+		// timeouts end at the same time.
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, c.client.clock, timeout)
+		defer cancel()
+	}
+	for len(missing) > 0 {
+		pidx, res, err := resRecv.NextFrom(ctx, sortedIdxs(missing))
+		if err != nil {
+			if pcontext.IsContextError(err) {
+				return newRequestTimedOutError("channel update",
+					fmt.Sprintf("%v: missing responses of participants %v", err, sortedIdxs(missing)))
+			}
+			return errors.WithMessage(err, "receiving update response")
+		}
+		c.Log().Tracef("Received update response (%T) from peer[%d]: %v", res, pidx, res)
+		if !missing[pidx] {
+			c.logPeer(pidx).Warn("Ignoring duplicate update response.")
+			continue
+		}
+
+		rej, rejected := res.(*msgChannelUpdateRej)
+		ulog.WithFields(log.Fields{
+			"peerIdx":  pidx,
+			"accepted": !rejected,
+			"latency":  c.client.clock.Now().Sub(start),
+		}).Debug("Update response received.")
+		if rejected {
+			return errors.WithStack(PeerRejectedError{
+				ItemType:   "channel update",
+				ChannelID:  c.ID(),
+				Version:    c.machine.StagingState().Version,
+				Reason:     rej.Reason,
+				UpdateCode: rej.Code,
+			})
+		}
+
+		acc := res.(*msgChannelUpdateAcc) // safe by predicate of the updateResRecv
+		if err := c.machine.AddSig(ctx, pidx, acc.Sig); err != nil {
+			return errors.WithMessagef(err, "adding signature of peer[%d]", pidx)
+		}
+		delete(missing, pidx)
 	}
+	return nil
+}
 
-	return c.enableNotifyUpdate(ctx)
+// logUpdate returns a logger for the lifecycle of the update to the given
+// version. The update field is derived from the channel ID and the version, so
+// all participants log the same value for the same update.
+func (c *Channel) logUpdate(version uint64) log.Logger {
+	return c.Log().WithFields(log.Fields{
+		"update":  fmt.Sprintf("%x:%d", c.ID(), version),
+		"version": version,
+	})
+}
+
+// sortedIdxs returns the indices contained in the set in ascending order.
+func sortedIdxs(set map[channel.Index]bool) []channel.Index {
+	idxs := make([]channel.Index, 0, len(set))
+	for i := range set {
+		idxs = append(idxs, i)
+	}
+	sort.Slice(idxs, func(i, j int) bool { return idxs[i] < idxs[j] })
+	return idxs
 }
 
 func (c *Channel) handleUpdateError(ctx context.Context, updateErr error) {
@@ -234,17 +555,27 @@ func (c *Channel) handleUpdateError(ctx context.Context, updateErr error) {
 // any peer did not respond before the context expires or is cancelled. Returns
 // an error if any runtime error occurs or any peer rejects the update.
 func (c *Channel) UpdateBy(ctx context.Context, update func(*channel.State) error) (err error) {
+	return c.UpdateByWithPayload(ctx, update, nil)
+}
+
+// UpdateByWithPayload is like UpdateBy, but attaches an application payload to
+// the update request, like UpdateWithPayload.
+func (c *Channel) UpdateByWithPayload(ctx context.Context, update func(*channel.State) error, payload []byte) (err error) {
 	if ctx == nil {
 		return errors.New("context must not be nil")
 	}
 
+	if c.updatePending.IsSet() {
+		return errors.WithStack(ErrUpdatePending)
+	}
+
 	// Lock machine while update is in progress.
 	if !c.machMtx.TryLockCtx(ctx) {
 		return errors.Errorf("locking machine mutex in time: %v", ctx.Err())
 	}
 	defer c.machMtx.Unlock()
 
-	return c.updateBy(ctx,
+	return c.updateByGeneric(ctx,
 		func(state *channel.State) error {
 			// apply update
 			if err := update(state); err != nil {
@@ -254,18 +585,28 @@ func (c *Channel) UpdateBy(ctx context.Context, update func(*channel.State) erro
 			// validate
 			return c.validTwoPartyUpdateState(state)
 		},
+		withPayload(payload),
 	)
 }
 
 // Like UpdateBy, but assumes channel locked and update validated.
 func (c *Channel) updateBy(ctx context.Context, update func(*channel.State) error) (err error) {
+	return c.updateByGeneric(ctx, update, func(mcu *msgChannelUpdate) wire.Msg { return mcu })
+}
+
+// Like updateBy, but for generic update types.
+func (c *Channel) updateByGeneric(
+	ctx context.Context,
+	update func(*channel.State) error,
+	prepareMsg func(*msgChannelUpdate) wire.Msg,
+) (err error) {
 	state := c.machine.State().Clone()
 	if err := update(state); err != nil {
 		return err
 	}
 	state.Version++
 
-	return c.update(ctx, state)
+	return c.updateGeneric(ctx, state, prepareMsg)
 }
 
 // handleUpdateReq is called by the controller on incoming channel update
@@ -275,17 +616,31 @@ func (c *Channel) handleUpdateReq(
 	req ChannelUpdateProposal,
 	uh UpdateHandler,
 ) {
-	c.machMtx.Lock() // Lock machine while update is in progress.
-	defer c.machMtx.Unlock()
+	responder := &UpdateResponder{channel: c, pidx: pidx, req: req}
+	client := c.client
 
-	if err := c.machine.CheckUpdate(req.Base().State, req.Base().ActorIdx, req.Base().Sig, pidx); err != nil {
-		// TODO: how to handle invalid updates? Just drop and ignore them?
-		c.logPeer(pidx).Warnf("invalid update received: %v", err)
+	if c.updatePending.IsSet() {
+		c.logPeer(pidx).Infof("rejecting update while deferred update pending")
+		client.rejectProposal(responder, UpdateRejectBusy, ErrUpdatePending.Error())
 		return
 	}
 
-	responder := &UpdateResponder{channel: c, pidx: pidx, req: req}
-	client := c.client
+	c.machMtx.Lock() // Lock machine while update is in progress.
+	defer func() {
+		// A deferred update keeps the machine locked until it is resolved.
+		if !responder.deferred.IsSet() {
+			c.machMtx.Unlock()
+		}
+	}()
+
+	if err := c.checkUpdateReq(pidx, req); err != nil {
+		c.handleInvalidUpdate(pidx, responder, err)
+		return
+	}
+	c.logUpdate(req.Base().State.Version).WithFields(log.Fields{
+		"peerIdx": pidx,
+		"actor":   req.Base().ActorIdx,
+	}).Debug("Update received.")
 
 	if prop, ok := req.(*virtualChannelFundingProposal); ok {
 		client.handleVirtualChannelFundingProposal(c, prop, responder)
@@ -307,15 +662,50 @@ func (c *Channel) handleUpdateReq(
 		return
 	}
 
-	if err := c.validTwoPartyUpdate(req.Base().ChannelUpdate, pidx); err != nil {
-		// TODO: how to handle invalid updates? Just drop and ignore them?
-		c.logPeer(pidx).Warnf("invalid update received: %v", err)
-		return
+	// Batch updates are checked step by step, single updates in one step.
+	prev, actor := c.machine.State(), req.Base().ActorIdx
+	for _, next := range updateChain(req) {
+		if err := c.validTwoPartyUpdate(ChannelUpdate{State: next, ActorIdx: actor}, pidx); err != nil {
+			if errors.As(err, new(ReserveViolatedError)) {
+				c.logPeer(pidx).Infof("update violates reserve: %v", err)
+				client.rejectProposal(responder, UpdateRejectInsufficientFunds, err.Error())
+				return
+			}
+			c.handleInvalidUpdate(pidx, responder, err)
+			return
+		}
+
+		if c.invariant != nil {
+			if err := c.invariant(prev, next); err != nil {
+				c.logPeer(pidx).Infof("update violates invariant: %v", err)
+				client.rejectProposal(responder, UpdateRejectAppLogic, err.Error())
+				return
+			}
+		}
+		prev = next
 	}
 
 	uh.HandleUpdate(c.machine.State(), req.Base().ChannelUpdate, responder)
 }
 
+// checkUpdateReq checks that the update request is a valid transition from the
+// current state, signed by peer pidx. For batch updates, every step is checked.
+func (c *Channel) checkUpdateReq(pidx channel.Index, req ChannelUpdateProposal) error {
+	if batch, ok := req.(*msgChannelUpdateBatch); ok {
+		return c.machine.CheckUpdates(batch.states(), batch.ActorIdx, batch.sigs(), pidx)
+	}
+	return c.machine.CheckUpdate(req.Base().State, req.Base().ActorIdx, req.Base().Sig, pidx)
+}
+
+// updateChain returns the states proposed by the update request in order. For
+// single updates, this is only the proposed state.
+func updateChain(req ChannelUpdateProposal) []*channel.State {
+	if batch, ok := req.(*msgChannelUpdateBatch); ok {
+		return batch.states()
+	}
+	return []*channel.State{req.Base().State}
+}
+
 func (c *Channel) handleUpdateAcc(
 	ctx context.Context,
 	pidx channel.Index,
@@ -328,7 +718,12 @@ func (c *Channel) handleUpdateAcc(
 	}()
 
 	// machine.Update and AddSig should never fail after CheckUpdate...
-	if err = c.machine.Update(ctx, req.Base().State, req.Base().ActorIdx); err != nil {
+	if batch, ok := req.(*msgChannelUpdateBatch); ok {
+		err = c.machine.UpdateBatch(ctx, batch.states(), batch.ActorIdx)
+	} else {
+		err = c.machine.Update(ctx, req.Base().State, req.Base().ActorIdx)
+	}
+	if err != nil {
 		return errors.WithMessage(err, "updating machine")
 	}
 	// if anything goes wrong from now on, we discard the update.
@@ -358,14 +753,33 @@ func (c *Channel) handleUpdateAcc(
 		c.Parent().registerSubChannelSettlement(c.ID(), req.Base().State.Balances)
 	}
 
+	// In channels with more than two participants, the other accepting
+	// participants' responses are received after sending ours. Responses that
+	// arrive earlier are cached by the channel connection.
+	var resRecv *channelMsgRecv
+	if c.machine.N() > 2 {
+		if resRecv, err = c.conn.NewUpdateResRecv(req.Base().State.Version); err != nil {
+			return errors.WithMessage(err, "creating update response receiver")
+		}
+		// nolint:errcheck
+		defer resRecv.Close()
+	}
+
 	msgUpAcc := &msgChannelUpdateAcc{
 		ChannelID: c.ID(),
 		Version:   req.Base().State.Version,
 		Sig:       sig,
 	}
-	if err := c.conn.Send(ctx, msgUpAcc); err != nil {
+	if err = c.conn.Send(ctx, msgUpAcc); err != nil {
 		return errors.WithMessage(err, "sending accept message")
 	}
+	c.logUpdate(msgUpAcc.Version).Debug("Update signature sent.")
+
+	if resRecv != nil {
+		if err = c.collectUpdateSigs(ctx, resRecv, pidx, c.machine.Idx()); err != nil {
+			return err
+		}
+	}
 
 	return c.enableNotifyUpdate(ctx)
 }
@@ -374,6 +788,7 @@ func (c *Channel) handleUpdateRej(
 	ctx context.Context,
 	pidx channel.Index,
 	req ChannelUpdateProposal,
+	code UpdateRejectCode,
 	reason string,
 ) (err error) {
 	defer func() {
@@ -386,8 +801,13 @@ func (c *Channel) handleUpdateRej(
 		ChannelID: c.ID(),
 		Version:   req.Base().State.Version,
 		Reason:    reason,
+		Code:      code,
+	}
+	if err = c.conn.Send(ctx, msgUpRej); err != nil {
+		return errors.WithMessage(err, "sending reject message")
 	}
-	return errors.WithMessage(c.conn.Send(ctx, msgUpRej), "sending reject message")
+	c.logUpdate(msgUpRej.Version).WithFields(log.Fields{"reason": reason, "code": code}).Debug("Update rejected.")
+	return nil
 }
 
 // enableNotifyUpdate enables the current staging state of the machine. If the
@@ -407,6 +827,8 @@ func (c *Channel) enableNotifyUpdate(ctx context.Context) error {
 		return errors.WithMessage(err, "enabling update")
 	}
 
+	c.logUpdate(to.Version).Debug("Update enabled.")
+
 	if c.onUpdate != nil {
 		c.onUpdate(from, to)
 	}
@@ -422,10 +844,38 @@ func (c *Channel) OnUpdate(cb func(from, to *channel.State)) {
 	c.onUpdate = cb
 }
 
+// SetUpdateInvariant sets up a check that is run on every incoming update
+// before it is passed to the UpdateHandler. If the check returns an error, the
+// update is rejected automatically with the error message as reason.
+// The invariant can be removed by passing nil.
+// The States that are passed to the check must not be modified.
+func (c *Channel) SetUpdateInvariant(inv func(prev, next *channel.State) error) {
+	c.machMtx.Lock()
+	defer c.machMtx.Unlock()
+	c.invariant = inv
+}
+
+// SetBalanceReserve sets the minimum balance that the own participant must
+// keep of every asset, in the order of the channel's assets. Any update,
+// incoming or outgoing, that drops the own balance of an asset below its
+// reserve is rejected with a ReserveViolatedError. Updates that increase a
+// balance that is below the reserve are still allowed. Passing nil removes
+// the reserve.
+func (c *Channel) SetBalanceReserve(reserve []channel.Bal) error {
+	c.machMtx.Lock()
+	defer c.machMtx.Unlock()
+	if n := len(c.machine.State().Assets); reserve != nil && len(reserve) != n {
+		return errors.Errorf("reserve has %d entries, channel has %d assets", len(reserve), n)
+	}
+	c.reserve = reserve
+	return nil
+}
+
 // validTwoPartyUpdate performs additional protocol-dependent checks on the
 // proposed update that go beyond the machine's checks:
 // * Actor and signer must be the same.
 // * Sub-allocations do not change.
+// * The own balances do not drop below the reserve.
 func (c *Channel) validTwoPartyUpdate(up ChannelUpdate, sigIdx channel.Index) error {
 	if up.ActorIdx != sigIdx {
 		return errors.Errorf(
@@ -434,6 +884,20 @@ func (c *Channel) validTwoPartyUpdate(up ChannelUpdate, sigIdx channel.Index) er
 	if err := channel.SubAllocsAssertEqual(c.machine.State().Locked, up.State.Locked); err != nil {
 		return errors.WithMessage(err, "sub-allocation changed")
 	}
+	return c.checkReserve(up.State)
+}
+
+// checkReserve checks that next does not drop any own balance below its
+// reserve.
+func (c *Channel) checkReserve(next *channel.State) error {
+	idx := c.machine.Idx()
+	cur := c.machine.State().Balances
+	for a, reserve := range c.reserve {
+		bal := next.Balances[a][idx]
+		if bal.Cmp(reserve) < 0 && bal.Cmp(cur[a][idx]) < 0 {
+			return errors.WithStack(ReserveViolatedError{Asset: a, Balance: bal, Reserve: reserve})
+		}
+	}
 	return nil
 }
 
@@ -442,6 +906,37 @@ func (c *Channel) validTwoPartyUpdateState(next *channel.State) error {
 	return c.validTwoPartyUpdate(up, c.machine.Idx())
 }
 
+// Payload returns the application payload attached to the update, or nil if
+// there is none. It is not part of the state and not signed.
+func (u ChannelUpdate) Payload() []byte {
+	return u.payload
+}
+
+// Steps returns the intermediate states of a batch update proposed with
+// Channel.UpdateBatch, in order. The last state of the batch is State. For
+// single updates, Steps returns nil. The states must not be modified.
+func (u ChannelUpdate) Steps() []*channel.State {
+	return u.steps
+}
+
+// IsPayment returns whether the update only changes the balances of prev. That
+// is, the channel ID, app, app data, assets and locked funds are unchanged and
+// neither prev nor the new state are final. It does not check the validity of
+// the update, e.g., that the balances are conserved.
+func (u ChannelUpdate) IsPayment(prev *channel.State) bool {
+	next := u.State
+	if next.ID != prev.ID || prev.IsFinal || next.IsFinal {
+		return false
+	}
+	if channel.AppShouldEqual(prev.App, next.App) != nil ||
+		channel.AssetsAssertEqual(prev.Assets, next.Assets) != nil ||
+		!channel.SubAllocsEqual(prev.Locked, next.Locked) {
+		return false
+	}
+	equalData, err := perunio.EqualEncoding(prev.Data, next.Data)
+	return err == nil && equalData
+}
+
 func makeChannelUpdate(next *channel.State, actor channel.Index) ChannelUpdate {
 	return ChannelUpdate{
 		State:    next,
@@ -15,13 +15,29 @@
 package client
 
 import (
+	"context"
+	"fmt"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	chtest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/log"
+	plogrus "perun.network/go-perun/log/logrus"
+	clocktest "perun.network/go-perun/pkg/clock/test"
+	pkgtest "perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wallet"
+	wallettest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
 )
 
 func TestUpdateResponder_Accept_NilArgs(t *testing.T) {
@@ -59,3 +75,201 @@ func TestRequestTimedOutError(t *testing.T) {
 		require.True(t, gotRequestTimedOutError)
 	})
 }
+
+func TestCheckPeerDisconnected(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	peer := wiretest.NewRandomAddress(rng)
+
+	t.Run("peer_unreachable", func(t *testing.T) {
+		err := checkPeerDisconnected(errors.WithMessage(wire.ErrPeerUnreachable, "publishing"), peer)
+		var disconnected ErrPeerDisconnected
+		require.True(t, errors.As(errors.WithMessage(err, "sending update"), &disconnected))
+		assert.Equal(t, peer, disconnected.Peer)
+	})
+
+	t.Run("other_error", func(t *testing.T) {
+		otherErr := errors.New("other error")
+		assert.Equal(t, otherErr, checkPeerDisconnected(otherErr, peer))
+		assert.NoError(t, checkPeerDisconnected(nil, peer))
+	})
+}
+
+func TestChannel_collectUpdateSigs(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	const n = 3
+	accs := make([]wallet.Account, n)
+	parts := make([]wallet.Address, n)
+	peers := make([]wire.Address, n)
+	for i := range accs {
+		accs[i] = wallettest.NewRandomAccount(rng)
+		parts[i] = accs[i].Address()
+		peers[i] = wiretest.NewRandomAddress(rng)
+	}
+	params := chtest.NewRandomParams(rng, chtest.WithParts(parts...), chtest.WithoutApp())
+
+	// newChannel returns a channel of participant 0 with the initial state
+	// staged, a receiver for update responses and the participants' signatures.
+	newChannel := func(t *testing.T) (*Channel, *channelMsgRecv, []wallet.Sig) {
+		m, err := channel.NewStateMachine(accs[0], *params)
+		require.NoError(t, err)
+		alloc := chtest.NewRandomAllocation(rng, chtest.WithNumParts(n))
+		require.NoError(t, m.Init(*alloc, channel.NoData()))
+		sigs := make([]wallet.Sig, n)
+		for i, acc := range accs {
+			sigs[i], err = channel.Sign(acc, params, m.StagingState())
+			require.NoError(t, err)
+		}
+		require.NoError(t, m.AddSig(0, sigs[0]))
+
+		r := wire.NewRelay()
+		conn := &channelConn{OnCloser: r, r: r, peers: peers, log: log.Get()}
+		ch := &Channel{
+			OnCloser:  conn,
+			Embedding: log.MakeEmbedding(log.Get()),
+			conn:      conn,
+			machine:   persistence.FromStateMachine(m, persistence.NonPersistRestorer),
+			client:    &Client{clock: clocktest.NewMockClock(time.Now()), cfg: DefaultConfig()},
+		}
+		resRecv, err := conn.NewUpdateResRecv(0)
+		require.NoError(t, err)
+		return ch, resRecv, sigs
+	}
+	acc := func(ch *Channel, from int, sig wallet.Sig) *wire.Envelope {
+		return &wire.Envelope{
+			Sender: peers[from],
+			Msg:    &msgChannelUpdateAcc{ChannelID: ch.ID(), Version: 0, Sig: sig},
+		}
+	}
+
+	t.Run("all accept", func(t *testing.T) {
+		ch, resRecv, sigs := newChannel(t)
+		ch.conn.r.Put(acc(ch, 2, sigs[2]))
+		ch.conn.r.Put(acc(ch, 2, sigs[2])) // duplicates are ignored
+		ch.conn.r.Put(acc(ch, 1, sigs[1]))
+		require.NoError(t, ch.collectUpdateSigs(context.Background(), resRecv, 0))
+		assert.NoError(t, ch.machine.EnableInit(context.Background()))
+	})
+
+	t.Run("one rejects", func(t *testing.T) {
+		ch, resRecv, sigs := newChannel(t)
+		ch.conn.r.Put(acc(ch, 1, sigs[1]))
+		ch.conn.r.Put(&wire.Envelope{
+			Sender: peers[2],
+			Msg:    &msgChannelUpdateRej{ChannelID: ch.ID(), Version: 0, Reason: "no"},
+		})
+		err := ch.collectUpdateSigs(context.Background(), resRecv, 0)
+		assert.True(t, errors.As(err, new(PeerRejectedError)))
+	})
+
+	t.Run("one times out", func(t *testing.T) {
+		ch, resRecv, sigs := newChannel(t)
+		ch.conn.r.Put(acc(ch, 2, sigs[2]))
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err := ch.collectUpdateSigs(ctx, resRecv, 0)
+		assert.True(t, errors.As(err, new(RequestTimedOutError)))
+		assert.Contains(t, err.Error(), "[1]")
+	})
+
+	t.Run("response timeout", func(t *testing.T) {
+		ch, resRecv, sigs := newChannel(t)
+		clk := ch.client.clock.(*clocktest.MockClock)
+		ch.client.cfg.UpdateResponseTimeout = time.Minute
+		ch.conn.r.Put(acc(ch, 1, sigs[1]))
+		done := make(chan error, 1)
+		go func() { done <- ch.collectUpdateSigs(context.Background(), resRecv, 0) }()
+		for clk.NumTimers() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		clk.Advance(time.Minute)
+		err := <-done
+		assert.True(t, errors.As(err, new(RequestTimedOutError)), "expected RequestTimedOutError, got %v", err)
+		assert.Contains(t, err.Error(), "[2]")
+	})
+
+	t.Run("no response timeout by default", func(t *testing.T) {
+		ch, resRecv, _ := newChannel(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err := ch.collectUpdateSigs(ctx, resRecv, 0)
+		assert.True(t, errors.As(err, new(RequestTimedOutError)), "expected RequestTimedOutError, got %v", err)
+		assert.Zero(t, ch.client.clock.(*clocktest.MockClock).NumTimers(), "no response timer should be started")
+	})
+
+	t.Run("one disconnects", func(t *testing.T) {
+		ch, resRecv, sigs := newChannel(t)
+		lost := make(chan struct{})
+		resRecv.dn = peerDisconnectNotifier{peer: peers[1], lost: lost}
+		ch.conn.r.Put(acc(ch, 2, sigs[2]))
+		close(lost)
+		err := ch.collectUpdateSigs(context.Background(), resRecv, 0)
+		var disconnected ErrPeerDisconnected
+		require.True(t, errors.As(err, &disconnected), "expected ErrPeerDisconnected, got %v", err)
+		assert.Equal(t, peers[1], disconnected.Peer)
+	})
+
+	t.Run("logs responses", func(t *testing.T) {
+		ch, resRecv, sigs := newChannel(t)
+		logger, hook := logrustest.NewNullLogger()
+		logger.SetLevel(logrus.DebugLevel)
+		ch.Embedding = log.MakeEmbedding(plogrus.FromLogrus(logger))
+		ch.conn.r.Put(acc(ch, 1, sigs[1]))
+		ch.conn.r.Put(acc(ch, 2, sigs[2]))
+		require.NoError(t, ch.collectUpdateSigs(context.Background(), resRecv, 0))
+
+		entries := hook.AllEntries()
+		require.Len(t, entries, 2)
+		for i, e := range entries {
+			assert.Equal(t, "Update response received.", e.Message)
+			assert.Equal(t, fmt.Sprintf("%x:0", ch.ID()), e.Data["update"])
+			assert.Equal(t, channel.Index(i+1), e.Data["peerIdx"])
+			assert.Equal(t, true, e.Data["accepted"])
+			assert.Equal(t, time.Duration(0).String(), fmt.Sprint(e.Data["latency"]), "mock clock did not advance")
+		}
+	})
+}
+
+// peerDisconnectNotifier reports the loss of the connection to peer once lost
+// is closed.
+type peerDisconnectNotifier struct {
+	peer wire.Address
+	lost chan struct{}
+}
+
+func (n peerDisconnectNotifier) Disconnected(peer wire.Address) <-chan struct{} {
+	if peer.Equals(n.peer) {
+		return n.lost
+	}
+	return nil
+}
+
+func TestChannelUpdate_IsPayment(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	prev := chtest.NewRandomState(rng, chtest.WithIsFinal(false), chtest.WithNumLocked(1))
+
+	tests := []struct {
+		name    string
+		modify  func(*channel.State)
+		payment bool
+	}{
+		{"balances", func(s *channel.State) {
+			bal := s.Balances[0][0]
+			bal.Add(bal, big.NewInt(1))
+		}, true},
+		{"id", func(s *channel.State) { s.ID = chtest.NewRandomChannelID(rng) }, false},
+		{"app", func(s *channel.State) { s.App = chtest.NewRandomApp(rng) }, false},
+		{"data", func(s *channel.State) { s.Data = chtest.NewRandomData(rng) }, false},
+		{"assets", func(s *channel.State) { s.Assets[0] = chtest.NewRandomAsset(rng) }, false},
+		{"locked", func(s *channel.State) { s.Locked = nil }, false},
+		{"final", func(s *channel.State) { s.IsFinal = true }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := prev.Clone()
+			next.Version++
+			tt.modify(next)
+			up := makeChannelUpdate(next, 0)
+			assert.Equal(t, tt.payment, up.IsPayment(prev))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+// transferStep returns an update function that sends amount of the first asset
+// from the first to the second participant.
+func transferStep(amount int64) func(*channel.State) error {
+	return func(s *channel.State) error {
+		s.Balances[0][0].Sub(s.Balances[0][0], big.NewInt(amount))
+		s.Balances[0][1].Add(s.Balances[0][1], big.NewInt(amount))
+		return nil
+	}
+}
+
+func TestChannel_UpdateBatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	steps := make(chan []*channel.State, 1)
+	var updateHandlerBob client.UpdateHandlerFunc = func(_ *channel.State, up client.ChannelUpdate, ur *client.UpdateResponder) {
+		steps <- up.Steps()
+		assert.NoError(t, ur.Accept(ctx))
+	}
+	chAlice, chBob := openChannel(ctx, t, rng, clients[0], clients[1], updateHandlerBob)
+
+	// Bob never wants to have more than 15.
+	const reason = "balance of Bob too high"
+	chBob.SetUpdateInvariant(func(_, next *channel.State) error {
+		if next.Balances[0][1].Cmp(big.NewInt(15)) > 0 {
+			return errors.New(reason)
+		}
+		return nil
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		prev := chAlice.State().Clone()
+		require.NoError(t, chAlice.UpdateBatch(ctx, []func(*channel.State) error{
+			transferStep(1), transferStep(2), transferStep(-1),
+		}))
+		bobSteps := <-steps
+		require.Len(t, bobSteps, 2, "intermediate states")
+		assert.Equal(t, prev.Version+1, bobSteps[0].Version)
+		assert.Equal(t, prev.Version+2, bobSteps[1].Version)
+
+		for _, ch := range []*client.Channel{chAlice, chBob} {
+			assert.Equal(t, prev.Version+3, ch.State().Version)
+			assert.Equal(t, big.NewInt(8), ch.State().Balances[0][0])
+			assert.Equal(t, big.NewInt(12), ch.State().Balances[0][1])
+		}
+	})
+
+	t.Run("rejected intermediate step", func(t *testing.T) {
+		prevAlice, prevBob := chAlice.State().Clone(), chBob.State().Clone()
+		// Only the second step violates Bob's invariant.
+		err := chAlice.UpdateBatch(ctx, []func(*channel.State) error{
+			transferStep(1), transferStep(5), transferStep(-5),
+		})
+		var rejErr client.PeerRejectedError
+		require.True(t, errors.As(err, &rejErr), "expected PeerRejectedError, got %v", err)
+		assert.Equal(t, reason, rejErr.Reason)
+		assert.NoError(t, prevAlice.Equal(chAlice.State()), "batch should be discarded")
+		assert.NoError(t, prevBob.Equal(chBob.State()), "batch should be discarded")
+
+		// The channel can be updated again.
+		require.NoError(t, transfer(ctx, chAlice, 1))
+		<-steps
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Error(t, chAlice.UpdateBatch(ctx, nil), "empty batch")
+		prev := chAlice.State().Clone()
+		assert.Error(t, chAlice.UpdateBatch(ctx, []func(*channel.State) error{
+			transferStep(1), transferStep(100),
+		}), "negative balance")
+		assert.NoError(t, prev.Equal(chAlice.State()))
+	})
+}
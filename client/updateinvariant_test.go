@@ -0,0 +1,58 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestChannel_SetUpdateInvariant(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	chAlice, chBob := openAcceptingChannel(ctx, t, rng, clients[0], clients[1])
+
+	// Bob never wants to have less than 10.
+	const reason = "balance of Bob too low"
+	chBob.SetUpdateInvariant(func(_, next *channel.State) error {
+		if next.Balances[0][1].Cmp(big.NewInt(10)) < 0 {
+			return errors.New(reason)
+		}
+		return nil
+	})
+
+	require.NoError(t, transfer(ctx, chAlice, 5))
+	err := transfer(ctx, chAlice, -10)
+	var rejErr client.PeerRejectedError
+	require.True(t, errors.As(err, &rejErr), "expected PeerRejectedError, got %v", err)
+	assert.Equal(t, reason, rejErr.Reason)
+	assert.Equal(t, uint64(1), chBob.State().Version)
+
+	// Removing the invariant lets the update pass again.
+	chBob.SetUpdateInvariant(nil)
+	require.NoError(t, transfer(ctx, chAlice, -10))
+}
@@ -16,6 +16,9 @@ package client
 
 import (
 	"io"
+	"math"
+
+	"github.com/pkg/errors"
 
 	"perun.network/go-perun/channel"
 	perunio "perun.network/go-perun/pkg/io"
@@ -29,6 +32,11 @@ func init() {
 			var m msgChannelUpdate
 			return &m, m.Decode(r)
 		})
+	wire.RegisterDecoder(wire.ChannelUpdateWithPayload,
+		func(r io.Reader) (wire.Msg, error) {
+			var m msgChannelUpdate
+			return &m, m.decodeWithPayload(r)
+		})
 	wire.RegisterDecoder(wire.ChannelUpdateAcc,
 		func(r io.Reader) (wire.Msg, error) {
 			var m msgChannelUpdateAcc
@@ -39,11 +47,26 @@ func init() {
 			var m msgChannelUpdateRej
 			return &m, m.Decode(r)
 		})
+	wire.RegisterDecoder(wire.ChannelUpdateRejWithCode,
+		func(r io.Reader) (wire.Msg, error) {
+			var m msgChannelUpdateRej
+			return &m, m.decodeWithCode(r)
+		})
+	wire.RegisterDecoder(wire.ChannelUpdateBatch,
+		func(r io.Reader) (wire.Msg, error) {
+			var m msgChannelUpdateBatch
+			return &m, m.Decode(r)
+		})
 	wire.RegisterDecoder(wire.VirtualChannelFundingProposal,
 		func(r io.Reader) (wire.Msg, error) {
 			var m virtualChannelFundingProposal
 			return &m, m.Decode(r)
 		})
+	wire.RegisterDecoder(wire.VirtualChannelFundingProposalWithFee,
+		func(r io.Reader) (wire.Msg, error) {
+			var m virtualChannelFundingProposal
+			return &m, m.decodeWithFee(r)
+		})
 	wire.RegisterDecoder(wire.VirtualChannelSettlementProposal,
 		func(r io.Reader) (wire.Msg, error) {
 			var m virtualChannelSettlementProposal
@@ -66,6 +89,11 @@ type (
 
 	// msgChannelUpdate is the wire message of a channel update proposal. It
 	// additionally holds the signature on the proposed state.
+	//
+	// Updates without a payload are sent as ChannelUpdate, which peers that
+	// do not know payloads can decode. Updates with a payload are sent as
+	// ChannelUpdateWithPayload. Messages that embed a msgChannelUpdate do not
+	// carry a payload.
 	msgChannelUpdate struct {
 		ChannelUpdate
 		// Sig is the signature on the proposed state by the peer sending the
@@ -73,6 +101,15 @@ type (
 		Sig wallet.Sig
 	}
 
+	// msgChannelUpdateBatch is the wire message of a batch of sequential
+	// channel updates. The embedded update proposes the last state of the
+	// batch, its Steps are the intermediate states. StepSigs holds the
+	// signatures on the intermediate states by the peer sending the batch.
+	msgChannelUpdateBatch struct {
+		msgChannelUpdate
+		StepSigs []wallet.Sig
+	}
+
 	// ChannelUpdateProposal represents an abstract update proposal message.
 	ChannelUpdateProposal interface {
 		wire.Msg
@@ -95,6 +132,10 @@ type (
 	// msgChannelUpdateRej is the wire message sent as a negative reply to a
 	// ChannelUpdate.  It references the channel ID and version and states a
 	// reason for the rejection.
+	//
+	// Rejections without a reason code are sent as ChannelUpdateRej, which
+	// peers that do not know reason codes can decode. Rejections with a reason
+	// code are sent as ChannelUpdateRejWithCode.
 	msgChannelUpdateRej struct {
 		// ChannelID is the channel ID.
 		ChannelID channel.ID
@@ -102,18 +143,25 @@ type (
 		Version uint64
 		// Reason states why the sender rejectes the proposed new state.
 		Reason string
+		// Code is the reason code of the rejection.
+		Code UpdateRejectCode
 	}
 )
 
 var (
-	_ ChannelMsg          = (*msgChannelUpdate)(nil)
-	_ channelUpdateResMsg = (*msgChannelUpdateAcc)(nil)
-	_ channelUpdateResMsg = (*msgChannelUpdateRej)(nil)
+	_ ChannelMsg            = (*msgChannelUpdate)(nil)
+	_ ChannelUpdateProposal = (*msgChannelUpdateBatch)(nil)
+	_ channelUpdateResMsg   = (*msgChannelUpdateAcc)(nil)
+	_ channelUpdateResMsg   = (*msgChannelUpdateRej)(nil)
 )
 
-// Type returns this message's type: ChannelUpdate.
-func (*msgChannelUpdate) Type() wire.Type {
-	return wire.ChannelUpdate
+// Type returns this message's type: ChannelUpdate if the update has no
+// payload and ChannelUpdateWithPayload otherwise.
+func (c *msgChannelUpdate) Type() wire.Type {
+	if len(c.payload) == 0 {
+		return wire.ChannelUpdate
+	}
+	return wire.ChannelUpdateWithPayload
 }
 
 // Type returns this message's type: ChannelUpdateAcc.
@@ -121,9 +169,13 @@ func (*msgChannelUpdateAcc) Type() wire.Type {
 	return wire.ChannelUpdateAcc
 }
 
-// Type returns this message's type: ChannelUpdateRej.
-func (*msgChannelUpdateRej) Type() wire.Type {
-	return wire.ChannelUpdateRej
+// Type returns this message's type: ChannelUpdateRej if the rejection has no
+// reason code and ChannelUpdateRejWithCode otherwise.
+func (c *msgChannelUpdateRej) Type() wire.Type {
+	if c.Code == UpdateRejectUnspecified {
+		return wire.ChannelUpdateRej
+	}
+	return wire.ChannelUpdateRejWithCode
 }
 
 // Base returns the core channel update message.
@@ -132,7 +184,13 @@ func (c *msgChannelUpdate) Base() *msgChannelUpdate {
 }
 
 func (c msgChannelUpdate) Encode(w io.Writer) error {
-	return perunio.Encode(w, c.State, c.ActorIdx, c.Sig)
+	if err := perunio.Encode(w, c.State, c.ActorIdx, c.Sig); err != nil {
+		return err
+	}
+	if len(c.payload) == 0 {
+		return nil
+	}
+	return perunio.Encode(w, string(c.payload))
 }
 
 func (c *msgChannelUpdate) Decode(r io.Reader) (err error) {
@@ -146,6 +204,77 @@ func (c *msgChannelUpdate) Decode(r io.Reader) (err error) {
 	return err
 }
 
+// decodeWithPayload decodes an update that was sent as
+// ChannelUpdateWithPayload.
+func (c *msgChannelUpdate) decodeWithPayload(r io.Reader) (err error) {
+	if err := c.Decode(r); err != nil {
+		return err
+	}
+	var payload string
+	if err := perunio.Decode(r, &payload); err != nil {
+		return err
+	}
+	c.payload = []byte(payload)
+	return nil
+}
+
+// Type returns this message's type: ChannelUpdateBatch.
+func (*msgChannelUpdateBatch) Type() wire.Type {
+	return wire.ChannelUpdateBatch
+}
+
+func (m msgChannelUpdateBatch) Encode(w io.Writer) error {
+	if len(m.steps) != len(m.StepSigs) {
+		return errors.Errorf("got %d steps but %d signatures", len(m.steps), len(m.StepSigs))
+	}
+	if len(m.steps) > math.MaxUint16 {
+		return errors.Errorf("too many steps: %d", len(m.steps))
+	}
+	if err := perunio.Encode(w, m.msgChannelUpdate, uint16(len(m.steps))); err != nil {
+		return err
+	}
+	for i, s := range m.steps {
+		if err := perunio.Encode(w, s, m.StepSigs[i]); err != nil {
+			return errors.WithMessagef(err, "encoding step %d", i)
+		}
+	}
+	return nil
+}
+
+func (m *msgChannelUpdateBatch) Decode(r io.Reader) (err error) {
+	var n uint16
+	if err := perunio.Decode(r, &m.msgChannelUpdate, &n); err != nil {
+		return err
+	}
+	if n == 0 {
+		m.steps, m.StepSigs = nil, nil
+		return nil
+	}
+	m.steps = make([]*channel.State, n)
+	m.StepSigs = make([]wallet.Sig, n)
+	for i := range m.steps {
+		m.steps[i] = new(channel.State)
+		if err := perunio.Decode(r, m.steps[i]); err != nil {
+			return errors.WithMessagef(err, "decoding step %d", i)
+		}
+		if m.StepSigs[i], err = wallet.DecodeSig(r); err != nil {
+			return errors.WithMessagef(err, "decoding signature of step %d", i)
+		}
+	}
+	return nil
+}
+
+// states returns all states of the batch, the last one being the proposed
+// state.
+func (m *msgChannelUpdateBatch) states() []*channel.State {
+	return append(append(make([]*channel.State, 0, len(m.steps)+1), m.steps...), m.State)
+}
+
+// sigs returns the sender's signatures on all states of the batch.
+func (m *msgChannelUpdateBatch) sigs() []wallet.Sig {
+	return append(append(make([]wallet.Sig, 0, len(m.StepSigs)+1), m.StepSigs...), m.Sig)
+}
+
 func (c msgChannelUpdateAcc) Encode(w io.Writer) error {
 	return perunio.Encode(w, c.ChannelID, c.Version, c.Sig)
 }
@@ -159,13 +288,26 @@ func (c *msgChannelUpdateAcc) Decode(r io.Reader) (err error) {
 }
 
 func (c msgChannelUpdateRej) Encode(w io.Writer) error {
-	return perunio.Encode(w, c.ChannelID, c.Version, c.Reason)
+	if c.Code == UpdateRejectUnspecified {
+		return perunio.Encode(w, c.ChannelID, c.Version, c.Reason)
+	}
+	return perunio.Encode(w, c.ChannelID, c.Version, c.Reason, uint8(c.Code))
 }
 
 func (c *msgChannelUpdateRej) Decode(r io.Reader) (err error) {
+	c.Code = UpdateRejectUnspecified
 	return perunio.Decode(r, &c.ChannelID, &c.Version, &c.Reason)
 }
 
+// decodeWithCode decodes a rejection that was sent as
+// ChannelUpdateRejWithCode.
+func (c *msgChannelUpdateRej) decodeWithCode(r io.Reader) (err error) {
+	var code uint8
+	err = perunio.Decode(r, &c.ChannelID, &c.Version, &c.Reason, &code)
+	c.Code = UpdateRejectCode(code)
+	return err
+}
+
 // ID returns the id of the channel this update refers to.
 func (c *msgChannelUpdate) ID() channel.ID {
 	return c.State.ID
@@ -197,10 +339,14 @@ Virtual channel
 
 type (
 	// virtualChannelFundingProposal is a channel update that proposes the funding of a virtual channel.
+	//
+	// Proposals without a fee are sent as VirtualChannelFundingProposal,
+	// proposals with a fee as VirtualChannelFundingProposalWithFee.
 	virtualChannelFundingProposal struct {
 		msgChannelUpdate
 		Initial  channel.SignedState
 		IndexMap []channel.Index
+		Fee      []channel.Bal // Fee paid to the intermediary, see VirtualChannelProposal.
 	}
 
 	// virtualChannelSettlementProposal is a channel update that proposes the settlement of a virtual channel.
@@ -210,9 +356,13 @@ type (
 	}
 )
 
-// Type returns the message type.
-func (*virtualChannelFundingProposal) Type() wire.Type {
-	return wire.VirtualChannelFundingProposal
+// Type returns the message type: VirtualChannelFundingProposal if the
+// proposal has no fee and VirtualChannelFundingProposalWithFee otherwise.
+func (m *virtualChannelFundingProposal) Type() wire.Type {
+	if isZeroFee(m.Fee) {
+		return wire.VirtualChannelFundingProposal
+	}
+	return wire.VirtualChannelFundingProposalWithFee
 }
 
 func (m virtualChannelFundingProposal) Encode(w io.Writer) (err error) {
@@ -226,7 +376,10 @@ func (m virtualChannelFundingProposal) Encode(w io.Writer) (err error) {
 		return
 	}
 
-	return wallet.EncodeSparseSigs(w, m.Initial.Sigs)
+	if err = wallet.EncodeSparseSigs(w, m.Initial.Sigs); err != nil || isZeroFee(m.Fee) {
+		return
+	}
+	return perunio.Encode(w, balsWithLen(m.Fee))
 }
 
 func (m *virtualChannelFundingProposal) Decode(r io.Reader) (err error) {
@@ -248,6 +401,15 @@ func (m *virtualChannelFundingProposal) Decode(r io.Reader) (err error) {
 	return wallet.DecodeSparseSigs(r, &m.Initial.Sigs)
 }
 
+// decodeWithFee decodes a proposal that was sent as
+// VirtualChannelFundingProposalWithFee.
+func (m *virtualChannelFundingProposal) decodeWithFee(r io.Reader) error {
+	if err := m.Decode(r); err != nil {
+		return err
+	}
+	return perunio.Decode(r, (*balsWithLen)(&m.Fee))
+}
+
 // Type returns the message type.
 func (*virtualChannelSettlementProposal) Type() wire.Type {
 	return wire.VirtualChannelSettlementProposal
@@ -15,11 +15,16 @@
 package client
 
 import (
+	"bytes"
 	"math/rand"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/channel/test"
+	perunio "perun.network/go-perun/pkg/io"
 	pkgtest "perun.network/go-perun/pkg/test"
 	"perun.network/go-perun/wallet"
 	wallettest "perun.network/go-perun/wallet/test"
@@ -30,10 +35,27 @@ func TestChannelUpdateSerialization(t *testing.T) {
 	rng := pkgtest.Prng(t)
 	for i := 0; i < 4; i++ {
 		m := newRandomMsgChannelUpdate(rng)
+		m.payload = newRandomPayload(rng)
 		wire.TestMsg(t, m)
 	}
 }
 
+func TestChannelUpdateCompatibility(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	m := newRandomMsgChannelUpdate(rng)
+
+	// Updates without a payload are encoded as before payloads existed.
+	var buf bytes.Buffer
+	require.NoError(t, wire.Encode(m, &buf))
+	var legacy bytes.Buffer
+	require.NoError(t, perunio.Encode(&legacy, byte(wire.ChannelUpdate), m.State, m.ActorIdx, m.Sig))
+	assert.Equal(t, legacy.Bytes(), buf.Bytes())
+
+	m.payload = []byte("payload")
+	assert.Equal(t, wire.ChannelUpdateWithPayload, m.Type())
+}
+
+// newRandomMsgChannelUpdate returns a random update without payload.
 func newRandomMsgChannelUpdate(rng *rand.Rand) *msgChannelUpdate {
 	state := test.NewRandomState(rng)
 	sig := newRandomSig(rng)
@@ -46,6 +68,28 @@ func newRandomMsgChannelUpdate(rng *rand.Rand) *msgChannelUpdate {
 	}
 }
 
+// newRandomPayload returns either no or a random application payload.
+func newRandomPayload(rng *rand.Rand) []byte {
+	if rng.Intn(2) == 0 {
+		return nil
+	}
+	payload := make([]byte, 1+rng.Intn(64))
+	rng.Read(payload)
+	return payload
+}
+
+func TestChannelUpdateBatchSerialization(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	for i := 0; i < 4; i++ {
+		m := &msgChannelUpdateBatch{msgChannelUpdate: *newRandomMsgChannelUpdate(rng)}
+		for j := rng.Intn(4); j > 0; j-- {
+			m.steps = append(m.steps, test.NewRandomState(rng))
+			m.StepSigs = append(m.StepSigs, newRandomSig(rng))
+		}
+		wire.TestMsg(t, m)
+	}
+}
+
 func TestSerialization_VirtualChannelFundingProposal(t *testing.T) {
 	rng := pkgtest.Prng(t)
 	for i := 0; i < 4; i++ {
@@ -60,6 +104,9 @@ func TestSerialization_VirtualChannelFundingProposal(t *testing.T) {
 			},
 			IndexMap: test.NewRandomIndexMap(rng, state.NumParts(), msgUp.State.NumParts()),
 		}
+		if i&1 == 1 {
+			m.Fee = test.NewRandomBals(rng, len(state.Assets))
+		}
 		wire.TestMsg(t, m)
 	}
 }
@@ -96,16 +143,36 @@ func TestChannelUpdateAccSerialization(t *testing.T) {
 
 func TestChannelUpdateRejSerialization(t *testing.T) {
 	rng := pkgtest.Prng(t)
-	for i := 0; i < 4; i++ {
+	for i := 0; i < 8; i++ {
 		m := &msgChannelUpdateRej{
 			ChannelID: test.NewRandomChannelID(rng),
 			Version:   uint64(rng.Int63()),
 			Reason:    newRandomString(rng, 16, 16),
+			Code:      UpdateRejectCode(i),
 		}
 		wire.TestMsg(t, m)
 	}
 }
 
+func TestChannelUpdateRejCompatibility(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	m := &msgChannelUpdateRej{
+		ChannelID: test.NewRandomChannelID(rng),
+		Version:   uint64(rng.Int63()),
+		Reason:    newRandomString(rng, 16, 16),
+	}
+
+	// Rejections without a code are encoded as before reason codes existed.
+	var buf bytes.Buffer
+	require.NoError(t, wire.Encode(m, &buf))
+	var legacy bytes.Buffer
+	require.NoError(t, perunio.Encode(&legacy, byte(wire.ChannelUpdateRej), m.ChannelID, m.Version, m.Reason))
+	assert.Equal(t, legacy.Bytes(), buf.Bytes())
+
+	m.Code = UpdateRejectBusy
+	assert.Equal(t, wire.ChannelUpdateRejWithCode, m.Type())
+}
+
 // newRandomSig generates a random account and then returns the signature on
 // some random data.
 func newRandomSig(rng *rand.Rand) wallet.Sig {
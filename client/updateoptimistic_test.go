@@ -0,0 +1,120 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+// nextTransfer returns the successor of s in which the first participant sends
+// amount of the first asset to the second.
+func nextTransfer(s *channel.State, amount int64) *channel.State {
+	next := s.Clone()
+	next.Version++
+	next.Balances[0][0].Sub(next.Balances[0][0], big.NewInt(amount))
+	next.Balances[0][1].Add(next.Balances[0][1], big.NewInt(amount))
+	return next
+}
+
+func TestChannel_UpdateOptimistic(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	chAlice, chBob := openAcceptingChannel(ctx, t, rng, clients[0], clients[1])
+
+	// Bob never wants to have more than 15.
+	const reason = "balance of Bob too high"
+	chBob.SetUpdateInvariant(func(_, next *channel.State) error {
+		if next.Balances[0][1].Cmp(big.NewInt(15)) > 0 {
+			return errors.New(reason)
+		}
+		return nil
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		next := nextTransfer(chAlice.State(), 1)
+		p, err := chAlice.UpdateOptimistic(ctx, next)
+		require.NoError(t, err)
+		assert.Equal(t, next.Version, p.Version())
+		require.NoError(t, p.Wait(ctx))
+		assert.Equal(t, next.Version, chAlice.State().Version)
+	})
+
+	t.Run("serialized", func(t *testing.T) {
+		first := nextTransfer(chAlice.State(), 1)
+		second := nextTransfer(first, 1)
+		p1, err := chAlice.UpdateOptimistic(ctx, first)
+		require.NoError(t, err)
+		// The second update waits until the first one is enabled.
+		p2, err := chAlice.UpdateOptimistic(ctx, second)
+		require.NoError(t, err)
+		select {
+		case <-p1.Done():
+		default:
+			t.Error("first update should be done before the second one is sent")
+		}
+		require.NoError(t, p1.Wait(ctx))
+		require.NoError(t, p2.Wait(ctx))
+		assert.Equal(t, second.Version, chAlice.State().Version)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		prev := chAlice.State().Clone()
+		p, err := chAlice.UpdateOptimistic(ctx, nextTransfer(prev, 5))
+		require.NoError(t, err)
+		err = p.Wait(ctx)
+		var rejErr client.PeerRejectedError
+		require.True(t, errors.As(err, &rejErr), "expected PeerRejectedError, got %v", err)
+		assert.Equal(t, reason, rejErr.Reason)
+		assert.NoError(t, prev.Equal(chAlice.State()), "rejected update should be discarded")
+
+		// The channel can be updated again.
+		require.NoError(t, transfer(ctx, chAlice, 1))
+	})
+}
+
+func TestChannel_UpdateOptimistic_TimedOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	// Bob never responds to updates.
+	var updateHandlerBob client.UpdateHandlerFunc = func(*channel.State, client.ChannelUpdate, *client.UpdateResponder) {}
+	chAlice, _ := openChannel(ctx, t, rng, clients[0], clients[1], updateHandlerBob)
+
+	prev := chAlice.State().Clone()
+	updateCtx, updateCancel := context.WithCancel(ctx)
+	p, err := chAlice.UpdateOptimistic(updateCtx, nextTransfer(prev, 1))
+	require.NoError(t, err)
+	updateCancel()
+
+	err = p.Wait(ctx)
+	var timeoutErr client.RequestTimedOutError
+	require.True(t, errors.As(err, &timeoutErr), "expected RequestTimedOutError, got %v", err)
+	assert.NoError(t, prev.Equal(chAlice.State()), "timed out update should be discarded")
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestChannel_UpdateWithPayload(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	alice, bob := clients[0], clients[1]
+
+	payloads := make(chan []byte, 1)
+	chAlice, chBob := openChannel(ctx, t, rng, alice, bob, client.UpdateHandlerFunc(
+		func(_ *channel.State, up client.ChannelUpdate, ur *client.UpdateResponder) {
+			payloads <- up.Payload()
+			assert.NoError(t, ur.Accept(ctx))
+		}))
+
+	payload := []byte("good move")
+	require.NoError(t, chAlice.UpdateByWithPayload(ctx, func(s *channel.State) error {
+		s.Balances[0][0].Sub(s.Balances[0][0], big.NewInt(1))
+		s.Balances[0][1].Add(s.Balances[0][1], big.NewInt(1))
+		return nil
+	}, payload))
+	assert.Equal(t, payload, <-payloads)
+
+	next := chAlice.State().Clone()
+	next.Version++
+	require.NoError(t, chAlice.UpdateWithPayload(ctx, next, nil))
+	assert.Nil(t, <-payloads)
+
+	// The payload is not part of the signed state.
+	assert.NoError(t, chAlice.State().Equal(chBob.State()))
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wire"
+)
+
+// UpdateQueuePolicy defines what happens to an incoming channel update if the
+// update queue of its channel is full.
+type UpdateQueuePolicy int
+
+const (
+	// BlockWhenFull makes the Client's request loop wait until the queue has
+	// space again. This stops the Client from reading further requests from
+	// the wire.
+	BlockWhenFull UpdateQueuePolicy = iota
+	// DropWhenFull drops the incoming update and logs an error. The peer will
+	// time out waiting for a response.
+	DropWhenFull
+)
+
+type (
+	// updateQueue serializes the handling of incoming updates of a channel.
+	updateQueue struct {
+		once    sync.Once
+		updates chan queuedUpdate
+	}
+
+	queuedUpdate struct {
+		pidx channel.Index
+		m    ChannelUpdateProposal
+		uh   UpdateHandler
+	}
+)
+
+// SetUpdateQueue enables per-channel queueing of incoming channel updates.
+// Updates of a channel are then handled one after another by a single
+// goroutine, with at most size updates waiting to be handled. The policy
+// decides what happens to updates that arrive while the queue is full.
+//
+// A size of zero, the default, disables queueing and handles every incoming
+// update in its own goroutine. This method is expected to be called once
+// during the setup of the client, before Handle is started, and is hence not
+// thread-safe.
+func (c *Client) SetUpdateQueue(size int, policy UpdateQueuePolicy) {
+	if size < 0 {
+		c.log.Panic("update queue size must not be negative")
+	}
+	c.updateQueueSize = size
+	c.updateQueuePolicy = policy
+}
+
+// dispatchChannelUpdate passes an incoming update to its channel's update
+// queue, if queueing is enabled and the channel is known. Otherwise, the
+// update is handled in a new goroutine.
+func (c *Client) dispatchChannelUpdate(uh UpdateHandler, p wire.Address, m ChannelUpdateProposal) {
+	ch, ok := c.channels.Get(m.Base().ID())
+	if c.updateQueueSize == 0 || !ok {
+		go c.handleChannelUpdate(uh, p, m)
+		return
+	}
+
+	updates := ch.updateQueue.get(c.updateQueueSize, ch)
+	u := queuedUpdate{pidx: ch.Idx() ^ 1, m: m, uh: uh}
+	if c.updateQueuePolicy == DropWhenFull {
+		select {
+		case updates <- u:
+		default:
+			ch.Log().WithField("peer", p).Errorf(
+				"Dropping update to version %d: update queue full", m.Base().State.Version)
+		}
+		return
+	}
+
+	select {
+	case updates <- u:
+	case <-ch.Ctx().Done():
+	case <-c.Ctx().Done():
+	}
+}
+
+// get returns the queue's channel, starting the goroutine that handles the
+// queued updates of ch on first use.
+func (q *updateQueue) get(size int, ch *Channel) chan<- queuedUpdate {
+	q.once.Do(func() {
+		q.updates = make(chan queuedUpdate, size)
+		go func() {
+			for {
+				select {
+				case u := <-q.updates:
+					ch.handleUpdateReq(u.pidx, u.m, u.uh)
+				case <-ch.Ctx().Done():
+					return
+				}
+			}
+		}()
+	})
+	return q.updates
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "fmt"
+
+// UpdateRejectCode states why a channel update was rejected. It is sent to
+// the proposer of the update along with the free-text rejection reason, so
+// that the proposer can react to a rejection programmatically, e.g., by
+// retrying the update later.
+type UpdateRejectCode uint8
+
+// The UpdateRejectCodes that are known to the client. Further codes might be
+// received from peers that use a newer protocol version.
+const (
+	// UpdateRejectUnspecified is used if no reason code was given. It is also
+	// the code of rejections by peers that do not send reason codes.
+	UpdateRejectUnspecified UpdateRejectCode = iota
+	// UpdateRejectInsufficientFunds indicates that the update would leave a
+	// participant with less funds than it requires, e.g., below its reserve.
+	UpdateRejectInsufficientFunds
+	// UpdateRejectAppLogic indicates that the update violates the logic of the
+	// channel's application or the peer's update invariant.
+	UpdateRejectAppLogic
+	// UpdateRejectBusy indicates that the peer cannot process the update at
+	// the moment. The update may be retried later.
+	UpdateRejectBusy
+	// UpdateRejectInvalid indicates that the update is invalid, e.g., that it
+	// has an invalid signature or is not a valid transition.
+	UpdateRejectInvalid
+)
+
+// String returns a human-readable representation of the code.
+func (c UpdateRejectCode) String() string {
+	switch c {
+	case UpdateRejectUnspecified:
+		return "unspecified"
+	case UpdateRejectInsufficientFunds:
+		return "insufficient funds"
+	case UpdateRejectAppLogic:
+		return "app logic"
+	case UpdateRejectBusy:
+		return "busy"
+	case UpdateRejectInvalid:
+		return "invalid update"
+	default:
+		return fmt.Sprintf("unknown code %d", uint8(c))
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestUpdateResponder_RejectWithCode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	const reason = "try again later"
+	errs := make(chan error, 1)
+	var updateHandlerBob client.UpdateHandlerFunc = func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+		errs <- ur.RejectWithCode(ctx, client.UpdateRejectBusy, reason)
+	}
+	chAlice, chBob := openChannel(ctx, t, rng, clients[0], clients[1], updateHandlerBob)
+
+	t.Run("RejectWithCode", func(t *testing.T) {
+		err := transfer(ctx, chAlice, 1)
+		require.NoError(t, <-errs)
+
+		var rejErr client.PeerRejectedError
+		require.True(t, errors.As(err, &rejErr))
+		assert.Equal(t, client.UpdateRejectBusy, rejErr.UpdateCode)
+		assert.Equal(t, client.ProposalRejectUnspecified, rejErr.Code)
+		assert.Equal(t, reason, rejErr.Reason)
+		assert.Equal(t, chAlice.ID(), rejErr.ChannelID)
+		assert.Equal(t, chAlice.State().Version+1, rejErr.Version)
+		assert.Contains(t, err.Error(), client.UpdateRejectBusy.String())
+		assert.Contains(t, err.Error(), fmt.Sprintf("%x", chAlice.ID()))
+	})
+
+	t.Run("invariant", func(t *testing.T) {
+		chBob.SetUpdateInvariant(func(_, next *channel.State) error {
+			if next.Balances[0][1].Cmp(big.NewInt(10)) > 0 {
+				return errors.New("no payments to Bob")
+			}
+			return nil
+		})
+		err := transfer(ctx, chAlice, 1)
+
+		var rejErr client.PeerRejectedError
+		require.True(t, errors.As(err, &rejErr))
+		assert.Equal(t, client.UpdateRejectAppLogic, rejErr.UpdateCode)
+	})
+}
+
+func TestUpdateRejectCode_String(t *testing.T) {
+	assert.Equal(t, "unspecified", client.UpdateRejectUnspecified.String())
+	assert.Equal(t, "busy", client.UpdateRejectBusy.String())
+	assert.Equal(t, "unknown code 200", client.UpdateRejectCode(200).String())
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+)
+
+// UpgradeApp moves all free funds of the channel into a new sub-channel that
+// runs the given app, starting with initData. This is the way to switch a
+// running channel to another app, e.g., from NoApp to a real app, without
+// closing it on-chain.
+//
+// The app of a channel cannot be changed in place because it is part of the
+// channel parameters and thereby of the channel ID that the adjudicator
+// knows. Instead, the peers have to accept a sub-channel proposal for the new
+// app, in which the initial state is validated by the new app's ValidInit.
+// The sub-channel has the same participants and challenge duration as the
+// channel. Disputes and settlement work as for any other sub-channel, so the
+// upgrade is also compatible with the on-chain contracts.
+//
+// Returns the sub-channel running the new app. Returns an error if the channel
+// has no free funds or if the proposal is rejected.
+func (c *Channel) UpgradeApp(ctx context.Context, app channel.App, initData channel.Data) (*Channel, error) {
+	if c.watchOnly {
+		return nil, errors.WithStack(ErrWatchOnly)
+	}
+
+	state := c.State()
+	if isZero(state.Balances) {
+		return nil, errors.New("channel has no free funds to upgrade")
+	}
+	alloc := &channel.Allocation{
+		Assets:   state.Assets,
+		Balances: state.Balances.Clone(),
+	}
+
+	prop, err := NewSubChannelProposal(
+		c.ID(),
+		c.Params().ChallengeDuration,
+		alloc,
+		WithApp(app, initData),
+		c.client.RandomNonce(),
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating sub-channel proposal")
+	}
+	res, err := c.client.ProposeChannel(ctx, prop)
+	if res == nil {
+		return nil, errors.WithMessage(err, "proposing sub-channel")
+	}
+	return res.Channel, errors.WithMessage(err, "proposing sub-channel")
+}
+
+// isZero returns whether all balances are zero.
+func isZero(bals channel.Balances) bool {
+	for _, assetBals := range bals {
+		for _, bal := range assetBals {
+			if bal.Sign() != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
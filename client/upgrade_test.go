@@ -0,0 +1,99 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/apps/payment"
+	"perun.network/go-perun/channel"
+	chtest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+func TestChannel_UpgradeApp(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+	clients := NewClients(rng, []string{"Alice", "Bob"}, t)
+	alice, bob := clients[0], clients[1]
+
+	errs := make(chan error, 10)
+	bobChs := make(chan *client.Channel, 2)
+	var proposalHandlerBob client.ProposalHandlerFunc = func(cp client.ChannelProposal, pr *client.ProposalResponder) {
+		var acc client.ChannelProposalAccept
+		switch cp := cp.(type) {
+		case *client.LedgerChannelProposal:
+			acc = cp.Accept(bob.Identity.Address(), client.WithRandomNonce())
+		case *client.SubChannelProposal:
+			acc = cp.Accept(client.WithRandomNonce())
+		default:
+			errs <- errors.Errorf("invalid channel proposal: %v", cp)
+			return
+		}
+		// Accepting a sub-channel blocks until its funding update is received,
+		// which is handled by the same handler routine.
+		go func() {
+			ch, err := pr.Accept(ctx, acc)
+			if err != nil {
+				errs <- errors.WithMessage(err, "accepting channel proposal")
+				return
+			}
+			bobChs <- ch
+		}()
+	}
+	var updateHandlerBob client.UpdateHandlerFunc = func(_ *channel.State, _ client.ChannelUpdate, ur *client.UpdateResponder) {
+		if err := ur.Accept(ctx); err != nil {
+			errs <- errors.WithMessage(err, "accepting channel update")
+		}
+	}
+	go bob.Handle(proposalHandlerBob, updateHandlerBob)
+
+	prop := newLedgerChannelProposal(t, rng, alice, bob)
+	initBals := prop.InitBals.Clone().Balances
+	res, err := alice.ProposeChannel(ctx, prop)
+	require.NoError(t, err)
+	ch := res.Channel
+	assert.Equal(t, ch.ID(), res.ID)
+	assert.Equal(t, ch.Params(), res.Params)
+	assert.Nil(t, res.FundingIDs, "mock funder derives no funding IDs")
+	require.True(t, channel.IsNoApp(ch.Params().App))
+	assert.Equal(t, []channel.Bal{big.NewInt(0)}, ch.LockedFunds())
+	select {
+	case <-bobChs:
+	case err := <-errs:
+		t.Fatal(err)
+	}
+
+	app, data := chtest.NewRandomAppAndData(rng, chtest.WithAppRandomizer(new(payment.Randomizer)))
+	sub, err := ch.UpgradeApp(ctx, app, data)
+	require.NoError(t, err)
+	assert.True(t, sub.IsSubChannel())
+	assert.Equal(t, app.Def(), sub.Params().App.Def())
+	assert.NoError(t, sub.State().Balances.AssertEqual(initBals))
+	assert.True(t, ch.State().Balances.Sum()[0].Sign() == 0, "all funds should be moved")
+	assert.Equal(t, initBals.Sum(), ch.LockedFunds())
+
+	// Nothing left to upgrade.
+	_, err = ch.UpgradeApp(ctx, app, data)
+	assert.Error(t, err)
+}
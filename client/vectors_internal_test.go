@@ -0,0 +1,116 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"flag"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/test"
+	perunio "perun.network/go-perun/pkg/io"
+	iotest "perun.network/go-perun/pkg/io/test"
+	"perun.network/go-perun/wallet"
+	wallettest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
+)
+
+var updateVectors = flag.Bool("update-vectors", false, "regenerate the golden conformance test vectors")
+
+// vectorSeed is the seed of the golden conformance test vectors.
+const vectorSeed = 20210805
+
+// TestConformanceVectors checks that the encodings of the wire messages and
+// channel types did not change. The vectors in testdata can be used by other
+// implementations to check their compatibility with go-perun. They are
+// regenerated by running the test with -update-vectors.
+func TestConformanceVectors(t *testing.T) {
+	vectors := conformanceVectors(t, vectorSeed)
+	require.Equal(t, vectors, conformanceVectors(t, vectorSeed), "vectors must be deterministic")
+	iotest.GoldenVectorTest(t, filepath.Join("testdata", "vectors.json"), *updateVectors, vectors)
+}
+
+// conformanceVectors deterministically generates wire messages and channel
+// types from the given seed and returns their encodings as test vectors.
+func conformanceVectors(t *testing.T, seed int64) []iotest.Vector {
+	t.Helper()
+	rng := rand.New(rand.NewSource(seed))
+	params, state := test.NewRandomParamsAndState(rng)
+	prop := NewRandomLedgerChannelProposal(rng)
+
+	values := []struct {
+		name string
+		v    interface{}
+	}{
+		{"channel.Params", params},
+		{"channel.State", state},
+		{"msgChannelUpdate", &msgChannelUpdate{
+			ChannelUpdate: ChannelUpdate{
+				State:    state,
+				ActorIdx: channel.Index(rng.Intn(state.NumParts())),
+			},
+			Sig: newVectorSig(rng),
+		}},
+		{"msgChannelUpdateAcc", &msgChannelUpdateAcc{
+			ChannelID: state.ID,
+			Version:   state.Version,
+			Sig:       newVectorSig(rng),
+		}},
+		{"msgChannelUpdateRej", &msgChannelUpdateRej{
+			ChannelID: state.ID,
+			Version:   state.Version,
+			Reason:    "rejected",
+		}},
+		{"LedgerChannelProposal", prop},
+		{"LedgerChannelProposalAcc", prop.Accept(wallettest.NewRandomAddress(rng), WithNonceFrom(rng))},
+		{"ChannelProposalRej", &ChannelProposalRej{
+			ProposalID: prop.ProposalID(),
+			Reason:     "rejected",
+		}},
+		{"ChannelProposalRejWithCode", &ChannelProposalRej{
+			ProposalID: prop.ProposalID(),
+			Code:       ProposalRejectCode(1),
+			Reason:     "rejected",
+		}},
+	}
+
+	vectors := make([]iotest.Vector, len(values))
+	for i, val := range values {
+		var err error
+		switch v := val.v.(type) {
+		case wire.Msg:
+			vectors[i], err = wiretest.NewMsgVector(val.name, v)
+		case perunio.Encoder:
+			vectors[i], err = iotest.NewVector(val.name, v)
+		default:
+			t.Fatalf("cannot encode %T", v)
+		}
+		require.NoError(t, err, "encoding %s", val.name)
+	}
+	return vectors
+}
+
+// newVectorSig returns random signature bytes. Real signatures are not
+// reproducible because signing reads from the system's randomness.
+func newVectorSig(rng *rand.Rand) wallet.Sig {
+	sig := make(wallet.Sig, 64)
+	rng.Read(sig)
+	return sig
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+)
+
+// DepositReader can optionally be implemented by a channel.Funder to read the
+// on-chain deposits of a channel's participants. It is used by
+// Channel.VerifyFunding.
+type DepositReader interface {
+	// Deposits returns the deposits of the participants of the channel with
+	// the given parameters, indexed by asset and participant.
+	Deposits(ctx context.Context, params *channel.Params, assets []channel.Asset) (channel.Balances, error)
+}
+
+// VerifyFunding reads the on-chain deposits of all participants and checks
+// that they cover the funds of the current state, including the funds locked
+// in sub-channels. A cautious client calls it before sending the first update.
+//
+// Only ledger channels are funded on-chain. The client's funder must implement
+// DepositReader.
+//
+// Returns FundingShortfallError if the deposits of any asset fall short.
+func (c *Channel) VerifyFunding(ctx context.Context) error {
+	if !c.IsLedgerChannel() {
+		return errors.New("only ledger channels are funded on-chain")
+	}
+	reader, ok := c.client.funder.(DepositReader)
+	if !ok {
+		return errors.New("funder cannot read deposits")
+	}
+
+	state := c.State()
+	deposits, err := reader.Deposits(ctx, c.Params(), state.Assets)
+	if err != nil {
+		return errors.WithMessage(err, "reading deposits")
+	}
+	if len(deposits) != len(state.Assets) {
+		return errors.Errorf("got deposits of %d assets, expected %d", len(deposits), len(state.Assets))
+	}
+
+	var shortfalls []AssetShortfall
+	for a, required := range state.Sum() {
+		if total := deposits[a : a+1].Sum()[0]; total.Cmp(required) < 0 {
+			shortfalls = append(shortfalls, AssetShortfall{
+				Asset:    a,
+				Deposits: deposits[a],
+				Required: required,
+			})
+		}
+	}
+	if len(shortfalls) > 0 {
+		return errors.WithStack(FundingShortfallError{Shortfalls: shortfalls})
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+// depositFunder is a funder that reports fixed deposits.
+type depositFunder struct {
+	channel.Funder
+	deposits channel.Balances
+}
+
+func (f *depositFunder) Deposits(context.Context, *channel.Params, []channel.Asset) (channel.Balances, error) {
+	return f.deposits, nil
+}
+
+func TestChannel_VerifyFunding(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	setups := NewSetups(rng, []string{"Alice", "Bob"})
+	funder := &depositFunder{Funder: setups[0].Funder}
+	setups[0].Funder = funder
+	clients := newClientsFromSetups(rng, setups, t)
+	alice, bob := clients[0], clients[1]
+	chAlice, chBob := openAcceptingChannel(ctx, t, rng, alice, bob)
+
+	assert.Error(t, chBob.VerifyFunding(ctx), "funder without DepositReader")
+
+	funder.deposits = channel.Balances{{big.NewInt(10), big.NewInt(10)}}
+	assert.NoError(t, chAlice.VerifyFunding(ctx))
+
+	funder.deposits = channel.Balances{{big.NewInt(20), big.NewInt(0)}}
+	assert.NoError(t, chAlice.VerifyFunding(ctx), "only the total deposit matters")
+
+	funder.deposits = channel.Balances{{big.NewInt(10), big.NewInt(7)}}
+	err := chAlice.VerifyFunding(ctx)
+	shortErr, ok := errors.Cause(err).(client.FundingShortfallError)
+	require.True(t, ok, "expected FundingShortfallError, got %v", err)
+	require.Len(t, shortErr.Shortfalls, 1)
+	assert.Equal(t, 0, shortErr.Shortfalls[0].Asset)
+	assert.Equal(t, funder.deposits[0], shortErr.Shortfalls[0].Deposits)
+	assert.Zero(t, shortErr.Shortfalls[0].Required.Cmp(big.NewInt(20)))
+}
@@ -17,7 +17,6 @@ package client
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/pkg/errors"
 	"perun.network/go-perun/channel"
@@ -39,8 +38,17 @@ func (c *Client) fundVirtualChannel(ctx context.Context, virtual *Channel, prop
 		return errors.New("referenced parent channel not found")
 	}
 
+	// The proposer pays the proposed fee, the others pay the fee of their own
+	// parent channel.
+	fee := prop.Fee
+	if virtual.Idx() != proposerIdx {
+		var err error
+		if fee, err = c.virtualChannelFee(parent, *prop.InitBals); err != nil {
+			return errors.WithMessage(err, "determining fee")
+		}
+	}
 	indexMap := prop.IndexMaps[virtual.Idx()]
-	err := parent.proposeVirtualChannelFunding(ctx, virtual, indexMap)
+	err := parent.proposeVirtualChannelFunding(ctx, virtual, indexMap, fee)
 	if err != nil {
 		return errors.WithMessage(err, "proposing channel funding")
 	}
@@ -48,35 +56,42 @@ func (c *Client) fundVirtualChannel(ctx context.Context, virtual *Channel, prop
 	return c.completeFunding(ctx, virtual)
 }
 
-func (c *Channel) proposeVirtualChannelFunding(ctx context.Context, virtual *Channel, indexMap []channel.Index) error {
+func (c *Channel) proposeVirtualChannelFunding(ctx context.Context, virtual *Channel, indexMap []channel.Index, fee []channel.Bal) error {
 	// We assume that the channel is locked.
 
 	state := c.state().Clone()
 	state.Version++
+	initial := channel.SignedState{
+		Params: virtual.Params(),
+		State:  virtual.State(),
+		Sigs:   virtual.machine.CurrentTX().Sigs,
+	}
 
 	// Deposit initial balances into sub-allocation
 	balances := virtual.translateBalances(indexMap)
 	state.Allocation.Balances = state.Allocation.Balances.Sub(balances)
 	state.AddSubAlloc(*channel.NewSubAlloc(virtual.ID(), balances.Sum(), indexMap))
 
-	err := c.updateGeneric(ctx, state, func(mcu *msgChannelUpdate) wire.Msg {
+	// Pay the intermediary's fee for this parent channel.
+	fee, err := normalizeFee(fee, len(state.Assets))
+	if err != nil {
+		return errors.WithMessage(err, "invalid fee")
+	}
+	if err := payFee(state.Allocation.Balances, c.Idx(), 1-c.Idx(), fee); err != nil {
+		return errors.WithMessage(err, "paying fee")
+	}
+
+	err = c.updateGeneric(ctx, state, func(mcu *msgChannelUpdate) wire.Msg {
 		return &virtualChannelFundingProposal{
 			msgChannelUpdate: *mcu,
-			Initial: channel.SignedState{
-				Params: virtual.Params(),
-				State:  virtual.State(),
-				Sigs:   virtual.machine.CurrentTX().Sigs,
-			},
-			IndexMap: indexMap,
+			Initial:          initial,
+			IndexMap:         indexMap,
+			Fee:              fee,
 		}
 	})
 	return err
 }
 
-const responseTimeout = 10 * time.Second          // How long we wait until the proposal response must be transmitted.
-const virtualFundingTimeout = 10 * time.Second    // How long we wait for a matching funding proposal.
-const virtualSettlementTimeout = 10 * time.Second // How long we wait for a matching settlement proposal.
-
 func (c *Client) handleVirtualChannelFundingProposal(
 	ch *Channel,
 	prop *virtualChannelFundingProposal,
@@ -84,15 +99,17 @@ func (c *Client) handleVirtualChannelFundingProposal(
 ) {
 	err := c.validateVirtualChannelFundingProposal(ch, prop)
 	if err != nil {
-		c.rejectProposal(responder, err.Error())
+		c.rejectProposal(responder, UpdateRejectUnspecified, err.Error())
+		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Ctx(), virtualFundingTimeout)
+	ctx, cancel := c.timeoutCtx(c.cfg.VirtualFundingTimeout)
 	defer cancel()
 
 	err = c.fundingWatcher.Await(ctx, prop)
 	if err != nil {
-		c.rejectProposal(responder, err.Error())
+		c.rejectProposal(responder, UpdateRejectUnspecified, err.Error())
+		return
 	}
 
 	c.acceptProposal(responder)
@@ -113,6 +130,8 @@ func (c *Channel) watchVirtual() error {
 			log.Warn(err)
 		}
 	}()
+	// nolint:errcheck,gosec
+	c.OnCloseAlways(func() { sub.Close() })
 
 	// Wait for state changed event
 	for e := sub.Next(); e != nil; e = sub.Next() {
@@ -287,6 +306,20 @@ func (c *Client) validateVirtualChannelFundingProposal(
 		return errors.WithMessage(err, "insufficient funds")
 	}
 
+	// Assert that the proposed fee is our fee and that, besides locking the
+	// funds, the proposer pays exactly that fee.
+	fee, err := c.virtualChannelFee(ch, prop.Initial.State.Allocation)
+	if err != nil {
+		return errors.WithMessage(err, "determining fee")
+	}
+	if !equalFee(fee, prop.Fee) {
+		return errors.Errorf("proposed fee %v, expected %v", prop.Fee, fee)
+	}
+	locked := ch.state().Balances.Sub(virtual)
+	if err := assertFeePaid(locked, prop.State.Balances, 1-ch.Idx(), ch.Idx(), fee); err != nil {
+		return errors.WithMessage(err, "invalid balances")
+	}
+
 	return nil
 }
 
@@ -342,7 +375,11 @@ func (c *Client) matchFundingProposal(ctx context.Context, a, b interface{}) boo
 		return false
 	}
 
+	if !c.watchers.add() {
+		return false
+	}
 	go func() {
+		defer c.watchers.done()
 		err := virtual.watchVirtual()
 		c.log.Debugf("channel %v: watcher stopped: %v", virtual.ID(), err)
 	}()
@@ -376,11 +413,15 @@ func (c *Client) gatherChannels(props ...*virtualChannelFundingProposal) ([]*Cha
 func (c *Client) gatherPeers(channels ...*Channel) (peers []wire.Address) {
 	peers = make([]wire.Address, len(channels))
 	for i, ch := range channels {
-		chPeers := ch.Peers()
-		if len(chPeers) != 2 {
+		parts := ch.Participants()
+		if len(parts) != 2 {
 			panic("unsupported number of participants")
 		}
-		peers[i] = chPeers[1-ch.Idx()]
+		for _, p := range parts {
+			if !p.IsMe {
+				peers[i] = p.Address
+			}
+		}
 	}
 	return
 }
@@ -79,10 +79,11 @@ func (c *Client) handleVirtualChannelSettlementProposal(
 ) {
 	err := c.validateVirtualChannelSettlementProposal(parent, prop)
 	if err != nil {
-		c.rejectProposal(responder, err.Error())
+		c.rejectProposal(responder, UpdateRejectUnspecified, err.Error())
+		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Ctx(), virtualSettlementTimeout)
+	ctx, cancel := c.timeoutCtx(c.cfg.VirtualSettlementTimeout)
 	defer cancel()
 
 	err = c.settlementWatcher.Await(ctx, &proposalAndResponder{
@@ -90,7 +91,7 @@ func (c *Client) handleVirtualChannelSettlementProposal(
 		resp: responder,
 	})
 	if err != nil {
-		c.rejectProposal(responder, err.Error())
+		c.rejectProposal(responder, UpdateRejectUnspecified, err.Error())
 	}
 }
 
@@ -37,10 +37,19 @@ const challengeDuration = 10
 const testDuration = 10 * time.Second
 
 func TestVirtualChannelsOptimistic(t *testing.T) {
+	testVirtualChannelsOptimistic(t, 0, 0)
+}
+
+func TestVirtualChannelsFee(t *testing.T) {
+	t.Run("same", func(t *testing.T) { testVirtualChannelsOptimistic(t, 1, 1) })
+	t.Run("per parent", func(t *testing.T) { testVirtualChannelsOptimistic(t, 1, 2) })
+}
+
+func testVirtualChannelsOptimistic(t *testing.T, feeAlice, feeBob int64) {
 	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
 	defer cancel()
 
-	vct := setupVirtualChannelTest(t, ctx)
+	vct := setupVirtualChannelTestWithFee(t, ctx, feeAlice, feeBob)
 
 	// Settle.
 	var success sync.WaitGroup
@@ -127,6 +136,12 @@ type virtualChannelTest struct {
 }
 
 func setupVirtualChannelTest(t *testing.T, ctx context.Context) (vct virtualChannelTest) {
+	return setupVirtualChannelTestWithFee(t, ctx, 0, 0)
+}
+
+// setupVirtualChannelTestWithFee is like setupVirtualChannelTest, but Ingrid
+// charges Alice and Bob the given fees for the virtual channel.
+func setupVirtualChannelTestWithFee(t *testing.T, ctx context.Context, feeAlice, feeBob int64) (vct virtualChannelTest) {
 	rng := test.Prng(t)
 	require := require.New(t)
 
@@ -137,8 +152,8 @@ func setupVirtualChannelTest(t *testing.T, ctx context.Context) (vct virtualChan
 	initBalsBob := []*big.Int{big.NewInt(10), big.NewInt(10)}         // with Ingrid
 	initBalsVirtual := []*big.Int{big.NewInt(5), big.NewInt(5)}       // Alice proposes
 	vct.virtualBalsUpdated = []*big.Int{big.NewInt(2), big.NewInt(8)} // Send 3.
-	vct.finalBalsAlice = []*big.Int{big.NewInt(7), big.NewInt(13)}
-	vct.finalBalsBob = []*big.Int{big.NewInt(13), big.NewInt(7)}
+	vct.finalBalsAlice = []*big.Int{big.NewInt(7 - feeAlice), big.NewInt(13 + feeAlice)}
+	vct.finalBalsBob = []*big.Int{big.NewInt(13 - feeBob), big.NewInt(7 + feeBob)}
 	vct.finalBalIngrid = new(big.Int).Add(vct.finalBalsAlice[1], vct.finalBalsBob[1])
 	vct.errs = make(chan error, 10)
 
@@ -151,6 +166,20 @@ func setupVirtualChannelTest(t *testing.T, ctx context.Context) (vct virtualChan
 	alice, bob, ingrid := clients[0], clients[1], clients[2]
 	vct.alice, vct.bob, vct.ingrid = alice, bob, ingrid
 	vct.backend = alice.Backend // Assumes all clients have same backend.
+	if feeAlice != 0 || feeBob != 0 {
+		// The fee of a parent channel depends on whether Alice or Bob is in it.
+		feeStrategy := client.VirtualFeeStrategyFunc(func(parent *client.Channel, _ channel.Allocation) []channel.Bal {
+			for _, p := range parent.Peers() {
+				if p.Equals(alice.Identity.Address()) {
+					return []channel.Bal{big.NewInt(feeAlice)}
+				}
+			}
+			return []channel.Bal{big.NewInt(feeBob)}
+		})
+		for _, c := range clients {
+			c.SetVirtualFeeStrategy(feeStrategy)
+		}
+	}
 
 	_channelsIngrid := make(chan *client.Channel, 1)
 	var openingProposalHandlerIngrid client.ProposalHandlerFunc = func(cp client.ChannelProposal, pr *client.ProposalResponder) {
@@ -184,8 +213,9 @@ func setupVirtualChannelTest(t *testing.T, ctx context.Context) (vct virtualChan
 	)
 	require.NoError(err, "creating ledger channel proposal")
 
-	vct.chAliceIngrid, err = alice.ProposeChannel(ctx, lcpAlice)
+	res, err := alice.ProposeChannel(ctx, lcpAlice)
 	require.NoError(err, "opening channel between Alice and Ingrid")
+	vct.chAliceIngrid = res.Channel
 	select {
 	case vct.chIngridAlice = <-_channelsIngrid:
 	case err := <-vct.errs:
@@ -205,8 +235,9 @@ func setupVirtualChannelTest(t *testing.T, ctx context.Context) (vct virtualChan
 	)
 	require.NoError(err, "creating ledger channel proposal")
 
-	vct.chBobIngrid, err = bob.ProposeChannel(ctx, lcpBob)
+	res, err = bob.ProposeChannel(ctx, lcpBob)
 	require.NoError(err, "opening channel between Bob and Ingrid")
+	vct.chBobIngrid = res.Channel
 	select {
 	case vct.chIngridBob = <-_channelsIngrid:
 	case err := <-vct.errs:
@@ -246,6 +277,10 @@ func setupVirtualChannelTest(t *testing.T, ctx context.Context) (vct virtualChan
 	}
 	indexMapAlice := []channel.Index{0, 1}
 	indexMapBob := []channel.Index{1, 0}
+	var opts []client.ProposalOpts
+	if feeAlice != 0 {
+		opts = append(opts, client.WithVirtualChannelFee([]channel.Bal{big.NewInt(feeAlice)}))
+	}
 	vcp, err := client.NewVirtualChannelProposal(
 		challengeDuration,
 		alice.Identity.Address(),
@@ -253,11 +288,13 @@ func setupVirtualChannelTest(t *testing.T, ctx context.Context) (vct virtualChan
 		[]wire.Address{alice.Identity.Address(), bob.Identity.Address()},
 		[]channel.ID{vct.chAliceIngrid.ID(), vct.chBobIngrid.ID()},
 		[][]channel.Index{indexMapAlice, indexMapBob},
+		opts...,
 	)
 	require.NoError(err, "creating virtual channel proposal")
 
-	vct.chAliceBob, err = alice.ProposeChannel(ctx, vcp)
+	res, err = alice.ProposeChannel(ctx, vcp)
 	require.NoError(err, "opening channel between Alice and Bob")
+	vct.chAliceBob = res.Channel
 	select {
 	case vct.chBobAlice = <-channelsBob:
 	case err := <-vct.errs:
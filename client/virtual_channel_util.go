@@ -43,17 +43,17 @@ func transformBalances(b channel.Balances, numParts int, indexMap []channel.Inde
 	return
 }
 
-func (c *Client) rejectProposal(responder *UpdateResponder, reason string) {
-	ctx, cancel := context.WithTimeout(c.Ctx(), responseTimeout)
+func (c *Client) rejectProposal(responder *UpdateResponder, code UpdateRejectCode, reason string) {
+	ctx, cancel := c.timeoutCtx(c.cfg.ResponseTimeout)
 	defer cancel()
-	err := responder.Reject(ctx, reason)
+	err := responder.RejectWithCode(ctx, code, reason)
 	if err != nil {
 		c.log.Warn("Rejecting proposal with reason '%s': %+v", reason, err)
 	}
 }
 
 func (c *Client) acceptProposal(responder *UpdateResponder) {
-	ctx, cancel := context.WithTimeout(c.Ctx(), responseTimeout)
+	ctx, cancel := c.timeoutCtx(c.cfg.ResponseTimeout)
 	defer cancel()
 	err := responder.Accept(ctx)
 	if err != nil {
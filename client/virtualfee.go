@@ -0,0 +1,165 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+)
+
+type (
+	// VirtualFeeStrategy determines the fee that the intermediary of a virtual
+	// channel charges for forwarding it. The fee is paid when the virtual
+	// channel is funded: the funding update of each parent channel transfers
+	// the fee from the endpoint of the virtual channel to the intermediary,
+	// in addition to locking the funds of the virtual channel. The settlement
+	// of the virtual channel is not affected.
+	//
+	// The fee is determined per parent channel, so an intermediary may charge
+	// the endpoints different fees. The proposer of a virtual channel puts its
+	// fee into the proposal, see WithVirtualChannelFee. The other endpoints
+	// pay the fee returned by their strategy for their parent channel. The
+	// funding proposal of each parent channel carries its fee to the
+	// intermediary, which rejects funding updates whose fee differs from the
+	// fee returned by its strategy for that parent channel, or that do not pay
+	// exactly that fee or change the parent balances in any other way. Hence,
+	// endpoints and intermediaries must use strategies that agree on the fee of
+	// their common parent channel.
+	VirtualFeeStrategy interface {
+		// VirtualChannelFee returns the fee per asset of the parent channel
+		// for funding the virtual channel with the given initial allocation
+		// from parent. A nil fee means no fee.
+		VirtualChannelFee(parent *Channel, virtual channel.Allocation) []channel.Bal
+	}
+
+	// VirtualFeeStrategyFunc is a function that implements VirtualFeeStrategy.
+	VirtualFeeStrategyFunc func(parent *Channel, virtual channel.Allocation) []channel.Bal
+)
+
+// VirtualChannelFee returns f(parent, virtual).
+func (f VirtualFeeStrategyFunc) VirtualChannelFee(parent *Channel, virtual channel.Allocation) []channel.Bal {
+	return f(parent, virtual)
+}
+
+// SetVirtualFeeStrategy sets the strategy that determines the fees paid and
+// charged for virtual channels. By default, no fees are paid or charged. This
+// method is expected to be called once during the setup of the client,
+// before Handle is started, and is hence not thread-safe.
+func (c *Client) SetVirtualFeeStrategy(s VirtualFeeStrategy) {
+	c.virtualFeeStrategy = s
+}
+
+// virtualChannelFee returns the fee for funding the virtual channel from
+// parent, with one entry per asset. Without a strategy, all entries are zero.
+func (c *Client) virtualChannelFee(parent *Channel, virtual channel.Allocation) ([]channel.Bal, error) {
+	var fee []channel.Bal
+	if c.virtualFeeStrategy != nil {
+		fee = c.virtualFeeStrategy.VirtualChannelFee(parent, virtual)
+	}
+	return normalizeFee(fee, len(parent.state().Assets))
+}
+
+// normalizeFee returns a copy of the fee with one non-nil entry per asset. A
+// nil fee is all zero.
+func normalizeFee(fee []channel.Bal, numAssets int) ([]channel.Bal, error) {
+	if fee != nil && len(fee) != numAssets {
+		return nil, errors.Errorf("fee has %d assets, expected %d", len(fee), numAssets)
+	}
+	norm := make([]channel.Bal, numAssets)
+	for a := range norm {
+		switch {
+		case fee == nil || fee[a] == nil:
+			norm[a] = new(big.Int)
+		case fee[a].Sign() < 0:
+			return nil, errors.Errorf("negative fee for asset %d", a)
+		default:
+			norm[a] = new(big.Int).Set(fee[a])
+		}
+	}
+	return norm, nil
+}
+
+// isZeroFee returns whether the fee is empty or all zero.
+func isZeroFee(fee []channel.Bal) bool {
+	for _, f := range fee {
+		if f != nil && f.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// equalFee returns whether both fees are equal. Missing and nil entries count
+// as zero.
+func equalFee(a, b []channel.Bal) bool {
+	at := func(fee []channel.Bal, i int) channel.Bal {
+		if i >= len(fee) || fee[i] == nil {
+			return new(big.Int)
+		}
+		return fee[i]
+	}
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if at(a, i).Cmp(at(b, i)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// payFee transfers the fee from participant `from` to participant `to`. It
+// fails without changing bals if `from` cannot afford the fee.
+func payFee(bals channel.Balances, from, to channel.Index, fee []channel.Bal) error {
+	for a, f := range fee {
+		if bals[a][from].Cmp(f) < 0 {
+			return errors.Errorf("insufficient funds for fee %v of asset %d", f, a)
+		}
+	}
+	for a, f := range fee {
+		bals[a][from] = new(big.Int).Sub(bals[a][from], f)
+		bals[a][to] = new(big.Int).Add(bals[a][to], f)
+	}
+	return nil
+}
+
+// assertFeePaid asserts that the balances after only differ from the balances
+// before by a transfer of exactly the fee from participant `from` to
+// participant `to`.
+func assertFeePaid(before, after channel.Balances, from, to channel.Index, fee []channel.Bal) error {
+	if len(after) != len(before) {
+		return errors.New("number of assets changed")
+	}
+	for a := range before {
+		if len(after[a]) != len(before[a]) {
+			return errors.Errorf("number of participants changed for asset %d", a)
+		}
+		paid := new(big.Int).Sub(before[a][from], after[a][from])
+		received := new(big.Int).Sub(after[a][to], before[a][to])
+		switch {
+		case paid.Cmp(received) != 0:
+			return errors.Errorf("paid fee %v does not match received fee %v for asset %d", paid, received, a)
+		case paid.Cmp(fee[a]) != 0:
+			return errors.Errorf("paid fee %v for asset %d, expected %v", paid, a, fee[a])
+		}
+		for p := range before[a] {
+			if p != int(from) && p != int(to) && before[a][p].Cmp(after[a][p]) != 0 {
+				return errors.Errorf("balance of participant %d changed for asset %d", p, a)
+			}
+		}
+	}
+	return nil
+}
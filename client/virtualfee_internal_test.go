@@ -0,0 +1,59 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"perun.network/go-perun/channel"
+)
+
+func TestAssertFeePaid(t *testing.T) {
+	bals := func(b ...int64) channel.Balances {
+		res := channel.Balances{make([]channel.Bal, len(b))}
+		for i, x := range b {
+			res[0][i] = big.NewInt(x)
+		}
+		return res
+	}
+	fee := []channel.Bal{big.NewInt(2)}
+	before := bals(10, 5)
+
+	assert.NoError(t, assertFeePaid(before, bals(8, 7), 0, 1, fee))
+	assert.Error(t, assertFeePaid(before, bals(7, 8), 0, 1, fee), "fee too high")
+	assert.Error(t, assertFeePaid(before, bals(9, 6), 0, 1, fee), "fee too low")
+	assert.Error(t, assertFeePaid(before, bals(10, 5), 0, 1, fee), "no fee")
+	assert.Error(t, assertFeePaid(before, bals(8, 8), 0, 1, fee), "created funds")
+	assert.Error(t, assertFeePaid(bals(10, 5, 1), bals(8, 7, 0), 0, 1, fee), "changed third balance")
+	assert.NoError(t, assertFeePaid(before, before, 0, 1, []channel.Bal{new(big.Int)}), "zero fee")
+}
+
+func TestPayFee(t *testing.T) {
+	bals := channel.Balances{[]channel.Bal{big.NewInt(1), big.NewInt(5)}}
+	assert.Error(t, payFee(bals, 0, 1, []channel.Bal{big.NewInt(2)}), "insufficient funds")
+	assert.True(t, bals.Equal(channel.Balances{[]channel.Bal{big.NewInt(1), big.NewInt(5)}}), "balances changed")
+	assert.NoError(t, payFee(bals, 0, 1, []channel.Bal{big.NewInt(1)}))
+	assert.True(t, bals.Equal(channel.Balances{[]channel.Bal{big.NewInt(0), big.NewInt(6)}}))
+}
+
+func TestEqualFee(t *testing.T) {
+	assert.True(t, equalFee(nil, []channel.Bal{new(big.Int), nil}))
+	assert.True(t, equalFee([]channel.Bal{big.NewInt(1)}, []channel.Bal{big.NewInt(1), new(big.Int)}))
+	assert.False(t, equalFee([]channel.Bal{big.NewInt(1)}, nil))
+	assert.False(t, equalFee([]channel.Bal{nil, big.NewInt(1)}, []channel.Bal{big.NewInt(1)}))
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+)
+
+type memCheckpointer struct {
+	mu  sync.Mutex
+	cps map[channel.ID]client.WatchCheckpoint
+}
+
+func newMemCheckpointer() *memCheckpointer {
+	return &memCheckpointer{cps: make(map[channel.ID]client.WatchCheckpoint)}
+}
+
+func (m *memCheckpointer) Checkpoint(_ context.Context, id channel.ID, cp client.WatchCheckpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cps[id] = cp
+	return nil
+}
+
+func (m *memCheckpointer) LastCheckpoint(_ context.Context, id channel.ID) (*client.WatchCheckpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp, ok := m.cps[id]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+// resumableAdjudicator reports the same block for all events and records the
+// blocks it is subscribed from.
+type resumableAdjudicator struct {
+	channel.Adjudicator
+	block      uint64
+	subscribed chan uint64
+}
+
+func (a *resumableAdjudicator) SubscribeFrom(ctx context.Context, params *channel.Params, block uint64) (channel.AdjudicatorSubscription, error) {
+	a.subscribed <- block
+	return a.Subscribe(ctx, params)
+}
+
+func (a *resumableAdjudicator) EventPosition(channel.AdjudicatorEvent) (uint64, uint, bool) {
+	return a.block, 0, true
+}
+
+type chanAdjEventHandler chan channel.AdjudicatorEvent
+
+func (h chanAdjEventHandler) HandleAdjudicatorEvent(e channel.AdjudicatorEvent) { h <- e }
+
+func TestChannel_Watch_Checkpointer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	rng := test.Prng(t)
+
+	setups := NewSetups(rng, []string{"Alice", "Bob"})
+	adj := &resumableAdjudicator{
+		Adjudicator: setups[0].Adjudicator,
+		block:       42,
+		subscribed:  make(chan uint64, 1),
+	}
+	setups[0].Adjudicator = adj
+	clients := newClientsFromSetups(rng, setups, t)
+	alice, bob := clients[0], clients[1]
+	chAlice, _ := openAcceptingChannel(ctx, t, rng, alice, bob)
+	defer alice.Close() // nolint:errcheck
+
+	cp := newMemCheckpointer()
+	strategyCalls := make(chan channel.AdjudicatorEvent, 10)
+	strategy := client.DisputeStrategyFunc(func(_ context.Context, _ *client.Channel, e channel.AdjudicatorEvent) error {
+		strategyCalls <- e
+		return nil
+	})
+	watch := func(opts ...client.WatchOption) chanAdjEventHandler {
+		h := make(chanAdjEventHandler, 1)
+		go chAlice.Watch(h, opts...) // nolint:errcheck
+		return h
+	}
+
+	first := watch(client.WithCheckpointer(cp), client.WithDisputeStrategy(strategy))
+	require.NoError(t, chAlice.Register(ctx))
+	select {
+	case e := <-first:
+		assert.IsType(t, new(channel.RegisteredEvent), e)
+	case <-ctx.Done():
+		t.Fatal("first watcher did not receive event")
+	}
+	<-strategyCalls
+	require.Eventually(t, func() bool {
+		last, _ := cp.LastCheckpoint(ctx, chAlice.ID())
+		return last != nil
+	}, testDuration, 10*time.Millisecond, "event should be checkpointed")
+	last, err := cp.LastCheckpoint(ctx, chAlice.ID())
+	require.NoError(t, err)
+	assert.Equal(t, adj.block, last.Block)
+
+	// A watcher without checkpointer receives the replayed event.
+	select {
+	case <-watch():
+	case <-ctx.Done():
+		t.Fatal("watcher did not receive replayed event")
+	}
+
+	// A watcher with checkpointer resumes at the checkpointed block. It
+	// processes the replayed event, but does not notify the handler again.
+	h := watch(client.WithCheckpointer(cp), client.WithDisputeStrategy(strategy))
+	select {
+	case block := <-adj.subscribed:
+		assert.Equal(t, adj.block, block)
+	case <-ctx.Done():
+		t.Fatal("watcher did not resume from checkpoint")
+	}
+	select {
+	case e := <-strategyCalls:
+		assert.IsType(t, new(channel.RegisteredEvent), e)
+	case <-ctx.Done():
+		t.Fatal("replayed event not processed")
+	}
+	select {
+	case e := <-h:
+		t.Errorf("checkpointed event %v delivered again", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/wallet"
+)
+
+// ErrWatchOnly is returned when a watch-only channel is asked to sign a new
+// state.
+var ErrWatchOnly = errors.New("channel is watch-only")
+
+// watchOnlyAccount is used for watch-only channels if the wallet does not
+// hold the account of the channel participant. It cannot sign.
+type watchOnlyAccount struct {
+	address wallet.Address
+}
+
+func (a *watchOnlyAccount) Address() wallet.Address {
+	return a.address
+}
+
+func (a *watchOnlyAccount) SignData([]byte) ([]byte, error) {
+	return nil, errors.WithStack(ErrWatchOnly)
+}
+
+// NewWatchOnlyChannel creates a channel controller for the ledger channel of
+// the given fully signed state, e.g., taken from a backup or received from a
+// counterparty. The controller takes the role of participant idx.
+//
+// A watch-only channel can only be used to Watch, Register and Settle the
+// channel on-chain. It cannot propose or progress states and returns
+// ErrWatchOnly instead. If the client's wallet does not hold the account of
+// participant idx, the channel can still be registered and concluded, but the
+// withdrawal might fail, depending on the backend.
+//
+// The channel does not increment the usage count of the wallet account, see
+// wallet.Wallet.IncrementUsage, and settling it does not decrement it.
+//
+// The channel is not persisted and not managed by the client. In particular,
+// it is not closed when the client is closed, and the caller must close it
+// after use.
+func (c *Client) NewWatchOnlyChannel(ss channel.SignedState, idx channel.Index) (*Channel, error) {
+	if ss.Params == nil || ss.State == nil {
+		return nil, errors.New("params and state must not be nil")
+	}
+	params, state := ss.Params, ss.State
+	if !params.LedgerChannel {
+		return nil, errors.New("only ledger channels are supported")
+	}
+	if int(idx) >= len(params.Parts) {
+		return nil, errors.Errorf("index %d out of range", idx)
+	}
+	if state.ID != params.ID() {
+		return nil, errors.New("state does not belong to params")
+	}
+	if len(ss.Sigs) != len(params.Parts) {
+		return nil, errors.Errorf("expected %d signatures, got %d", len(params.Parts), len(ss.Sigs))
+	}
+	for i, sig := range ss.Sigs {
+		if ok, err := channel.Verify(params.Parts[i], params, state, sig); err != nil {
+			return nil, errors.WithMessagef(err, "verifying signature %d", i)
+		} else if !ok {
+			return nil, errors.Errorf("invalid signature %d", i)
+		}
+	}
+
+	acc, err := c.wallet.Unlock(params.Parts[idx])
+	if err != nil {
+		c.logChan(params.ID()).Debugf("Creating watch-only channel without account: %v", err)
+		acc = &watchOnlyAccount{address: params.Parts[idx]}
+	}
+
+	src := persistence.NewChannel()
+	src.IdxV = idx
+	src.ParamsV = params.Clone()
+	src.CurrentTXV = channel.Transaction{State: state.Clone(), Sigs: ss.Sigs}
+	src.PhaseV = channel.Acting
+	machine, err := channel.RestoreStateMachine(acc, src)
+	if err != nil {
+		return nil, errors.WithMessage(err, "restoring state machine")
+	}
+
+	ch, err := c.channelFromMachine(machine, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The channel is not persisted, so we don't persist its changes either.
+	ch.machine = persistence.FromStateMachine(machine, persistence.NonPersistRestorer)
+	ch.watchOnly = true
+	return ch, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	chtest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wallet"
+	wtest "perun.network/go-perun/wallet/test"
+)
+
+func TestClient_NewWatchOnlyChannel(t *testing.T) {
+	rng := test.Prng(t)
+	c := NewClients(rng, []string{"Watcher"}, t)[0]
+
+	// The wallet of the client holds the account of participant 1.
+	accs := []wallet.Account{wtest.NewRandomAccount(rng), c.Wallet.NewRandomAccount(rng)}
+	params, state := chtest.NewRandomParamsAndState(rng,
+		chtest.WithParts(accs[0].Address(), accs[1].Address()),
+		chtest.WithoutApp(),
+		chtest.WithLedgerChannel(true),
+		chtest.WithNumLocked(0),
+		chtest.WithIsFinal(false),
+	)
+	sigs := make([]wallet.Sig, len(accs))
+	for i, acc := range accs {
+		var err error
+		sigs[i], err = channel.Sign(acc, params, state)
+		require.NoError(t, err)
+	}
+
+	// Invalid signatures are rejected.
+	_, err := c.NewWatchOnlyChannel(channel.SignedState{Params: params, State: state, Sigs: []wallet.Sig{sigs[1], sigs[0]}}, 0)
+	assert.Error(t, err)
+
+	ch, err := c.NewWatchOnlyChannel(channel.SignedState{Params: params, State: state, Sigs: sigs}, 1)
+	require.NoError(t, err)
+	defer ch.Close()
+	assert.Equal(t, channel.Index(1), ch.Idx())
+	assert.Equal(t, state, ch.State())
+
+	ctx, cancel := context.WithTimeout(context.Background(), testDuration)
+	defer cancel()
+	err = ch.UpdateBy(ctx, func(s *channel.State) error { return nil })
+	assert.True(t, errors.Is(err, client.ErrWatchOnly))
+
+	// The channel can be registered and settled on-chain.
+	require.NoError(t, ch.Register(ctx))
+	assert.Equal(t, channel.Registered, ch.Phase())
+	require.NoError(t, ch.Settle(ctx, false))
+	assert.Equal(t, channel.Withdrawn, ch.Phase())
+	asset := state.Assets[0]
+	assert.Zero(t, c.Backend.GetBalance(accs[1].Address(), asset).Cmp(state.Balances[0][1]), "funds should be withdrawn")
+}
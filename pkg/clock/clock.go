@@ -0,0 +1,102 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clock provides an abstraction of the passing of time, so that
+// time-dependent logic can be tested deterministically.
+package clock // import "perun.network/go-perun/pkg/clock"
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Clock is the source of time for timeout computations.
+	Clock interface {
+		// Now returns the current time.
+		Now() time.Time
+		// After returns a channel that receives the current time once
+		// duration d has passed.
+		After(d time.Duration) <-chan time.Time
+	}
+
+	// SystemClock is the Clock of the system, as implemented by the time
+	// package.
+	SystemClock struct{}
+)
+
+// System returns the system clock.
+func System() Clock {
+	return SystemClock{}
+}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After returns time.After(d).
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WithTimeout is like context.WithTimeout but measures the timeout with the
+// given clock. Once the timeout has passed, the context's Err() returns
+// context.DeadlineExceeded.
+func WithTimeout(parent context.Context, clk Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	// Use the more efficient timer of the context package for the system clock.
+	if _, ok := clk.(SystemClock); ok {
+		return context.WithTimeout(parent, d)
+	}
+
+	deadline := clk.Now().Add(d)
+	ctx, cancel := context.WithCancel(parent)
+	tctx := &timeoutCtx{Context: ctx, deadline: deadline}
+	timeout := clk.After(d)
+	go func() {
+		select {
+		case <-timeout:
+			atomic.StoreInt32(&tctx.expired, 1)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return tctx, cancel
+}
+
+// timeoutCtx is a cancelable context that reports context.DeadlineExceeded
+// after its timeout expired.
+type timeoutCtx struct {
+	context.Context
+	deadline time.Time
+	expired  int32 // accessed atomically
+}
+
+// Deadline returns the earlier deadline of the context and its parent.
+func (c *timeoutCtx) Deadline() (time.Time, bool) {
+	if d, ok := c.Context.Deadline(); ok && d.Before(c.deadline) {
+		return d, true
+	}
+	return c.deadline, true
+}
+
+// Err returns context.DeadlineExceeded if the timeout expired and the
+// underlying context's error otherwise.
+func (c *timeoutCtx) Err() error {
+	if atomic.LoadInt32(&c.expired) == 1 {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"perun.network/go-perun/pkg/clock"
+	clocktest "perun.network/go-perun/pkg/clock/test"
+	ctxtest "perun.network/go-perun/pkg/context/test"
+)
+
+func TestWithTimeout(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clk := clocktest.NewMockClock(start)
+
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, time.Second)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(time.Second), deadline)
+
+	clk.Advance(time.Second - 1)
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, 1, clk.NumTimers())
+
+	clk.Advance(1)
+	ctxtest.AssertTerminates(t, time.Second, func() { <-ctx.Done() })
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	assert.Zero(t, clk.NumTimers())
+}
+
+func TestWithTimeout_Cancel(t *testing.T) {
+	clk := clocktest.NewMockClock(time.Unix(0, 0))
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, time.Second)
+	cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestWithTimeout_System(t *testing.T) {
+	ctx, cancel := clock.WithTimeout(context.Background(), clock.System(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
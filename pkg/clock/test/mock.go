@@ -0,0 +1,86 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package test contains a Clock implementation for deterministic tests.
+package test // import "perun.network/go-perun/pkg/clock/test"
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// MockClock is a Clock whose time only passes when it is advanced
+	// manually.
+	MockClock struct {
+		mtx    sync.Mutex
+		now    time.Time
+		timers []mockTimer
+	}
+
+	mockTimer struct {
+		deadline time.Time
+		c        chan time.Time
+	}
+)
+
+// NewMockClock returns a new MockClock that starts at the given time.
+func NewMockClock(now time.Time) *MockClock {
+	return &MockClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *MockClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the current time once the clock was
+// advanced by at least d.
+func (c *MockClock) After(d time.Duration) <-chan time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, mockTimer{deadline: c.now.Add(d), c: ch})
+	return ch
+}
+
+// Advance advances the clock by d and fires all timers that expired.
+func (c *MockClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+}
+
+// NumTimers returns the number of timers that have not fired yet. It can be
+// used to wait until the tested code is waiting for the clock.
+func (c *MockClock) NumTimers() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.timers)
+}
@@ -25,7 +25,8 @@ import (
 // 1024bit -> 128 bytes.
 const MaxBigIntLength = 128
 
-// BigInt is a serializer big integer.
+// BigInt is a serializer big integer. Negative values cannot be encoded, see
+// SignedBigInt.
 type BigInt struct {
 	*big.Int
 }
@@ -84,3 +85,60 @@ func (b BigInt) Encode(writer io.Writer) error {
 	n, err := writer.Write(bytes)
 	return errors.Wrapf(err, "failed to write big.Int, wrote %d bytes of %d", n, length)
 }
+
+// SignedBigInt is a serializer big integer that can also be negative. It is
+// encoded as a sign byte, 0 for non-negative and 1 for negative values,
+// followed by the absolute value encoded as a BigInt. BigInt's encoding is
+// left unchanged, so SignedBigInt must be used explicitly on both sides.
+type SignedBigInt struct {
+	*big.Int
+}
+
+// Sign bytes of an encoded SignedBigInt.
+const (
+	signNonNegative uint8 = 0
+	signNegative    uint8 = 1
+)
+
+// Decode reads a signed big.Int from the given stream.
+func (b *SignedBigInt) Decode(reader io.Reader) error {
+	var sign = make([]byte, 1)
+	if _, err := io.ReadFull(reader, sign); err != nil {
+		return errors.Wrap(err, "failed to decode sign of big.Int")
+	}
+	if sign[0] != signNonNegative && sign[0] != signNegative {
+		return errors.Errorf("invalid sign byte %d of big.Int", sign[0])
+	}
+
+	abs := BigInt{b.Int}
+	if err := abs.Decode(reader); err != nil {
+		return err
+	}
+	if sign[0] == signNegative {
+		if abs.Sign() == 0 {
+			return errors.New("negative zero big.Int")
+		}
+		abs.Neg(abs.Int)
+	}
+	b.Int = abs.Int
+	return nil
+}
+
+// Encode writes a signed big.Int to the stream.
+func (b SignedBigInt) Encode(writer io.Writer) error {
+	if b.Int == nil {
+		panic("logic error: tried to encode nil big.Int")
+	}
+	abs, sign := b.Int, signNonNegative
+	if b.Int.Sign() == -1 {
+		abs, sign = new(big.Int).Neg(b.Int), signNegative
+	}
+	if abs.BitLen() > MaxBigIntLength*8 {
+		return errors.New("big.Int too big to encode")
+	}
+
+	if _, err := writer.Write([]byte{sign}); err != nil {
+		return errors.Wrap(err, "failed to write sign")
+	}
+	return BigInt{abs}.Encode(writer)
+}
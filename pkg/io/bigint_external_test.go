@@ -87,3 +87,46 @@ func TestBigInt_Invalid(t *testing.T) {
 
 	a.Panics(func() { perunio.BigInt{nil}.Encode(buf) }, "encoding nil big.Int failed to panic")
 }
+
+func TestSignedBigInt_Generic(t *testing.T) {
+	vars := []perunio.Serializer{
+		&perunio.SignedBigInt{big.NewInt(0)},
+		&perunio.SignedBigInt{big.NewInt(1)},
+		&perunio.SignedBigInt{big.NewInt(-1)},
+		&perunio.SignedBigInt{big.NewInt(-123456)},
+		&perunio.SignedBigInt{new(big.Int).Neg(new(big.Int).SetBytes([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}))},
+	}
+	test.GenericSerializerTest(t, vars...)
+}
+
+func TestSignedBigInt_Encoding(t *testing.T) {
+	for _, tc := range []struct {
+		x   int64
+		enc []byte
+	}{
+		{0, []byte{0, 0}},
+		{42, []byte{0, 1, 42}},
+		{-42, []byte{1, 1, 42}},
+	} {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, perunio.SignedBigInt{big.NewInt(tc.x)}.Encode(buf))
+		assert.Equal(t, tc.enc, buf.Bytes(), "encoding of %d", tc.x)
+
+		var result perunio.SignedBigInt
+		assert.NoError(t, result.Decode(buf))
+		assert.Zero(t, big.NewInt(tc.x).Cmp(result.Int), "decoding of %d", tc.x)
+	}
+}
+
+func TestSignedBigInt_Invalid(t *testing.T) {
+	var result perunio.SignedBigInt
+	assert.Error(t, result.Decode(bytes.NewBuffer([]byte{2, 1, 42})), "invalid sign byte")
+	assert.Error(t, result.Decode(bytes.NewBuffer([]byte{1, 0})), "negative zero")
+	assert.Error(t, result.Decode(bytes.NewBuffer(nil)), "missing sign byte")
+
+	tooBig := new(big.Int).Lsh(big.NewInt(-1), perunio.MaxBigIntLength*8+1)
+	buf := new(bytes.Buffer)
+	assert.Error(t, perunio.SignedBigInt{tooBig}.Encode(buf), "encoding too big big.Int should fail")
+	assert.Zero(t, buf.Len(), "encoding too big big.Int should not write anything")
+	assert.Panics(t, func() { perunio.SignedBigInt{nil}.Encode(buf) })
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDecodeBudgetExceeded is returned when decoding from a BudgetReader would
+// allocate more memory than its remaining budget.
+var ErrDecodeBudgetExceeded = errors.New("decode allocation budget exceeded")
+
+// Allocator is implemented by readers that limit the memory that decoders may
+// allocate, like BudgetReader. Readers that wrap an Allocator should
+// implement it by forwarding to the wrapped reader, so that its limit still
+// applies to the values decoded from the wrapper.
+type Allocator interface {
+	// Alloc reserves n bytes. It returns ErrDecodeBudgetExceeded if n bytes
+	// must not be allocated.
+	Alloc(n int) error
+}
+
+// BudgetReader is an io.Reader that limits the cumulative amount of memory
+// that length-prefixed values may allocate while being decoded from it. This
+// protects decoders against messages that announce many large values.
+type BudgetReader struct {
+	io.Reader
+	remaining int
+	buf       [1]byte // for ReadByte
+}
+
+// NewBudgetReader returns a BudgetReader reading from r that allows decoded
+// length-prefixed values to allocate at most budget bytes in total.
+func NewBudgetReader(r io.Reader, budget int) *BudgetReader {
+	return &BudgetReader{Reader: r, remaining: budget}
+}
+
+// Alloc reserves n bytes from the remaining budget. It returns
+// ErrDecodeBudgetExceeded without reserving anything if the budget is too
+// small.
+func (b *BudgetReader) Alloc(n int) error {
+	if n > b.remaining {
+		return errors.WithMessagef(ErrDecodeBudgetExceeded, "allocating %d bytes, %d remaining", n, b.remaining)
+	}
+	b.remaining -= n
+	return nil
+}
+
+// Remaining returns the remaining budget in bytes.
+func (b *BudgetReader) Remaining() int {
+	return b.remaining
+}
+
+// ReadByte reads a single byte. It makes BudgetReader an io.ByteReader, so
+// that wrapping a reader into a BudgetReader does not disable the fast paths
+// of decoders for io.ByteReaders. Reading does not consume the budget.
+func (b *BudgetReader) ReadByte() (byte, error) {
+	if br, ok := b.Reader.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+	_, err := io.ReadFull(b.Reader, b.buf[:])
+	return b.buf[0], err
+}
+
+// AllocBudget reserves n bytes from the budget of r if r is an Allocator. It
+// must be called by decoders before allocating memory for a length-prefixed
+// value.
+func AllocBudget(r io.Reader, n int) error {
+	if a, ok := r.(Allocator); ok {
+		return a.Alloc(n)
+	}
+	return nil
+}
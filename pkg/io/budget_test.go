@@ -0,0 +1,79 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetReader(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, "perun", "network"))
+	enc := buf.Bytes()
+
+	var s1, s2 string
+	r := NewBudgetReader(bytes.NewReader(enc), 12)
+	require.NoError(t, Decode(r, &s1, &s2))
+	assert.Equal(t, "network", s2)
+	assert.Zero(t, r.Remaining())
+
+	r = NewBudgetReader(bytes.NewReader(enc), 11)
+	err := Decode(r, &s1, &s2)
+	assert.True(t, errors.Is(err, ErrDecodeBudgetExceeded))
+	assert.Equal(t, 6, r.Remaining(), "failed allocation should not be deducted")
+}
+
+// wrappingReader wraps a reader and forwards its allocations.
+type wrappingReader struct {
+	r *BudgetReader
+}
+
+func (w wrappingReader) Read(p []byte) (int, error) { return w.r.Read(p) }
+
+func (w wrappingReader) Alloc(n int) error { return w.r.Alloc(n) }
+
+func TestAllocBudget_wrapped(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, "perun"))
+
+	var s string
+	err := Decode(wrappingReader{NewBudgetReader(&buf, 4)}, &s)
+	assert.True(t, errors.Is(err, ErrDecodeBudgetExceeded))
+}
+
+func TestBudgetReader_ReadByte(t *testing.T) {
+	for name, r := range map[string]io.Reader{
+		"ByteReader": bytes.NewReader([]byte{1, 2}),
+		"Reader":     iotest.OneByteReader(bytes.NewReader([]byte{1, 2})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var br io.ByteReader = NewBudgetReader(r, 0)
+			for _, want := range []byte{1, 2} {
+				b, err := br.ReadByte()
+				require.NoError(t, err)
+				assert.Equal(t, want, b)
+			}
+			_, err := br.ReadByte()
+			assert.Error(t, err)
+		})
+	}
+}
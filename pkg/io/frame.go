@@ -0,0 +1,77 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// WriteFrame encodes msg and writes it to w as a frame, that is, prefixed with
+// the length of the encoding as uint32. The frame is written at once, so that
+// it is not interleaved with concurrent writes if w serializes its writes.
+func WriteFrame(w io.Writer, msg Encoder) error {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 4)) // placeholder for the length
+	if err := msg.Encode(&buf); err != nil {
+		return errors.WithMessage(err, "encoding frame")
+	}
+	frame := buf.Bytes()
+	length := len(frame) - 4
+	if uint64(length) > math.MaxUint32 {
+		return errors.Errorf("frame too long: %d bytes", length)
+	}
+	byteOrder.PutUint32(frame, uint32(length))
+
+	_, err := w.Write(frame)
+	return errors.Wrap(err, "writing frame")
+}
+
+// ReadFrame reads a frame written by WriteFrame from r and decodes it into
+// msg. It reads exactly the frame from r, so the stream stays in sync even if
+// msg cannot be decoded from the frame. It returns an error if the frame is
+// truncated or msg does not consume the whole frame. If r is an Allocator,
+// the frame length is deducted from its budget.
+func ReadFrame(r io.Reader, msg Decoder) error {
+	var length uint32
+	if err := binary.Read(r, byteOrder, &length); err != nil {
+		return errors.Wrap(err, "reading frame length")
+	}
+	if err := AllocBudget(r, int(length)); err != nil {
+		return err
+	}
+
+	// The frame is read incrementally so that a corrupt length does not
+	// allocate the whole announced length upfront.
+	var buf bytes.Buffer
+	if n, err := io.CopyN(&buf, r, int64(length)); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return errors.Wrapf(err, "truncated frame, read %d/%d bytes", n, length)
+	}
+
+	if err := msg.Decode(&buf); err != nil {
+		return errors.WithMessage(err, "decoding frame")
+	}
+	if buf.Len() != 0 {
+		return errors.Errorf("frame has %d trailing bytes", buf.Len())
+	}
+	return nil
+}
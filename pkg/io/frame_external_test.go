@@ -0,0 +1,72 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io_test
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	perunio "perun.network/go-perun/pkg/io"
+)
+
+func TestFrame(t *testing.T) {
+	var buf bytes.Buffer
+	msgs := []perunio.BigInt{{big.NewInt(0)}, {big.NewInt(42)}, {big.NewInt(123456)}}
+	for _, m := range msgs {
+		require.NoError(t, perunio.WriteFrame(&buf, m))
+	}
+	assert.Equal(t, []byte{2, 0, 0, 0, 1, 42}, buf.Bytes()[5:11], "second frame")
+
+	for _, m := range msgs {
+		var d perunio.BigInt
+		require.NoError(t, perunio.ReadFrame(&buf, &d))
+		assert.Zero(t, m.Cmp(d.Int))
+	}
+	assert.Zero(t, buf.Len())
+}
+
+func TestReadFrame_Invalid(t *testing.T) {
+	var d perunio.BigInt
+
+	t.Run("truncated", func(t *testing.T) {
+		err := perunio.ReadFrame(bytes.NewBuffer([]byte{3, 0, 0, 0, 2, 42}), &d)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "expected ErrUnexpectedEOF, got %v", err)
+		assert.Error(t, perunio.ReadFrame(bytes.NewBuffer([]byte{3, 0}), &d))
+	})
+
+	t.Run("trailing bytes", func(t *testing.T) {
+		buf := bytes.NewBuffer([]byte{3, 0, 0, 0, 1, 42, 7, 9})
+		assert.Error(t, perunio.ReadFrame(buf, &d))
+		assert.Equal(t, []byte{9}, buf.Bytes(), "stream should stay in sync")
+	})
+
+	t.Run("undecodable", func(t *testing.T) {
+		buf := bytes.NewBuffer([]byte{2, 0, 0, 0, 5, 42, 9})
+		assert.Error(t, perunio.ReadFrame(buf, &d))
+		assert.Equal(t, []byte{9}, buf.Bytes(), "stream should stay in sync")
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		r := perunio.NewBudgetReader(bytes.NewBuffer([]byte{0, 0, 0, 1}), 1024)
+		err := perunio.ReadFrame(r, &d)
+		assert.True(t, errors.Is(err, perunio.ErrDecodeBudgetExceeded), "expected budget error, got %v", err)
+	})
+}
@@ -15,9 +15,11 @@
 package io
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"time"
 
@@ -26,14 +28,29 @@ import (
 
 var byteOrder = binary.LittleEndian
 
+// Times are encoded as nanoseconds since the Unix epoch in an int64, so only
+// times between minTime and maxTime can be encoded.
+var (
+	minTime = time.Unix(0, math.MinInt64)
+	maxTime = time.Unix(0, math.MaxInt64)
+)
+
 // Encode encodes multiple primitive values into a writer.
 // All passed values must be copies, not references.
+//
+// A time.Time is encoded as nanoseconds since the Unix epoch, dropping its
+// location and monotonic clock reading. Times outside of the range
+// representable this way, like the zero time, cannot be encoded.
 func Encode(writer io.Writer, values ...interface{}) (err error) {
 	for i, value := range values {
 		switch v := value.(type) {
 		case bool, int8, uint8, int16, uint16, int32, uint32, int64, uint64:
 			err = binary.Write(writer, byteOrder, v)
 		case time.Time:
+			if v.Before(minTime) || v.After(maxTime) {
+				err = errors.Errorf("time %v out of encodable range", v)
+				break
+			}
 			err = binary.Write(writer, byteOrder, v.UnixNano())
 		case *big.Int:
 			err = BigInt{v}.Encode(writer)
@@ -59,8 +76,20 @@ func Encode(writer io.Writer, values ...interface{}) (err error) {
 	return nil
 }
 
+// EncodeTo encodes multiple primitive values like Encode, but appends the
+// encoding to buf and returns the extended buffer. If buf has enough capacity,
+// no allocations are needed, so buffers can be reused across calls. This allows
+// writing the encoding of a large value at once instead of value by value.
+func EncodeTo(buf []byte, values ...interface{}) ([]byte, error) {
+	w := bytes.NewBuffer(buf)
+	err := Encode(w, values...)
+	return w.Bytes(), err
+}
+
 // Decode decodes multiple primitive values from a reader.
 // All passed values must be references, not copies.
+//
+// A time.Time is decoded in UTC.
 func Decode(reader io.Reader, values ...interface{}) (err error) {
 	for i, value := range values {
 		switch v := value.(type) {
@@ -69,7 +98,7 @@ func Decode(reader io.Reader, values ...interface{}) (err error) {
 		case *time.Time:
 			var nsec int64
 			err = binary.Read(reader, byteOrder, &nsec)
-			*v = time.Unix(0, nsec)
+			*v = time.Unix(0, nsec).UTC()
 		case **big.Int:
 			var d BigInt
 			err = d.Decode(reader)
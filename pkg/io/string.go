@@ -15,45 +15,90 @@
 package io
 
 import (
+	"bytes"
 	"encoding/binary"
 	"io"
+	"math"
 
 	"github.com/pkg/errors"
 )
 
-// encodeString writes the length as an uint16 and then the string itself to the io.Writer.
+// shortLengthLimit is the uint16 length prefix that signals that the actual
+// length follows as an uint32. Shorter lengths are written as uint16 only, so
+// their encoding is unchanged from before long values were supported.
+const shortLengthLimit = math.MaxUint16
+
+// encodeString writes the length and then the string itself to the
+// io.Writer. See encodeLongBytes.
 func encodeString(w io.Writer, s string) error {
-	l := uint16(len(s))
-	if int(l) != len(s) {
-		return errors.Errorf("string length exceeded: %d", len(s))
+	return errors.WithMessage(encodeLongBytes(w, []byte(s)), "encoding string")
+}
+
+// decodeString reads the length and then the string itself from the
+// io.Reader. If r is an Allocator, the string length is deducted from its
+// budget.
+func decodeString(r io.Reader, s *string) error {
+	buf, err := decodeLongBytes(r)
+	if err != nil {
+		return errors.WithMessage(err, "decoding string")
 	}
+	*s = string(buf)
+	return nil
+}
 
-	if err := binary.Write(w, byteOrder, l); err != nil {
-		return errors.Wrap(err, "failed to write string length")
+// encodeLongBytes writes the length and then b itself to the io.Writer. Lengths
+// below 0xFFFF are written as uint16, longer ones as the uint16 0xFFFF
+// followed by the length as uint32.
+func encodeLongBytes(w io.Writer, b []byte) error {
+	if uint64(len(b)) > math.MaxUint32 {
+		return errors.Errorf("length exceeded: %d", len(b))
+	}
+	if len(b) < shortLengthLimit {
+		if err := binary.Write(w, byteOrder, uint16(len(b))); err != nil {
+			return errors.Wrap(err, "failed to write length")
+		}
+	} else if err := binary.Write(w, byteOrder, uint16(shortLengthLimit)); err != nil {
+		return errors.Wrap(err, "failed to write long length marker")
+	} else if err := binary.Write(w, byteOrder, uint32(len(b))); err != nil {
+		return errors.Wrap(err, "failed to write long length")
 	}
 
-	// Early exit. Plus, io.WriteString will complain about a closed io.Writer
+	// Early exit. Plus, io.Writer will complain about a closed io.Writer
 	// even if there is nothing left to write
-	if l == 0 {
+	if len(b) == 0 {
 		return nil
 	}
 
-	_, err := io.WriteString(w, s)
-	return errors.Wrap(err, "failed to write string")
+	_, err := w.Write(b)
+	return errors.Wrap(err, "failed to write bytes")
 }
 
-// decodeString reads the length as uint16 and the the string itself from the io.Reader.
-func decodeString(r io.Reader, s *string) error {
-	var l uint16
-	if err := binary.Read(r, byteOrder, &l); err != nil {
-		return errors.Wrap(err, "failed to read string length")
+// decodeLongBytes reads a length written by encodeLongBytes and then as many
+// bytes from the io.Reader. If r is an Allocator, the length is deducted
+// from its budget.
+func decodeLongBytes(r io.Reader) ([]byte, error) {
+	var short uint16
+	if err := binary.Read(r, byteOrder, &short); err != nil {
+		return nil, errors.Wrap(err, "failed to read length")
+	}
+	l := uint32(short)
+	if short == shortLengthLimit {
+		if err := binary.Read(r, byteOrder, &l); err != nil {
+			return nil, errors.Wrap(err, "failed to read long length")
+		}
+	}
+	if err := AllocBudget(r, int(l)); err != nil {
+		return nil, err
 	}
 
-	buf := make([]byte, l)
-	_, err := io.ReadFull(r, buf)
-	if err != nil {
-		return errors.Wrap(err, "failed to read string")
+	if short < shortLengthLimit {
+		buf := make([]byte, l)
+		_, err := io.ReadFull(r, buf)
+		return buf, errors.Wrap(err, "failed to read bytes")
 	}
-	*s = string(buf)
-	return nil
+	// Long values are read incrementally so that a corrupt length does not
+	// allocate the whole announced length upfront.
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, r, int64(l))
+	return buf.Bytes(), errors.Wrapf(err, "failed to read bytes, read %d/%d", n, l)
 }
@@ -52,11 +52,34 @@ func TestEncodeDecodeString(t *testing.T) {
 		}
 	})
 
-	t.Run("too long string", func(t *testing.T) {
-		tooLong := string(append(uint16buf, 42))
+	t.Run("long strings", func(t *testing.T) {
+		long := string(append(uint16buf, 42))
+		for _, tc := range []struct {
+			s      string
+			prefix int
+		}{
+			{string(uint16buf[:math.MaxUint16-1]), 2},
+			{string(uint16buf), 6},
+			{long, 6},
+		} {
+			var buf bytes.Buffer
+			assert.NoError(encodeString(&buf, tc.s))
+			assert.Equal(len(tc.s)+tc.prefix, buf.Len(), "length prefix should be %d bytes", tc.prefix)
+
+			var d string
+			assert.NoError(decodeString(&buf, &d))
+			assert.Equal(tc.s, d)
+		}
+	})
+
+	t.Run("short long stream", func(t *testing.T) {
 		var buf bytes.Buffer
-		assert.Error(encodeString(&buf, tooLong))
-		assert.Zero(buf.Len(), "nothing should have been written to the stream")
+		binary.Write(&buf, byteOrder, uint16(math.MaxUint16))
+		binary.Write(&buf, byteOrder, uint32(math.MaxUint32))
+		buf.Write(make([]byte, 8))
+
+		var d string
+		assert.Error(decodeString(&buf, &d))
 	})
 
 	t.Run("short stream", func(t *testing.T) {
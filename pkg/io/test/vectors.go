@@ -0,0 +1,67 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	perunio "perun.network/go-perun/pkg/io"
+)
+
+// Vector is a conformance test vector. It holds the binary encoding of a named
+// value, so that other implementations can check that they encode and decode
+// the value byte for byte like go-perun.
+type Vector struct {
+	Name     string `json:"name"`
+	Encoding string `json:"encoding"` // Hex encoding of the binary encoding.
+}
+
+// NewVector creates a test vector with the given name from the encoding of v.
+func NewVector(name string, v perunio.Encoder) (Vector, error) {
+	var buf bytes.Buffer
+	if err := v.Encode(&buf); err != nil {
+		return Vector{}, err
+	}
+	return Vector{Name: name, Encoding: hex.EncodeToString(buf.Bytes())}, nil
+}
+
+// GoldenVectorTest checks that vectors equal the vectors stored as JSON in the
+// golden file at path. If update is true, the golden file is (re)written with
+// vectors instead.
+func GoldenVectorTest(t *testing.T, path string, update bool, vectors []Vector) {
+	t.Helper()
+	if update {
+		data, err := json.MarshalIndent(vectors, "", "\t")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, ioutil.WriteFile(path, append(data, '\n'), 0o644)) // nolint:gosec
+		return
+	}
+
+	data, err := ioutil.ReadFile(path) // nolint:gosec
+	require.NoError(t, err, "reading golden file, regenerate it with the update flag")
+	var golden []Vector
+	require.NoError(t, json.Unmarshal(data, &golden))
+	assert.Equal(t, golden, vectors, "vectors differ from golden file %s", path)
+}
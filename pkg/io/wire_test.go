@@ -15,6 +15,7 @@
 package io
 
 import (
+	"bytes"
 	"io"
 	"math/big"
 	"reflect"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	peruntest "perun.network/go-perun/pkg/test"
 )
@@ -53,6 +55,22 @@ func TestWrongTypes(t *testing.T) {
 	peruntest.CheckPanic(func() { Decode(r, d...) })
 }
 
+func TestEncodeTo(t *testing.T) {
+	values := []interface{}{true, uint16(0x1234), [32]byte{1, 2, 3}, []byte{4, 5}, "perun"}
+	var expected bytes.Buffer
+	require.NoError(t, Encode(&expected, values...))
+
+	prefix := []byte{0xFF}
+	buf, err := EncodeTo(append(make([]byte, 0, 64), prefix...), values...)
+	require.NoError(t, err)
+	assert.Equal(t, append(prefix, expected.Bytes()...), buf, "should append the encoding")
+
+	again, err := EncodeTo(buf[:0], values...)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Bytes(), again)
+	assert.Equal(t, &buf[0], &again[0], "should reuse the buffer")
+}
+
 func TestEncodeDecode(t *testing.T) {
 	a := assert.New(t)
 	r, w := io.Pipe()
@@ -75,7 +93,7 @@ func TestEncodeDecode(t *testing.T) {
 		int32(0x123567),
 		int64(0x1234567890123456),
 		// The time has to be constructed this way, because otherwise DeepEqual fails.
-		time.Unix(0, time.Now().UnixNano()),
+		time.Unix(0, time.Now().UnixNano()).UTC(),
 		big.NewInt(0x1234567890123456),
 		longInt,
 		byte32,
@@ -111,3 +129,23 @@ func TestEncodeDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestEncodeDecodeTime(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	for _, tm := range []time.Time{
+		time.Date(2021, 8, 5, 12, 30, 0, 123456789, zone),
+		minTime,
+		maxTime,
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, tm))
+		var decoded time.Time
+		require.NoError(t, Decode(&buf, &decoded))
+		assert.True(t, tm.Equal(decoded), "should encode the same instant")
+		assert.Equal(t, time.UTC, decoded.Location(), "should decode in UTC")
+	}
+
+	for _, tm := range []time.Time{{}, minTime.Add(-1), maxTime.Add(1)} {
+		assert.Error(t, Encode(new(bytes.Buffer), tm), "encoding %v should fail", tm)
+	}
+}
@@ -31,6 +31,11 @@ type Address interface {
 	wallet.Address
 }
 
+// AddrKey is a non-human readable representation of an Address. It can be
+// compared and therefore used as a key in a map. Sets and maps of peers should
+// be keyed by it, so that they do not depend on the Address implementation.
+type AddrKey = wallet.AddrKey
+
 // Addresses is a helper type for encoding and decoding address slices in
 // situations where the length of the slice is known.
 type Addresses []Address
@@ -39,6 +44,12 @@ type Addresses []Address
 // of unknown length.
 type AddressesWithLen []Address
 
+// Key returns the AddrKey of the given address.
+// Panics when the address can't be encoded.
+func Key(a Address) AddrKey {
+	return wallet.Key(a)
+}
+
 // DecodeAddress decodes a peer address.
 func DecodeAddress(r stdio.Reader) (Address, error) {
 	return wallet.DecodeAddress(r)
@@ -88,6 +99,24 @@ func (a *AddressesWithLen) Decode(r stdio.Reader) error {
 	return nil
 }
 
+// Equal returns whether a and b contain equal addresses in the same order.
+func (a Addresses) Equal(b Addresses) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, x := range a {
+		if !x.Equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns whether a and b contain equal addresses in the same order.
+func (a AddressesWithLen) Equal(b AddressesWithLen) bool {
+	return Addresses(a).Equal(Addresses(b))
+}
+
 // IndexOfAddr returns the index of the given address in the address slice,
 // or -1 if it is not part of the slice.
 func IndexOfAddr(addrs []Address, addr Address) int {
@@ -0,0 +1,57 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	_ "perun.network/go-perun/backend/ethereum/wallet/test" // random init
+	pkgtest "perun.network/go-perun/pkg/test"
+	wallettest "perun.network/go-perun/wallet/test"
+)
+
+func TestKey(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	a := wallettest.NewRandomAddress(rng)
+	b := wallettest.NewRandomAddress(rng)
+	clone, err := DecodeAddress(bytesOf(t, a))
+	assert.NoError(t, err)
+
+	peers := map[AddrKey]Address{Key(a): a}
+	assert.Contains(t, peers, Key(clone), "equal addresses should have the same key")
+	assert.NotContains(t, peers, Key(b), "different addresses should have different keys")
+}
+
+func TestAddresses_Equal(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	a, b := wallettest.NewRandomAddress(rng), wallettest.NewRandomAddress(rng)
+
+	assert.True(t, Addresses{}.Equal(nil))
+	assert.True(t, Addresses{a, b}.Equal(Addresses{a, b}))
+	assert.False(t, Addresses{a, b}.Equal(Addresses{b, a}), "order matters")
+	assert.False(t, Addresses{a}.Equal(Addresses{a, b}))
+	assert.True(t, AddressesWithLen{a, b}.Equal(AddressesWithLen{a, b}))
+	assert.False(t, AddressesWithLen{a}.Equal(AddressesWithLen{b}))
+}
+
+func bytesOf(t *testing.T, a Address) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	assert.NoError(t, a.Encode(&buf))
+	return &buf
+}
@@ -14,6 +14,14 @@
 
 package wire
 
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrPeerUnreachable is returned by a Publisher if the connection to the
+// recipient of an envelope could not be established or was lost.
+var ErrPeerUnreachable = errors.New("peer unreachable")
+
 // A Bus is a central message bus over which all clients of a channel network
 // communicate. It is used as the transport layer abstraction for the
 // client.Client.
@@ -24,3 +32,20 @@ type Bus interface {
 	// the provided Consumer. Every address may only be subscribed to once.
 	SubscribeClient(c Consumer, clientAddr Address) error
 }
+
+// A Disconnecter can close the connection to a peer, e.g., to stop
+// communicating with a misbehaving peer. Buses may implement it optionally.
+type Disconnecter interface {
+	// Disconnect closes the connection to the given peer. Later messages to or
+	// from the peer may establish a new connection.
+	Disconnect(peer Address) error
+}
+
+// A DisconnectNotifier reports lost connections to peers. Buses may implement
+// it optionally.
+type DisconnectNotifier interface {
+	// Disconnected returns a channel that is closed when the current
+	// connection to the given peer is lost. Returns nil if there is no
+	// connection to the peer.
+	Disconnected(peer Address) <-chan struct{}
+}
@@ -46,7 +46,7 @@ func newPingPongMsg() pingPongMsg {
 	// data specific to the current process which breaks, e.g.,
 	// `reflect.DeepEqual`, cf. "Marshal/Unmarshal functions are asymmetrical"
 	// https://github.com/golang/go/issues/19502
-	return pingPongMsg{Created: time.Unix(0, time.Now().UnixNano())}
+	return pingPongMsg{Created: time.Unix(0, time.Now().UnixNano()).UTC()}
 }
 
 // PingMsg is a ping request.
@@ -15,6 +15,7 @@
 package wire
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strconv"
@@ -52,6 +53,12 @@ func (env *Envelope) Encode(w io.Writer) error {
 	return Encode(env.Msg, w)
 }
 
+// EncodeTo appends the encoding of the Envelope to buf and returns the
+// extended buffer. It produces the same encoding as Encode.
+func (env *Envelope) EncodeTo(buf []byte) ([]byte, error) {
+	return perunio.EncodeTo(buf, env)
+}
+
 // Decode decodes an Envelope from an io.Reader.
 func (env *Envelope) Decode(r io.Reader) (err error) {
 	if env.Sender, err = DecodeAddress(r); err != nil {
@@ -64,6 +71,38 @@ func (env *Envelope) Decode(r io.Reader) (err error) {
 	return err
 }
 
+// PeekType reads the header of an encoded Envelope from r up to the message
+// type and returns the type without decoding the message. The returned reader
+// yields the complete encoded Envelope, including the already read header, so
+// that the Envelope can be decoded from it later or forwarded as is. If r is a
+// perunio.Allocator, so is the returned reader.
+func PeekType(r io.Reader) (Type, io.Reader, error) {
+	var header bytes.Buffer
+	tee := io.TeeReader(r, &header)
+	if _, err := DecodeAddress(tee); err != nil {
+		return 0, nil, errors.WithMessage(err, "decoding sender")
+	}
+	if _, err := DecodeAddress(tee); err != nil {
+		return 0, nil, errors.WithMessage(err, "decoding recipient")
+	}
+	t, err := decodeType(tee)
+	if err != nil {
+		return 0, nil, errors.WithMessage(err, "failed to decode message Type")
+	}
+	rest := io.MultiReader(&header, r)
+	if a, ok := r.(perunio.Allocator); ok {
+		return t, &allocReader{Reader: rest, Allocator: a}, nil
+	}
+	return t, rest, nil
+}
+
+// allocReader forwards the allocations of decoders that read from its Reader
+// to the Allocator of the reader that it wraps.
+type allocReader struct {
+	io.Reader
+	perunio.Allocator
+}
+
 // Encode encodes a message into an io.Writer. It also encodes the
 // message type whereas the Msg.Encode implementation is assumed not to write
 // the type.
@@ -73,19 +112,36 @@ func Encode(msg Msg, w io.Writer) error {
 }
 
 // Decode decodes a message from an io.Reader.
+//
+// The message type is looked up in the decoder registry, which is indexed by
+// the type byte, so that decoding dispatches directly to the registered
+// decoder. If r is an io.ByteReader, the type byte is read without allocating.
 func Decode(r io.Reader) (Msg, error) {
-	var t Type
-	if err := perunio.Decode(r, (*byte)(&t)); err != nil {
+	t, err := decodeType(r)
+	if err != nil {
 		return nil, errors.WithMessage(err, "failed to decode message Type")
 	}
 
-	if !t.Valid() {
+	decoder := decoders[t]
+	if decoder == nil {
 		return nil, errors.Errorf("wire: no decoder known for message Type): %v", t)
 	}
-	return decoders[t](r)
+	return decoder(r)
+}
+
+func decodeType(r io.Reader) (Type, error) {
+	if br, ok := r.(io.ByteReader); ok {
+		b, err := br.ReadByte()
+		return Type(b), errors.WithStack(err)
+	}
+	var t Type
+	err := perunio.Decode(r, (*byte)(&t))
+	return t, err
 }
 
-var decoders = make(map[Type]func(io.Reader) (Msg, error))
+// decoders maps every message Type to its decoder. It is an array instead of a
+// map because the decoder lookup is on the hot path of message decoding.
+var decoders [1 << 8]func(io.Reader) (Msg, error)
 
 // RegisterDecoder sets the decoder of messages of Type `t`.
 func RegisterDecoder(t Type, decoder func(io.Reader) (Msg, error)) {
@@ -96,13 +152,28 @@ func RegisterDecoder(t Type, decoder func(io.Reader) (Msg, error)) {
 	decoders[t] = decoder
 }
 
+// WithDecoder temporarily sets the decoder of messages of Type `t` to
+// `decoder` while running fn and restores the previously registered decoder
+// afterwards, even if fn panics. In contrast to RegisterDecoder, it does not
+// panic if a decoder is already set, so that tests can exercise alternative
+// decoders in the same test binary. It must not be called concurrently with
+// other (de)registrations or decodings.
+func WithDecoder(t Type, decoder func(io.Reader) (Msg, error), fn func()) {
+	prev := decoders[t]
+	decoders[t] = decoder
+	defer func() { decoders[t] = prev }()
+	fn()
+}
+
 // RegisterExternalDecoder sets the decoder of messages of external type `t`.
 // This is like RegisterDecoder but for message types not part of the Perun wire
 // protocol and thus not known natively. This can be used by users of the
 // framework to create additional message types and send them over the same
 // peer connection. It also comes in handy to register types for testing.
+// External types must not be below LastType or in the reserved range from
+// FirstReservedType to LastReservedType.
 func RegisterExternalDecoder(t Type, decoder func(io.Reader) (Msg, error), name string) {
-	if t < LastType {
+	if t < LastType || (FirstReservedType <= t && t <= LastReservedType) {
 		panic("external decoders can only be registered for alien types")
 	}
 	RegisterDecoder(t, decoder)
@@ -137,24 +208,54 @@ const (
 	LastType // upper bound on the message types of the Perun wire protocol
 )
 
+// The types from FirstReservedType to LastReservedType are reserved for
+// message types of the Perun wire protocol that were added after LastType was
+// fixed. This keeps the external types from LastType on stable.
+const (
+	FirstReservedType Type = 0x80
+	LastReservedType  Type = 0x9f
+)
+
+// Message types of the Perun wire protocol in the reserved range.
+const (
+	ChannelUpdateBatch Type = FirstReservedType + iota
+	ChannelUpdateRejWithCode
+	ChannelProposalRejWithCode
+	ChannelUpdateWithPayload
+	ChannelSyncReply
+	VirtualChannelProposalWithFee
+	VirtualChannelFundingProposalWithFee
+	endReservedTypes
+)
+
+// Fails to compile if the reserved range is exhausted.
+const _ = LastReservedType + 1 - endReservedTypes
+
 var typeNames = map[Type]string{
-	Ping:                             "Ping",
-	Pong:                             "Pong",
-	Shutdown:                         "Shutdown",
-	AuthResponse:                     "AuthResponse",
-	LedgerChannelProposal:            "LedgerChannelProposal",
-	LedgerChannelProposalAcc:         "LedgerChannelProposalAcc",
-	SubChannelProposal:               "SubChannelProposal",
-	SubChannelProposalAcc:            "SubChannelProposalAcc",
-	VirtualChannelProposal:           "VirtualChannelProposal",
-	VirtualChannelProposalAcc:        "VirtualChannelProposalAcc",
-	ChannelProposalRej:               "ChannelProposalRej",
-	ChannelUpdate:                    "ChannelUpdate",
-	VirtualChannelFundingProposal:    "VirtualChannelFundingProposal",
-	VirtualChannelSettlementProposal: "VirtualChannelSettlementProposal",
-	ChannelUpdateAcc:                 "ChannelUpdateAcc",
-	ChannelUpdateRej:                 "ChannelUpdateRej",
-	ChannelSync:                      "ChannelSync",
+	Ping:                                 "Ping",
+	Pong:                                 "Pong",
+	Shutdown:                             "Shutdown",
+	AuthResponse:                         "AuthResponse",
+	LedgerChannelProposal:                "LedgerChannelProposal",
+	LedgerChannelProposalAcc:             "LedgerChannelProposalAcc",
+	SubChannelProposal:                   "SubChannelProposal",
+	SubChannelProposalAcc:                "SubChannelProposalAcc",
+	VirtualChannelProposal:               "VirtualChannelProposal",
+	VirtualChannelProposalAcc:            "VirtualChannelProposalAcc",
+	ChannelProposalRej:                   "ChannelProposalRej",
+	ChannelUpdate:                        "ChannelUpdate",
+	VirtualChannelFundingProposal:        "VirtualChannelFundingProposal",
+	VirtualChannelSettlementProposal:     "VirtualChannelSettlementProposal",
+	ChannelUpdateAcc:                     "ChannelUpdateAcc",
+	ChannelUpdateRej:                     "ChannelUpdateRej",
+	ChannelSync:                          "ChannelSync",
+	ChannelUpdateBatch:                   "ChannelUpdateBatch",
+	ChannelUpdateRejWithCode:             "ChannelUpdateRejWithCode",
+	ChannelProposalRejWithCode:           "ChannelProposalRejWithCode",
+	ChannelUpdateWithPayload:             "ChannelUpdateWithPayload",
+	ChannelSyncReply:                     "ChannelSyncReply",
+	VirtualChannelProposalWithFee:        "VirtualChannelProposalWithFee",
+	VirtualChannelFundingProposalWithFee: "VirtualChannelFundingProposalWithFee",
 }
 
 // String returns the name of a message type if it is valid and name known
@@ -169,6 +270,5 @@ func (t Type) String() string {
 
 // Valid checks whether a decoder is known for the type.
 func (t Type) Valid() bool {
-	_, ok := decoders[t]
-	return ok
+	return decoders[t] != nil
 }
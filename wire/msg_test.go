@@ -15,13 +15,17 @@
 package wire
 
 import (
+	"bytes"
 	"io"
 	"math/rand"
 	"strconv"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	perunio "perun.network/go-perun/pkg/io"
 	iotest "perun.network/go-perun/pkg/io/test"
 	"perun.network/go-perun/pkg/test"
 	wtest "perun.network/go-perun/wallet/test"
@@ -67,9 +71,84 @@ func TestRegisterExternalDecoder(t *testing.T) {
 		func() { RegisterExternalDecoder(Ping, nilDecoder, "PingFail") },
 		"registration of internal type should fail",
 	)
+	assert.Panics(t,
+		func() { RegisterExternalDecoder(LastReservedType, nilDecoder, "ReservedFail") },
+		"registration of reserved type should fail",
+	)
+}
+
+func TestType_stableNumbering(t *testing.T) {
+	// External types are numbered relative to LastType, which must not change.
+	assert.Equal(t, Type(17), LastType)
+	assert.Equal(t, Type(16), ChannelSync)
+	assert.Equal(t, FirstReservedType, ChannelUpdateBatch)
+}
+
+func TestWithDecoder(t *testing.T) {
+	const testType = Type(250)
+	errDecoder := func(io.Reader) (Msg, error) { return nil, errors.New("custom decoder") }
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(NewPingMsg(), &buf))
+	enc := buf.Bytes()
+
+	WithDecoder(Ping, errDecoder, func() {
+		_, err := Decode(bytes.NewReader(enc))
+		assert.EqualError(t, err, "custom decoder")
+	})
+	msg, err := Decode(bytes.NewReader(enc))
+	require.NoError(t, err, "original decoder should be restored")
+	assert.Equal(t, Ping, msg.Type())
+
+	WithDecoder(testType, nilDecoder, func() {
+		assert.True(t, testType.Valid())
+	})
+	assert.False(t, testType.Valid(), "temporary decoder should be removed")
+}
+
+func TestPeekType(t *testing.T) {
+	env := NewRandomEnvelope(test.Prng(t), NewPingMsg())
+	var buf bytes.Buffer
+	require.NoError(t, env.Encode(&buf))
+
+	typ, r, err := PeekType(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, Ping, typ)
+
+	var decoded Envelope
+	require.NoError(t, decoded.Decode(r), "returned reader should yield the whole envelope")
+	assert.Equal(t, env, &decoded)
+
+	_, _, err = PeekType(bytes.NewReader(nil))
+	assert.Error(t, err)
+
+	require.NoError(t, env.Encode(&buf))
+	budget := perunio.NewBudgetReader(&buf, 0)
+	_, r, err = PeekType(budget)
+	require.NoError(t, err)
+	_, ok := r.(perunio.Allocator)
+	assert.True(t, ok, "returned reader should keep the allocation budget")
 }
 
 func TestEnvelope_EncodeDecode(t *testing.T) {
 	ping := NewRandomEnvelope(test.Prng(t), NewPingMsg())
 	iotest.GenericSerializerTest(t, ping)
 }
+
+func BenchmarkDecode(b *testing.B) {
+	var buf bytes.Buffer
+	if err := Encode(NewPingMsg(), &buf); err != nil {
+		b.Fatal(err)
+	}
+	enc := buf.Bytes()
+	r := bytes.NewReader(enc)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Reset(enc)
+		if _, err := Decode(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/log"
-	"perun.network/go-perun/wallet"
 	"perun.network/go-perun/wire"
 )
 
@@ -30,7 +29,7 @@ import (
 type Bus struct {
 	reg      *EndpointRegistry
 	mainRecv *wire.Receiver
-	recvs    map[wallet.AddrKey]wire.Consumer
+	recvs    map[wire.AddrKey]wire.Consumer
 	mutex    sync.RWMutex // Protects reg, recv.
 }
 
@@ -48,7 +47,7 @@ const (
 func NewBus(id wire.Account, d Dialer) *Bus {
 	b := &Bus{
 		mainRecv: wire.NewReceiver(),
-		recvs:    make(map[wallet.AddrKey]wire.Consumer),
+		recvs:    make(map[wire.AddrKey]wire.Consumer),
 	}
 
 	onNewEndpoint := func(wire.Address) wire.Consumer { return b.mainRecv }
@@ -74,7 +73,9 @@ func (b *Bus) SubscribeClient(c wire.Consumer, addr wire.Address) error {
 
 // Publish sends an envelope to its recipient. Automatically establishes a
 // communication channel to the recipient using the bus' dialer. Only returns
-// when the context is aborted or the envelope was sent successfully.
+// when the context is aborted, the envelope was sent successfully, or all
+// PublishAttempts failed. In the latter case, the returned error wraps
+// wire.ErrPeerUnreachable.
 func (b *Bus) Publish(ctx context.Context, e *wire.Envelope) (err error) {
 	for attempt := 1; attempt <= PublishAttempts; attempt++ {
 		log.Tracef("Bus.Publish attempt: %d/%d", attempt, PublishAttempts)
@@ -99,7 +100,19 @@ func (b *Bus) Publish(ctx context.Context, e *wire.Envelope) (err error) {
 		case <-time.After(PublishCooldown):
 		}
 	}
-	return
+	return errors.Wrap(wire.ErrPeerUnreachable, err.Error())
+}
+
+// Disconnect closes the connection to the given peer. Returns an error if
+// there is no connection to the peer.
+func (b *Bus) Disconnect(peer wire.Address) error {
+	return b.reg.Disconnect(peer)
+}
+
+// Disconnected returns a channel that is closed when the current connection
+// to the given peer is lost. Returns nil if there is no connection to the peer.
+func (b *Bus) Disconnected(peer wire.Address) <-chan struct{} {
+	return b.reg.Disconnected(peer)
 }
 
 // Close closes the bus and terminates its goroutines.
@@ -119,11 +132,11 @@ func (b *Bus) addSubscriber(c wire.Consumer, addr wire.Address) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	if _, ok := b.recvs[wallet.Key(addr)]; ok {
+	if _, ok := b.recvs[wire.Key(addr)]; ok {
 		log.Panic("duplicate SubscribeClient")
 	}
 
-	b.recvs[wallet.Key(addr)] = c
+	b.recvs[wire.Key(addr)] = c
 }
 
 // ctx returns the context of the bus' registry.
@@ -141,7 +154,7 @@ func (b *Bus) dispatchMsgs() {
 		}
 
 		b.mutex.Lock()
-		r, ok := b.recvs[wallet.Key(e.Recipient)]
+		r, ok := b.recvs[wire.Key(e.Recipient)]
 		b.mutex.Unlock()
 		if !ok {
 			log.WithField("sender", e.Sender).
@@ -157,9 +170,9 @@ func (b *Bus) removeSubscriber(addr wire.Address) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	if _, ok := b.recvs[wallet.Key(addr)]; !ok {
+	if _, ok := b.recvs[wire.Key(addr)]; !ok {
 		log.Panic("deleting nonexisting subscriber")
 	}
 
-	delete(b.recvs, wallet.Key(addr))
+	delete(b.recvs, wire.Key(addr))
 }
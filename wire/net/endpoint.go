@@ -36,7 +36,8 @@ type Endpoint struct {
 	Address wire.Address // The Endpoint's Perun address.
 	conn    Conn         // The Endpoint's connection.
 
-	sending sync.Mutex // Blocks multiple Send calls.
+	sending sync.Mutex    // Blocks multiple Send calls.
+	lost    chan struct{} // Closed when recvLoop returns.
 }
 
 // recvLoop continuously receives messages from an Endpoint until it is closed.
@@ -46,6 +47,7 @@ type Endpoint struct {
 // Does not return an error when the Endpoint closing fails or when
 // conn.Recv returns io.EOF, which indicates connection closing for TCP.
 func (p *Endpoint) recvLoop(c wire.Consumer) error {
+	defer close(p.lost)
 	for {
 		e, err := p.conn.Recv()
 		if err != nil {
@@ -102,6 +104,7 @@ func newEndpoint(addr wire.Address, conn Conn) *Endpoint {
 	return &Endpoint{
 		Address: addr,
 		conn:    conn,
+		lost:    make(chan struct{}),
 	}
 }
 
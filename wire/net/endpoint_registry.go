@@ -68,8 +68,8 @@ type EndpointRegistry struct {
 	dialer        Dialer                           // Used for dialing peers.
 	onNewEndpoint func(wire.Address) wire.Consumer // Selects Consumer for new Endpoints' receive loop.
 
-	endpoints map[wallet.AddrKey]*fullEndpoint // The list of all of all established Endpoints.
-	dialing   map[wallet.AddrKey]*dialingEndpoint
+	endpoints map[wire.AddrKey]*fullEndpoint // The list of all of all established Endpoints.
+	dialing   map[wire.AddrKey]*dialingEndpoint
 	mutex     sync.RWMutex // protects peers and dialing.
 
 	log.Embedding
@@ -87,8 +87,8 @@ func NewEndpointRegistry(id wire.Account, onNewEndpoint func(wire.Address) wire.
 		onNewEndpoint: onNewEndpoint,
 		dialer:        dialer,
 
-		endpoints: make(map[wallet.AddrKey]*fullEndpoint),
-		dialing:   make(map[wallet.AddrKey]*dialingEndpoint),
+		endpoints: make(map[wire.AddrKey]*fullEndpoint),
+		dialing:   make(map[wire.AddrKey]*dialingEndpoint),
 
 		Embedding: log.MakeEmbedding(log.WithField("id", id.Address())),
 	}
@@ -122,6 +122,27 @@ func (r *EndpointRegistry) Close() (err error) {
 	return
 }
 
+// Disconnect closes the connection to the given peer. Returns an error if
+// there is no connection to the peer.
+func (r *EndpointRegistry) Disconnect(addr wire.Address) error {
+	e := r.find(addr)
+	if e == nil {
+		return errors.Errorf("no connection to peer %v", addr)
+	}
+	r.Log().WithField("peer", addr).Debug("Disconnecting peer.")
+	return e.Close()
+}
+
+// Disconnected returns a channel that is closed when the current connection
+// to the given peer is lost. Returns nil if there is no connection to the peer.
+func (r *EndpointRegistry) Disconnected(addr wire.Address) <-chan struct{} {
+	e := r.find(addr)
+	if e == nil {
+		return nil
+	}
+	return e.lost
+}
+
 // Listen starts listening for incoming connections on the provided listener and
 // currently just automatically accepts them after successful authentication.
 // This function does not start go routines but instead should be started by the
@@ -184,7 +205,7 @@ func (r *EndpointRegistry) setupConn(conn Conn) error {
 // context is closed.
 func (r *EndpointRegistry) Get(ctx context.Context, addr wire.Address) (*Endpoint, error) {
 	log := r.Log().WithField("peer", addr)
-	key := wallet.Key(addr)
+	key := wire.Key(addr)
 
 	if addr.Equals(r.id.Address()) {
 		log.Panic("tried to dial self")
@@ -215,7 +236,7 @@ func (r *EndpointRegistry) authenticatedDial(
 	addr wire.Address,
 	de *dialingEndpoint,
 	created bool) (ret *Endpoint, _ error) {
-	key := wallet.Key(addr)
+	key := wire.Key(addr)
 
 	// Short cut: another dial for that peer is already in progress.
 	if !created {
@@ -256,7 +277,7 @@ func (r *EndpointRegistry) authenticatedDial(
 }
 
 func (r *EndpointRegistry) getOrCreateDialingEndpoint(a wallet.Address) (_ *dialingEndpoint, created bool) {
-	key := wallet.Key(a)
+	key := wire.Key(a)
 	entry, ok := r.dialing[key]
 	if !ok {
 		entry = newDialingEndpoint(a)
@@ -281,7 +302,7 @@ func (r *EndpointRegistry) Has(addr wire.Address) bool {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	_, ok := r.endpoints[wallet.Key(addr)]
+	_, ok := r.endpoints[wire.Key(addr)]
 
 	return ok
 }
@@ -311,7 +332,7 @@ func (r *EndpointRegistry) addEndpoint(addr wire.Address, conn Conn, dialer bool
 }
 
 func (r *EndpointRegistry) getOrCreateFullEndpoint(addr wire.Address, e *Endpoint) (_ *fullEndpoint, created bool) {
-	key := wallet.Key(addr)
+	key := wire.Key(addr)
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 	entry, ok := r.endpoints[key]
@@ -365,7 +386,7 @@ func (p *fullEndpoint) delete(expectedOldValue *Endpoint) {
 func (r *EndpointRegistry) find(addr wire.Address) *Endpoint {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
-	if e, ok := r.endpoints[wallet.Key(addr)]; ok {
+	if e, ok := r.endpoints[wire.Key(addr)]; ok {
 		return e.Endpoint()
 	}
 	return nil
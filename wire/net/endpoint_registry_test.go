@@ -344,6 +344,46 @@ func TestRegistry_addEndpoint_Subscribe(t *testing.T) {
 	assert.True(t, called, "onNewEndpoint must have been called")
 }
 
+func TestRegistry_Disconnect(t *testing.T) {
+	t.Parallel()
+	rng := test.Prng(t)
+	r := NewEndpointRegistry(wallettest.NewRandomAccount(rng), nilConsumer, nil)
+	addr := wallettest.NewRandomAddress(rng)
+
+	assert.Error(t, r.Disconnect(addr), "unknown peer")
+
+	conn := newMockConn()
+	r.addEndpoint(addr, conn, false)
+	require.NoError(t, r.Disconnect(addr))
+	assert.True(t, conn.closed.IsSet(), "connection should be closed")
+}
+
+func TestRegistry_Disconnected(t *testing.T) {
+	t.Parallel()
+	rng := test.Prng(t)
+	r := NewEndpointRegistry(wallettest.NewRandomAccount(rng), nilConsumer, nil)
+	addr := wallettest.NewRandomAddress(rng)
+
+	assert.Nil(t, r.Disconnected(addr), "unknown peer")
+
+	conn, _ := newPipeConnPair()
+	r.addEndpoint(addr, conn, false)
+	lost := r.Disconnected(addr)
+	require.NotNil(t, lost)
+	select {
+	case <-lost:
+		t.Fatal("connection should not be lost yet")
+	default:
+	}
+
+	require.NoError(t, r.Disconnect(addr))
+	select {
+	case <-lost:
+	case <-time.After(timeout):
+		t.Fatal("connection should be lost")
+	}
+}
+
 func TestRegistry_Close(t *testing.T) {
 	t.Parallel()
 
@@ -16,19 +16,28 @@ package net
 
 import (
 	"io"
+	"sync"
 
 	"github.com/pkg/errors"
 
+	perunio "perun.network/go-perun/pkg/io"
 	"perun.network/go-perun/pkg/sync/atomic"
 	"perun.network/go-perun/wire"
 )
 
 var _ Conn = (*ioConn)(nil)
 
+// maxRecvAlloc is the maximum number of bytes that length-prefixed values of a
+// single received envelope may allocate.
+const maxRecvAlloc = 1 << 20
+
 // ioConn is a connection that communicates its messages over an io stream.
 type ioConn struct {
 	closed atomic.Bool
 	conn   io.ReadWriteCloser
+
+	sendMtx sync.Mutex
+	sendBuf []byte // reused encoding buffer, guarded by sendMtx
 }
 
 // NewIoConn creates a peer message connection from an io stream.
@@ -38,8 +47,17 @@ func NewIoConn(conn io.ReadWriteCloser) Conn {
 	}
 }
 
+// Send encodes the envelope into a buffer and writes it to the stream at once.
 func (c *ioConn) Send(e *wire.Envelope) error {
-	if err := e.Encode(c.conn); err != nil {
+	c.sendMtx.Lock()
+	defer c.sendMtx.Unlock()
+
+	buf, err := e.EncodeTo(c.sendBuf[:0])
+	if err == nil {
+		c.sendBuf = buf
+		_, err = c.conn.Write(buf)
+	}
+	if err != nil {
 		// nolint:errcheck,gosec
 		c.conn.Close()
 		return err
@@ -49,7 +67,7 @@ func (c *ioConn) Send(e *wire.Envelope) error {
 
 func (c *ioConn) Recv() (*wire.Envelope, error) {
 	var e wire.Envelope
-	if err := e.Decode(c.conn); err != nil {
+	if err := e.Decode(perunio.NewBudgetReader(c.conn, maxRecvAlloc)); err != nil {
 		// nolint:errcheck,gosec
 		c.conn.Close()
 		return nil, err
@@ -22,7 +22,6 @@ import (
 
 	"github.com/pkg/errors"
 	pkgsync "perun.network/go-perun/pkg/sync"
-	"perun.network/go-perun/wallet"
 	"perun.network/go-perun/wire"
 	wirenet "perun.network/go-perun/wire/net"
 )
@@ -30,10 +29,11 @@ import (
 // Dialer is a simple lookup-table based dialer that can dial known peers.
 // New peer addresses can be added via Register().
 type Dialer struct {
-	mutex   sync.RWMutex              // Protects peers.
-	peers   map[wallet.AddrKey]string // Known peer addresses.
-	dialer  net.Dialer                // Used to dial connections.
-	network string                    // The socket type.
+	mutex     sync.RWMutex            // Protects peers.
+	peers     map[wire.AddrKey]string // Known peer addresses.
+	dialer    net.Dialer              // Used to dial connections.
+	network   string                  // The socket type.
+	transform Transform               // Applied to dialed connections, may be nil.
 
 	pkgsync.Closer
 }
@@ -46,7 +46,7 @@ var _ wirenet.Dialer = (*Dialer)(nil)
 // controls the type of connection that the dialer can dial.
 func NewNetDialer(network string, defaultTimeout time.Duration) *Dialer {
 	return &Dialer{
-		peers:   make(map[wallet.AddrKey]string),
+		peers:   make(map[wire.AddrKey]string),
 		dialer:  net.Dialer{Timeout: defaultTimeout},
 		network: network,
 	}
@@ -62,7 +62,7 @@ func NewUnixDialer(defaultTimeout time.Duration) *Dialer {
 	return NewNetDialer("unix", defaultTimeout)
 }
 
-func (d *Dialer) get(key wallet.AddrKey) (string, bool) {
+func (d *Dialer) get(key wire.AddrKey) (string, bool) {
 	d.mutex.RLock()
 	defer d.mutex.RUnlock()
 
@@ -75,7 +75,7 @@ func (d *Dialer) Dial(ctx context.Context, addr wire.Address) (wirenet.Conn, err
 	done := make(chan struct{})
 	defer close(done)
 
-	host, ok := d.get(wallet.Key(addr))
+	host, ok := d.get(wire.Key(addr))
 	if !ok {
 		return nil, errors.New("peer not found")
 	}
@@ -97,13 +97,32 @@ func (d *Dialer) Dial(ctx context.Context, addr wire.Address) (wirenet.Conn, err
 		return nil, errors.Wrap(err, "failed to dial peer")
 	}
 
+	// Bound handshakes of the transform by the context's deadline.
+	if deadline, ok := ctx.Deadline(); ok && d.transform != nil {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close() // nolint:errcheck,gosec
+			return nil, errors.Wrap(err, "setting deadline")
+		}
+		defer conn.SetDeadline(time.Time{}) // nolint:errcheck
+	}
+	if conn, err = wrapConn(d.transform, conn); err != nil {
+		return nil, err
+	}
+
 	return wirenet.NewIoConn(conn), nil
 }
 
+// SetTransform sets the transform that is applied to every dialed connection.
+// Use ChainTransforms to apply multiple transforms. Passing nil removes the
+// transform. Should only be called before the Dialer is used.
+func (d *Dialer) SetTransform(t Transform) {
+	d.transform = t
+}
+
 // Register registers a network address for a peer address.
 func (d *Dialer) Register(addr wire.Address, address string) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
-	d.peers[wallet.Key(addr)] = address
+	d.peers[wire.Key(addr)] = address
 }
@@ -0,0 +1,221 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simple
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Frame kinds of a keepalive connection. Data frames carry a length-prefixed
+// chunk of the envelope stream, ping and pong frames consist of the kind only.
+const (
+	kaData byte = iota
+	kaPing
+	kaPong
+)
+
+// ErrKeepAliveTimeout is returned by reads and writes on a keepalive
+// connection that was closed because the peer did not answer a ping in time.
+var ErrKeepAliveTimeout = errors.New("keepalive: peer did not answer ping in time")
+
+// KeepAlive returns a Transform that detects silently dropped connections. It
+// sends a ping every interval and closes the connection if the peer does not
+// answer with a pong within timeout. Pending and further reads and writes
+// then fail with ErrKeepAliveTimeout, which ends the Endpoint's receive loop.
+//
+// The transform frames the data stream so that pings and pongs can be told
+// apart from envelopes, so it must be set on the Dialer and the Listener of
+// both peers. Pings are only answered while the connection is being read,
+// which the wire/net Endpoint does continuously. interval and timeout must be
+// positive.
+func KeepAlive(interval, timeout time.Duration) Transform {
+	if interval <= 0 || timeout <= 0 {
+		panic("keepalive interval and timeout must be positive")
+	}
+	return TransformFunc(func(conn net.Conn) (net.Conn, error) {
+		c := &keepAliveConn{
+			Conn:   conn,
+			pings:  make(chan struct{}, 1),
+			pongs:  make(chan struct{}, 1),
+			closed: make(chan struct{}),
+		}
+		go c.pingLoop(interval, timeout)
+		return c, nil
+	})
+}
+
+// keepAliveConn is a connection that is wrapped by the KeepAlive transform.
+type keepAliveConn struct {
+	net.Conn
+
+	remaining uint32        // Unread bytes of the current data frame.
+	pings     chan struct{} // Signals received pings to the ping loop.
+	pongs     chan struct{} // Signals received pongs to the ping loop.
+
+	writing sync.Mutex // Serializes frame writes.
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	timedOut  bool // Set before closed is closed, if the keepalive timed out.
+}
+
+// Read reads from the current data frame. Ping and pong frames are handled
+// transparently.
+func (c *keepAliveConn) Read(b []byte) (int, error) {
+	for c.remaining == 0 {
+		if err := c.readHeader(); err != nil {
+			return 0, c.wrapErr(err)
+		}
+	}
+
+	if uint32(len(b)) > c.remaining {
+		b = b[:c.remaining]
+	}
+	n, err := c.Conn.Read(b)
+	c.remaining -= uint32(n)
+	return n, c.wrapErr(err)
+}
+
+// readHeader reads the next frame header. Pings and pongs are passed on to
+// the ping loop. For data frames, the frame length is
+// stored in c.remaining.
+func (c *keepAliveConn) readHeader() error {
+	var kind [1]byte
+	if _, err := io.ReadFull(c.Conn, kind[:]); err != nil {
+		return err
+	}
+
+	switch kind[0] {
+	case kaData:
+		var length [4]byte
+		if _, err := io.ReadFull(c.Conn, length[:]); err != nil {
+			return err
+		}
+		c.remaining = binary.BigEndian.Uint32(length[:])
+		return nil
+	case kaPing:
+		signal(c.pings)
+		return nil
+	case kaPong:
+		signal(c.pongs)
+		return nil
+	default:
+		return errors.Errorf("keepalive: unknown frame kind %d", kind[0])
+	}
+}
+
+// signal notifies the receiver of ch without blocking. Pending signals are
+// coalesced.
+func signal(ch chan<- struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Write writes b as a single data frame.
+func (c *keepAliveConn) Write(b []byte) (int, error) {
+	if err := c.writeFrame(kaData, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// writeFrame writes a frame of the given kind. The payload is only written
+// for data frames.
+func (c *keepAliveConn) writeFrame(kind byte, data []byte) error {
+	frame := []byte{kind}
+	if kind == kaData {
+		frame = make([]byte, 5, 5+len(data))
+		frame[0] = kind
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+		frame = append(frame, data...)
+	}
+
+	c.writing.Lock()
+	defer c.writing.Unlock()
+	_, err := c.Conn.Write(frame)
+	return c.wrapErr(err)
+}
+
+// pingLoop sends a ping every interval and closes the connection if no pong
+// arrives within timeout. It also answers the peer's pings, so that reading
+// never blocks on writing.
+func (c *keepAliveConn) pingLoop(interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var deadline <-chan time.Time // Set while a ping is unanswered.
+
+	for {
+		var err error
+		select {
+		case <-ticker.C:
+			if deadline == nil {
+				err = c.writeFrame(kaPing, nil)
+				deadline = time.After(timeout)
+			}
+		case <-c.pongs:
+			deadline = nil
+		case <-c.pings:
+			err = c.writeFrame(kaPong, nil)
+		case <-deadline:
+			c.close(true) // nolint:errcheck,gosec
+			return
+		case <-c.closed:
+			return
+		}
+		if err != nil {
+			c.Close() // nolint:errcheck,gosec
+			return
+		}
+	}
+}
+
+// Close stops the ping loop and closes the underlying connection.
+func (c *keepAliveConn) Close() error {
+	return c.close(false)
+}
+
+func (c *keepAliveConn) close(timedOut bool) (err error) {
+	err = errors.New("connection already closed")
+	c.closeOnce.Do(func() {
+		c.timedOut = timedOut
+		close(c.closed)
+		err = c.Conn.Close()
+	})
+	return err
+}
+
+// wrapErr replaces err with ErrKeepAliveTimeout if the connection was closed
+// because the keepalive timed out.
+func (c *keepAliveConn) wrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	select {
+	case <-c.closed:
+		if c.timedOut {
+			return errors.WithStack(ErrKeepAliveTimeout)
+		}
+	default:
+	}
+	return err
+}
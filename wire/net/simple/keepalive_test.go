@@ -0,0 +1,91 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simple
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepAlive(t *testing.T) {
+	const (
+		interval = 10 * time.Millisecond
+		timeout  = 50 * time.Millisecond
+	)
+
+	t.Run("answering peer", func(t *testing.T) {
+		a, b := net.Pipe()
+		ka, err := KeepAlive(interval, timeout).Wrap(a)
+		require.NoError(t, err)
+		defer ka.Close()
+		kb, err := KeepAlive(interval, timeout).Wrap(b)
+		require.NoError(t, err)
+		defer kb.Close()
+
+		// Both sides read continuously, like the wire/net Endpoint does.
+		go ka.Read(make([]byte, 1)) // nolint:errcheck
+		data := []byte("perun")
+		received := make(chan []byte, 1)
+		go func() {
+			buf := make([]byte, len(data))
+			_, err := io.ReadFull(kb, buf)
+			assert.NoError(t, err)
+			received <- buf
+		}()
+
+		// Let several pings pass before sending the data.
+		time.Sleep(5 * timeout)
+		_, err = ka.Write(data)
+		require.NoError(t, err)
+		assert.Equal(t, data, <-received)
+	})
+
+	t.Run("silent peer", func(t *testing.T) {
+		a, b := net.Pipe()
+		defer b.Close()
+		ka, err := KeepAlive(interval, timeout).Wrap(a)
+		require.NoError(t, err)
+		defer ka.Close()
+		// The peer reads pings but never answers them.
+		go io.Copy(ioutil.Discard, b) // nolint:errcheck
+
+		read := make(chan error, 1)
+		go func() {
+			_, err := ka.Read(make([]byte, 1))
+			read <- err
+		}()
+
+		select {
+		case err := <-read:
+			assert.True(t, errors.Is(err, ErrKeepAliveTimeout))
+		case <-time.After(10 * timeout):
+			t.Fatal("keepalive did not time out")
+		}
+		_, err = ka.Write([]byte{1})
+		assert.True(t, errors.Is(err, ErrKeepAliveTimeout))
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		assert.Panics(t, func() { KeepAlive(0, timeout) })
+		assert.Panics(t, func() { KeepAlive(interval, -1) })
+	})
+}
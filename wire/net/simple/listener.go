@@ -16,14 +16,23 @@ package simple
 
 import (
 	"net"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wire"
 	wirenet "perun.network/go-perun/wire/net"
 )
 
+// transformTimeout bounds the transform of an accepted connection, so that
+// peers cannot stall handshakes indefinitely.
+const transformTimeout = 10 * time.Second
+
 // Listener is a TCP Listener.
 type Listener struct {
 	net.Listener
+	transform Transform // Applied to accepted connections, may be nil.
 }
 
 var _ wirenet.Listener = (*Listener)(nil)
@@ -50,11 +59,105 @@ func NewUnixListener(address string) (*Listener, error) {
 }
 
 // Accept implements peer.Dialer.Accept().
+//
+// If a transform is set, it is not applied by Accept, but on the first Send or
+// Recv call on the returned connection. This way, a failing or stalling
+// transform only affects its own connection: its error is returned by Send or
+// Recv, which closes the connection, while Accept keeps accepting new ones.
 func (l *Listener) Accept() (wirenet.Conn, error) {
 	conn, err := l.Listener.Accept()
 	if err != nil {
 		return nil, errors.Wrap(err, "accept failed")
 	}
+	if l.transform == nil {
+		return wirenet.NewIoConn(conn), nil
+	}
+
+	return &lazyConn{raw: conn, transform: l.transform}, nil
+}
+
+// SetTransform sets the transform that is applied to every accepted
+// connection. The transform runs when the connection is first used and is
+// aborted after a timeout, see Accept. Use ChainTransforms to apply multiple
+// transforms. Passing nil removes the transform. Should only be called before
+// the Listener is used.
+func (l *Listener) SetTransform(t Transform) {
+	l.transform = t
+}
+
+// lazyConn is an accepted connection whose transform is applied on first use.
+type lazyConn struct {
+	raw       net.Conn
+	transform Transform
+
+	once sync.Once
+	err  error // Set if the transform failed.
+
+	mu     sync.Mutex   // Protects conn and closed.
+	conn   wirenet.Conn // The transformed connection, once it is set up.
+	closed bool
+}
+
+// Send applies the transform, if not done yet, and sends e.
+func (c *lazyConn) Send(e *wire.Envelope) error {
+	conn, err := c.wrapped()
+	if err != nil {
+		return err
+	}
+	return conn.Send(e)
+}
+
+// Recv applies the transform, if not done yet, and receives an envelope.
+func (c *lazyConn) Recv() (*wire.Envelope, error) {
+	conn, err := c.wrapped()
+	if err != nil {
+		return nil, err
+	}
+	return conn.Recv()
+}
+
+// Close closes the connection and aborts a running transform.
+func (c *lazyConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errors.New("connection already closed")
+	}
+	c.closed = true
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return c.raw.Close()
+}
+
+// wrapped applies the transform exactly once, bounded by transformTimeout. On
+// failure, the connection is closed.
+func (c *lazyConn) wrapped() (wirenet.Conn, error) {
+	c.once.Do(func() {
+		if err := c.raw.SetDeadline(time.Now().Add(transformTimeout)); err != nil {
+			c.raw.Close() // nolint:errcheck,gosec
+			c.err = errors.Wrap(err, "setting deadline")
+			return
+		}
+		conn, err := wrapConn(c.transform, c.raw)
+		if err != nil {
+			c.err = err
+			return
+		}
+		if err := c.raw.SetDeadline(time.Time{}); err != nil {
+			conn.Close() // nolint:errcheck,gosec
+			c.err = errors.Wrap(err, "resetting deadline")
+			return
+		}
 
-	return wirenet.NewIoConn(conn), nil
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.closed {
+			conn.Close() // nolint:errcheck,gosec
+			c.err = errors.New("connection closed during transform")
+			return
+		}
+		c.conn = wirenet.NewIoConn(conn)
+	})
+	return c.conn, c.err
 }
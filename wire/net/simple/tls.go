@@ -0,0 +1,54 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simple
+
+import (
+	"crypto/tls"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// TLSClient returns a Transform that secures dialed connections with TLS
+// according to cfg. The server's certificate is verified against cfg.RootCAs
+// and cfg.ServerName, which has to be set because the transform does not know
+// the dialed host. For mutual TLS, cfg must contain a client certificate.
+func TLSClient(cfg *tls.Config) Transform {
+	return TransformFunc(func(conn net.Conn) (net.Conn, error) {
+		return tlsHandshake(tls.Client(conn, cfg))
+	})
+}
+
+// TLSServer returns a Transform that secures accepted connections with TLS
+// according to cfg. It requires mutual TLS: clients must present a
+// certificate, which is verified against cfg.ClientCAs, regardless of
+// cfg.ClientAuth. Like all transforms of a Listener, the handshake is bounded
+// by the Listener.
+func TLSServer(cfg *tls.Config) Transform {
+	cfg = cfg.Clone()
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return TransformFunc(func(conn net.Conn) (net.Conn, error) {
+		return tlsHandshake(tls.Server(conn, cfg))
+	})
+}
+
+// tlsHandshake runs the handshake of conn, so that a failing handshake fails
+// the transform instead of the first read or write.
+func tlsHandshake(conn *tls.Conn) (net.Conn, error) {
+	if err := conn.Handshake(); err != nil {
+		return nil, errors.Wrap(err, "TLS handshake")
+	}
+	return conn, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simple
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	simwallet "perun.network/go-perun/backend/sim/wallet"
+	"perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wire"
+)
+
+// testCA is a certificate authority that issues certificates for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// issue issues a certificate for 127.0.0.1 with the given extended key usage.
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestTLS_DialAccept(t *testing.T) {
+	const timeout = time.Second
+	rng := test.Prng(t)
+	lhost := "127.0.0.1:7359"
+	laddr := simwallet.NewRandomAddress(rng)
+
+	ca := newTestCA(t)
+	serverCert := ca.issue(t, 2, x509.ExtKeyUsageServerAuth)
+	clientCert := ca.issue(t, 3, x509.ExtKeyUsageClientAuth)
+
+	l, err := NewTCPListener(lhost)
+	require.NoError(t, err)
+	defer l.Close()
+	l.SetTransform(TLSServer(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    ca.pool,
+	}))
+
+	d := NewTCPDialer(timeout)
+	d.Register(laddr, lhost)
+	defer d.Close()
+
+	e := &wire.Envelope{
+		Sender:    simwallet.NewRandomAddress(rng),
+		Recipient: laddr,
+		Msg:       wire.NewPingMsg(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	t.Run("mutual", func(t *testing.T) {
+		d.SetTransform(TLSClient(&tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			RootCAs:      ca.pool,
+			ServerName:   "127.0.0.1",
+		}))
+
+		received := make(chan *wire.Envelope, 1)
+		go func() {
+			conn, err := l.Accept()
+			require.NoError(t, err)
+			re, err := conn.Recv()
+			assert.NoError(t, err)
+			received <- re
+		}()
+
+		conn, err := d.Dial(ctx, laddr)
+		require.NoError(t, err)
+		require.NoError(t, conn.Send(e))
+		assert.Equal(t, e, <-received)
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		d.SetTransform(TLSClient(&tls.Config{
+			RootCAs:    ca.pool,
+			ServerName: "127.0.0.1",
+		}))
+
+		rejected := make(chan error, 1)
+		go func() {
+			conn, err := l.Accept()
+			require.NoError(t, err)
+			_, err = conn.Recv()
+			rejected <- err
+		}()
+
+		// Depending on the TLS version, the client only notices the rejection
+		// on its first read, so only the server side is checked reliably.
+		conn, err := d.Dial(ctx, laddr)
+		if err == nil {
+			conn.Close()
+		}
+		assert.Error(t, <-rejected)
+
+		// The listener still accepts clients with a valid certificate.
+		d.SetTransform(TLSClient(&tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			RootCAs:      ca.pool,
+			ServerName:   "127.0.0.1",
+		}))
+		received := make(chan *wire.Envelope, 1)
+		go func() {
+			conn, err := l.Accept()
+			require.NoError(t, err)
+			re, err := conn.Recv()
+			assert.NoError(t, err)
+			received <- re
+		}()
+
+		conn, err = d.Dial(ctx, laddr)
+		require.NoError(t, err)
+		require.NoError(t, conn.Send(e))
+		assert.Equal(t, e, <-received)
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		d.SetTransform(TLSClient(&tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			RootCAs:      x509.NewCertPool(),
+			ServerName:   "127.0.0.1",
+		}))
+		go func() {
+			conn, err := l.Accept()
+			require.NoError(t, err)
+			conn.Recv() // nolint:errcheck
+		}()
+
+		conn, err := d.Dial(ctx, laddr)
+		assert.Error(t, err)
+		assert.Nil(t, conn)
+	})
+}
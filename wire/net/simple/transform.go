@@ -0,0 +1,77 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simple
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Transform wraps a raw network connection before the envelope stream is
+	// framed on top of it, e.g., to compress or encrypt the data or to run a
+	// handshake. A Transform that runs a handshake must be set up for the
+	// correct side, i.e., the Dialer's transform for the client and the
+	// Listener's transform for the server side.
+	Transform interface {
+		Wrap(net.Conn) (net.Conn, error)
+	}
+
+	// TransformFunc is a function that implements Transform.
+	TransformFunc func(net.Conn) (net.Conn, error)
+
+	// transforms is a pipeline of transforms.
+	transforms []Transform
+)
+
+// Wrap calls f(conn).
+func (f TransformFunc) Wrap(conn net.Conn) (net.Conn, error) {
+	return f(conn)
+}
+
+// ChainTransforms composes the given transforms into one Transform. The first
+// transform wraps the raw connection, each following one wraps the result of
+// its predecessor. For example, to compress data before it is encrypted, the
+// encryption transform must come first.
+func ChainTransforms(ts ...Transform) Transform {
+	return transforms(ts)
+}
+
+// Wrap applies all transforms in order.
+func (ts transforms) Wrap(conn net.Conn) (net.Conn, error) {
+	for i, t := range ts {
+		wrapped, err := t.Wrap(conn)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "applying transform %d", i)
+		}
+		conn = wrapped
+	}
+	return conn, nil
+}
+
+// wrapConn applies the transform t, if set, to conn. On failure, conn is
+// closed.
+func wrapConn(t Transform, conn net.Conn) (net.Conn, error) {
+	if t == nil {
+		return conn, nil
+	}
+	wrapped, err := t.Wrap(conn)
+	if err != nil {
+		conn.Close() // nolint:errcheck,gosec
+		return nil, errors.WithMessage(err, "transforming connection")
+	}
+	return wrapped, nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simple
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	simwallet "perun.network/go-perun/backend/sim/wallet"
+	"perun.network/go-perun/pkg/test"
+	"perun.network/go-perun/wire"
+)
+
+// xorConn flips all bits of the data read from and written to a connection.
+type xorConn struct{ net.Conn }
+
+func (c xorConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	for i := range b[:n] {
+		b[i] ^= 0xff
+	}
+	return n, err
+}
+
+func (c xorConn) Write(b []byte) (int, error) {
+	x := make([]byte, len(b))
+	for i := range b {
+		x[i] = b[i] ^ 0xff
+	}
+	return c.Conn.Write(x)
+}
+
+var xorTransform = TransformFunc(func(conn net.Conn) (net.Conn, error) {
+	return xorConn{conn}, nil
+})
+
+func TestChainTransforms(t *testing.T) {
+	var order []int
+	step := func(i int) Transform {
+		return TransformFunc(func(conn net.Conn) (net.Conn, error) {
+			order = append(order, i)
+			return conn, nil
+		})
+	}
+	_, err := ChainTransforms(step(0), step(1), step(2)).Wrap(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, order)
+
+	fail := TransformFunc(func(net.Conn) (net.Conn, error) { return nil, errors.New("fail") })
+	_, err = ChainTransforms(step(3), fail, step(4)).Wrap(nil)
+	assert.Error(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, order, "should stop at failing transform")
+}
+
+func TestTransform_DialAccept(t *testing.T) {
+	const timeout = time.Second
+	rng := test.Prng(t)
+	lhost := "127.0.0.1:7358"
+	laddr := simwallet.NewRandomAddress(rng)
+
+	l, err := NewTCPListener(lhost)
+	require.NoError(t, err)
+	defer l.Close()
+
+	d := NewTCPDialer(timeout)
+	d.Register(laddr, lhost)
+	defer d.Close()
+
+	e := &wire.Envelope{
+		Sender:    simwallet.NewRandomAddress(rng),
+		Recipient: laddr,
+		Msg:       wire.NewPingMsg(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	t.Run("matching", func(t *testing.T) {
+		l.SetTransform(ChainTransforms(xorTransform, xorTransform, xorTransform))
+		d.SetTransform(xorTransform)
+
+		received := make(chan *wire.Envelope, 1)
+		go func() {
+			conn, err := l.Accept()
+			require.NoError(t, err)
+			re, err := conn.Recv()
+			assert.NoError(t, err)
+			received <- re
+		}()
+
+		conn, err := d.Dial(ctx, laddr)
+		require.NoError(t, err)
+		require.NoError(t, conn.Send(e))
+		assert.Equal(t, e, <-received)
+	})
+
+	t.Run("mismatching", func(t *testing.T) {
+		l.SetTransform(nil)
+		d.SetTransform(xorTransform)
+
+		received := make(chan error, 1)
+		go func() {
+			conn, err := l.Accept()
+			require.NoError(t, err)
+			_, err = conn.Recv()
+			received <- err
+		}()
+
+		conn, err := d.Dial(ctx, laddr)
+		require.NoError(t, err)
+		require.NoError(t, conn.Send(e))
+		assert.Error(t, <-received, "untransformed data should not decode")
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		d.SetTransform(TransformFunc(func(net.Conn) (net.Conn, error) {
+			return nil, errors.New("handshake failed")
+		}))
+		go l.Accept() // nolint:errcheck
+
+		conn, err := d.Dial(ctx, laddr)
+		assert.Error(t, err)
+		assert.Nil(t, conn)
+	})
+}
+
+func TestListener_TransformFailure(t *testing.T) {
+	const timeout = time.Second
+	rng := test.Prng(t)
+	lhost := "127.0.0.1:7360"
+	laddr := simwallet.NewRandomAddress(rng)
+
+	l, err := NewTCPListener(lhost)
+	require.NoError(t, err)
+	defer l.Close()
+
+	stalling, stall := make(chan struct{}), make(chan struct{})
+	defer close(stall)
+	var calls int32
+	l.SetTransform(TransformFunc(func(conn net.Conn) (net.Conn, error) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			return nil, errors.New("transform failed")
+		case 2:
+			close(stalling)
+			<-stall
+			return nil, errors.New("transform stalled")
+		}
+		return conn, nil
+	}))
+
+	d := NewTCPDialer(timeout)
+	d.Register(laddr, lhost)
+	defer d.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// The first connection fails its transform.
+	_, err = d.Dial(ctx, laddr)
+	require.NoError(t, err)
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	_, err = conn.Recv()
+	assert.Error(t, err)
+
+	// The second connection stalls in its transform.
+	_, err = d.Dial(ctx, laddr)
+	require.NoError(t, err)
+	conn, err = l.Accept()
+	require.NoError(t, err)
+	go conn.Recv() // nolint:errcheck
+	<-stalling
+
+	// A good connection is still accepted and usable.
+	dconn, err := d.Dial(ctx, laddr)
+	require.NoError(t, err)
+	conn, err = l.Accept()
+	require.NoError(t, err)
+	e := &wire.Envelope{
+		Sender:    simwallet.NewRandomAddress(rng),
+		Recipient: laddr,
+		Msg:       wire.NewPingMsg(),
+	}
+	require.NoError(t, dconn.Send(e))
+	re, err := conn.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, e, re)
+}
@@ -0,0 +1,35 @@
+// Copyright 2021 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"io"
+
+	iotest "perun.network/go-perun/pkg/io/test"
+	"perun.network/go-perun/wire"
+)
+
+// NewMsgVector creates a conformance test vector with the given name from the
+// encoding of msg as produced by wire.Encode, i.e., the message type followed
+// by the payload.
+func NewMsgVector(name string, msg wire.Msg) (iotest.Vector, error) {
+	return iotest.NewVector(name, msgEncoder{msg})
+}
+
+type msgEncoder struct{ wire.Msg }
+
+func (m msgEncoder) Encode(w io.Writer) error {
+	return wire.Encode(m.Msg, w)
+}